@@ -1,10 +1,23 @@
 package main
 
 import (
+	"context"
+	"log/slog"
+	"os"
+
 	"txn-processor/server"
 )
 
 func main() {
 	app := server.New()
+
+	if len(os.Args) > 1 {
+		if err := app.Exec(context.Background(), os.Args[1:]); err != nil {
+			slog.Error("Command failed", "command", os.Args[1], "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	app.Start()
 }
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+	"txn-processor/config"
+	"txn-processor/internal/adapter/outbound/gorm/dao"
+	"txn-processor/internal/adapter/outbound/logger"
+	"txn-processor/internal/adapter/outbound/redispubsub"
+	"txn-processor/internal/adapter/outbound/redisratelimit"
+	"txn-processor/internal/adapter/outbound/webhook"
+	"txn-processor/internal/core/model"
+	"txn-processor/internal/core/service"
+	"txn-processor/pkg/audit"
+	"txn-processor/pkg/auth"
+	"txn-processor/pkg/client"
+	"txn-processor/pkg/tracing"
+
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// backend is what the commands need from a deployment. The method set is the services' own, so
+// the offline backend is the services themselves.
+type backend interface {
+	CreateAccount(ctx context.Context, req model.AccountCreateRequest) (*model.AccountCreateResponse, error)
+	GetAccount(ctx context.Context, id int64) (*model.AccountGetResponse, error)
+	ListAccounts(ctx context.Context, afterID int64, limit int) (*model.AccountListResponse, error)
+	SetAccountFrozen(ctx context.Context, id int64, frozen bool) (*model.AccountFreezeResponse, error)
+	ProcessTransfer(ctx context.Context, req model.TransferRequest) (*model.TransferResponse, error)
+	GetTransfer(ctx context.Context, id string) (*model.TransferStatusResponse, error)
+	ReverseTransfer(ctx context.Context, id int64) (*model.TransferResponse, error)
+	Reconcile(ctx context.Context) (*model.ReconcileReport, error)
+	ListEvents(ctx context.Context, afterID int64, limit int) (*model.EventListResponse, error)
+	Import(ctx context.Context, req model.ImportRequest, body io.Reader) (*model.ImportJob, error)
+	GetImport(ctx context.Context, id string) (*model.ImportJob, error)
+	ListImportResults(ctx context.Context, id string, afterRow int64, limit int) (*model.ImportResultList, error)
+	Close(ctx context.Context) error
+}
+
+const requestTimeout = 30 * time.Second
+
+// remote calls a server through the SDK, with the caller's credentials.
+type remote struct {
+	*client.Client
+}
+
+func newRemote(server, apiKey, token string) *remote {
+	opts := []client.Option{
+		client.WithHTTPClient(&http.Client{Timeout: requestTimeout}),
+		client.WithUserAgent("txnctl"),
+	}
+	if apiKey != "" {
+		opts = append(opts, client.WithAPIKey(apiKey))
+	}
+	if token != "" {
+		opts = append(opts, client.WithBearerToken(token))
+	}
+	return &remote{Client: client.New(server, opts...)}
+}
+
+func (r *remote) SetAccountFrozen(ctx context.Context, id int64, frozen bool) (*model.AccountFreezeResponse, error) {
+	if frozen {
+		return r.FreezeAccount(ctx, id)
+	}
+	return r.UnfreezeAccount(ctx, id)
+}
+
+func (r *remote) ProcessTransfer(ctx context.Context, req model.TransferRequest) (*model.TransferResponse, error) {
+	return r.CreateTransfer(ctx, req)
+}
+
+func (r *remote) Import(ctx context.Context, req model.ImportRequest, body io.Reader) (*model.ImportJob, error) {
+	return r.Client.Import(ctx, req.JobID, req.Kind, req.Format, body)
+}
+
+func (r *remote) Close(context.Context) error { return nil }
+
+// offline runs the services in-process against the database and cache configured by the usual
+// environment, without a server. Background work such as the outbox relay is left to the
+// servers; events written here are published by them.
+type offline struct {
+	*service.Service
+	dao *dao.Dao
+}
+
+func newOffline(ctx context.Context) (*offline, error) {
+	cfg := config.New()
+	// GORM logs to stdout, which carries the command's output.
+	cfg.DB.LogLevel = int(gormlogger.Silent)
+
+	tracer := tracing.NewBlankTracer()
+	outbound, err := dao.New(ctx, cfg.DB, cfg.Cache, tracer)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := dao.GetConnections()
+	if err != nil {
+		return nil, err
+	}
+
+	rdb := conn.Redis()
+	svc := service.New(
+		outbound,
+		logger.NewPublisher(),
+		webhook.NewSender(time.Duration(cfg.Webhook.TimeoutMs)*time.Millisecond),
+		redispubsub.NewBroker(rdb, cfg.Realtime),
+		redisratelimit.NewLimiter(rdb),
+		tracer,
+		cfg,
+	)
+	return &offline{Service: svc, dao: outbound}, nil
+}
+
+func (o *offline) Close(ctx context.Context) error {
+	err := errors.Join(o.Shutdown(ctx), o.dao.Shutdown(ctx))
+	if conn, cerr := dao.GetConnections(); cerr == nil {
+		err = errors.Join(err, conn.Close(ctx))
+	}
+	return err
+}
+
+// operatorContext runs offline commands as an admin the audit log records as "txnctl".
+func operatorContext(ctx context.Context) context.Context {
+	ctx = auth.WithPrincipal(ctx, auth.Principal{Subject: "system:txnctl", Method: auth.MethodSystem, Scopes: []string{auth.ScopeAdmin}})
+	return audit.WithActor(ctx, audit.System("txnctl"))
+}
@@ -0,0 +1,476 @@
+// Command txnctl is the operators' tool for a txn-processor deployment: it creates, inspects,
+// freezes and lists accounts, posts and reverses transfers, imports both in bulk from CSV or
+// NDJSON files, reconciles balances against the change log and tails the event log.
+//
+// By default it calls the server at -server with an admin API key or token. With -offline it
+// runs the same services in-process against the database and cache configured by the usual
+// DB_* and CACHE_* variables, for when the API is down.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+	"txn-processor/internal/core/model"
+	"txn-processor/pkg/bulk"
+
+	"github.com/google/uuid"
+)
+
+const usage = `usage: txnctl [flags] <command> [args]
+
+commands:
+  account create [-owner subject] <id> <initial-balance>
+  account get <id>
+  account list [-after id] [-limit n] [-all]
+  account freeze <id>
+  account unfreeze <id>
+  transfer create [-request-id id] <from> <to> <amount>
+  transfer get <transaction-id | request-id>
+  transfer reverse <transaction-id>
+  import accounts|transfers [-job id] [-format csv|ndjson] [-results file] <file>
+  import status <job>
+  reconcile
+  events tail [-after id] [-limit n] [-follow] [-interval d]
+
+flags:
+`
+
+// errUsage marks errors that come with the usage text and exit status 2.
+var errUsage = errors.New("usage")
+
+// errUnbalanced fails reconcile after its report is printed, so scripts can alert on it.
+var errUnbalanced = errors.New("balances do not match the change log")
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	code := run(ctx, os.Args[1:], os.Stdout, os.Stderr)
+	stop()
+	os.Exit(code)
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("txnctl", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		fs.PrintDefaults()
+	}
+	server := fs.String("server", envOr("TXNCTL_SERVER", "http://localhost:9999"), "server URL (TXNCTL_SERVER)")
+	apiKey := fs.String("api-key", os.Getenv("TXNCTL_API_KEY"), "API key (TXNCTL_API_KEY)")
+	token := fs.String("token", os.Getenv("TXNCTL_TOKEN"), "bearer token (TXNCTL_TOKEN)")
+	offlineMode := fs.Bool("offline", false, "use the database directly instead of a server")
+	format := fs.String("o", outputTable, "output format: table or json")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	out, err := newPrinter(stdout, *format)
+	if err != nil {
+		fmt.Fprintln(stderr, "txnctl:", err)
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	var b backend
+	if *offlineMode {
+		o, err := newOffline(ctx)
+		if err != nil {
+			fmt.Fprintln(stderr, "txnctl: offline:", err)
+			return 1
+		}
+		ctx = operatorContext(ctx)
+		b = o
+	} else {
+		b = newRemote(*server, *apiKey, *token)
+	}
+	defer func() {
+		closeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+		defer cancel()
+		if err := b.Close(closeCtx); err != nil {
+			fmt.Fprintln(stderr, "txnctl: close:", err)
+		}
+	}()
+
+	err = dispatch(ctx, b, out, fs.Args())
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, errUsage):
+		fmt.Fprintln(stderr, err)
+		return 2
+	default:
+		fmt.Fprintln(stderr, "txnctl:", err)
+		return 1
+	}
+}
+
+func dispatch(ctx context.Context, b backend, out *printer, args []string) error {
+	cmd := args[0]
+	if len(args) > 1 && (cmd == "account" || cmd == "transfer" || cmd == "import" || cmd == "events") {
+		cmd += " " + args[1]
+		args = args[1:]
+	}
+	args = args[1:]
+
+	switch cmd {
+	case "account create":
+		return createAccount(ctx, b, out, args)
+	case "account get":
+		return getAccount(ctx, b, out, args)
+	case "account list":
+		return listAccounts(ctx, b, out, args)
+	case "account freeze":
+		return setFrozen(ctx, b, out, args, true)
+	case "account unfreeze":
+		return setFrozen(ctx, b, out, args, false)
+	case "transfer create":
+		return createTransfer(ctx, b, out, args)
+	case "transfer get":
+		return getTransfer(ctx, b, out, args)
+	case "transfer reverse":
+		return reverseTransfer(ctx, b, out, args)
+	case "import accounts":
+		return runImport(ctx, b, out, args, model.ImportAccounts)
+	case "import transfers":
+		return runImport(ctx, b, out, args, model.ImportTransfers)
+	case "import status":
+		return importStatus(ctx, b, out, args)
+	case "reconcile":
+		return reconcile(ctx, b, out, args)
+	case "events tail":
+		return tailEvents(ctx, b, out, args)
+	default:
+		return fmt.Errorf("%w: unknown command %q, run txnctl -h", errUsage, cmd)
+	}
+}
+
+func createAccount(ctx context.Context, b backend, out *printer, args []string) error {
+	fs := subcommand("account create")
+	owner := fs.String("owner", "", "subject that owns the account")
+	if err := fs.Parse(args); err != nil || fs.NArg() != 2 {
+		return fmt.Errorf("%w: account create [-owner subject] <id> <initial-balance>", errUsage)
+	}
+	accountID, err := parseID(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	res, err := b.CreateAccount(ctx, model.AccountCreateRequest{AccountID: accountID, InitialBalance: fs.Arg(1), Owner: *owner})
+	if err != nil {
+		return err
+	}
+	return out.print(res)
+}
+
+func getAccount(ctx context.Context, b backend, out *printer, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%w: account get <id>", errUsage)
+	}
+	accountID, err := parseID(args[0])
+	if err != nil {
+		return err
+	}
+
+	res, err := b.GetAccount(ctx, accountID)
+	if err != nil {
+		return err
+	}
+	return out.print(res)
+}
+
+// listAccounts prints one page, or with -all every page as one list.
+func listAccounts(ctx context.Context, b backend, out *printer, args []string) error {
+	fs := subcommand("account list")
+	after := fs.Int64("after", 0, "list accounts with an ID above this one")
+	limit := fs.Int("limit", 0, "page size; 0 uses the server default")
+	all := fs.Bool("all", false, "follow next_after_id through every page")
+	if err := fs.Parse(args); err != nil || fs.NArg() != 0 {
+		return fmt.Errorf("%w: account list [-after id] [-limit n] [-all]", errUsage)
+	}
+
+	res, err := b.ListAccounts(ctx, *after, *limit)
+	if err != nil {
+		return err
+	}
+	for next := res.NextAfterID; *all && next > 0; {
+		page, err := b.ListAccounts(ctx, next, *limit)
+		if err != nil {
+			return err
+		}
+		res.Accounts = append(res.Accounts, page.Accounts...)
+		next = page.NextAfterID
+		res.NextAfterID = next
+	}
+	return out.print(res)
+}
+
+func setFrozen(ctx context.Context, b backend, out *printer, args []string, frozen bool) error {
+	if len(args) != 1 {
+		if frozen {
+			return fmt.Errorf("%w: account freeze <id>", errUsage)
+		}
+		return fmt.Errorf("%w: account unfreeze <id>", errUsage)
+	}
+	accountID, err := parseID(args[0])
+	if err != nil {
+		return err
+	}
+
+	res, err := b.SetAccountFrozen(ctx, accountID, frozen)
+	if err != nil {
+		return err
+	}
+	return out.print(res)
+}
+
+// createTransfer always sends a request ID, so a command that timed out can be run again with
+// the printed or given ID without moving the money twice.
+func createTransfer(ctx context.Context, b backend, out *printer, args []string) error {
+	fs := subcommand("transfer create")
+	requestID := fs.String("request-id", "", "idempotency key; generated when empty")
+	if err := fs.Parse(args); err != nil || fs.NArg() != 3 {
+		return fmt.Errorf("%w: transfer create [-request-id id] <from> <to> <amount>", errUsage)
+	}
+	from, err := parseID(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	to, err := parseID(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	req := model.TransferRequest{SourceAccountID: from, DestinationAccountID: to, Amount: fs.Arg(2), RequestID: *requestID}
+	if req.RequestID == "" {
+		req.RequestID = uuid.NewString()
+	}
+	res, err := b.ProcessTransfer(ctx, req)
+	if err != nil {
+		return fmt.Errorf("request %s: %w", req.RequestID, err)
+	}
+	return out.print(res)
+}
+
+func getTransfer(ctx context.Context, b backend, out *printer, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%w: transfer get <transaction-id | request-id>", errUsage)
+	}
+
+	res, err := b.GetTransfer(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	return out.print(res)
+}
+
+func reverseTransfer(ctx context.Context, b backend, out *printer, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%w: transfer reverse <transaction-id>", errUsage)
+	}
+	transferID, err := parseID(args[0])
+	if err != nil {
+		return err
+	}
+
+	res, err := b.ReverseTransfer(ctx, transferID)
+	if err != nil {
+		return err
+	}
+	return out.print(res)
+}
+
+func reconcile(ctx context.Context, b backend, out *printer, args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("%w: reconcile", errUsage)
+	}
+
+	report, err := b.Reconcile(ctx)
+	if err != nil {
+		return err
+	}
+	if err := out.print(report); err != nil {
+		return err
+	}
+	if len(report.Mismatches) > 0 || report.Total != report.Issued {
+		return errUnbalanced
+	}
+	return nil
+}
+
+// tailEvents prints events after -after in commit order. With -follow it keeps polling for
+// new ones until interrupted.
+func tailEvents(ctx context.Context, b backend, out *printer, args []string) error {
+	fs := subcommand("events tail")
+	after := fs.Int64("after", 0, "print events with an ID above this one")
+	limit := fs.Int("limit", 0, "page size; 0 uses the server default")
+	follow := fs.Bool("follow", false, "keep polling for new events")
+	interval := fs.Duration("interval", 2*time.Second, "poll interval with -follow")
+	if err := fs.Parse(args); err != nil || fs.NArg() != 0 || *interval <= 0 {
+		return fmt.Errorf("%w: events tail [-after id] [-limit n] [-follow] [-interval d]", errUsage)
+	}
+
+	first := true
+	for {
+		page, err := b.ListEvents(ctx, *after, *limit)
+		if err != nil {
+			if *follow && ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		if len(page.Events) > 0 || first {
+			if err := out.events(page.Events, first); err != nil {
+				return err
+			}
+			first = false
+		}
+		if n := len(page.Events); n > 0 {
+			*after = page.Events[n-1].ID
+		}
+		if page.NextAfterID > 0 {
+			continue
+		}
+		if !*follow {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(*interval):
+		}
+	}
+}
+
+// runImport uploads a file to an import job, generating the job ID when none is given. The ID
+// comes with any error, so an import that was cut short can be resumed by running the command
+// again with -job; the rows already applied are skipped.
+func runImport(ctx context.Context, b backend, out *printer, args []string, kind string) error {
+	fs := subcommand("import " + kind)
+	jobID := fs.String("job", "", "import job ID; generated when empty")
+	format := fs.String("format", "", "csv or ndjson; taken from the file extension when empty")
+	resultsFile := fs.String("results", "", "write every row's result to this file, in the format of the input")
+	if err := fs.Parse(args); err != nil || fs.NArg() != 1 {
+		return fmt.Errorf("%w: import %s [-job id] [-format csv|ndjson] [-results file] <file>", errUsage, kind)
+	}
+	path := fs.Arg(0)
+	if *format == "" {
+		*format = formatOf(path)
+	}
+	if *format != bulk.CSV && *format != bulk.NDJSON {
+		return fmt.Errorf("%w: cannot tell the format of %s, pass -format csv or -format ndjson", errUsage, path)
+	}
+	if *jobID == "" {
+		*jobID = uuid.NewString()
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	job, err := b.Import(ctx, model.ImportRequest{JobID: *jobID, Kind: kind, Format: *format}, f)
+	if err != nil {
+		return fmt.Errorf("import job %s: %w", *jobID, err)
+	}
+	if *resultsFile != "" {
+		if err := writeImportResults(ctx, b, *jobID, *resultsFile, *format); err != nil {
+			return err
+		}
+	}
+	return out.print(job)
+}
+
+func importStatus(ctx context.Context, b backend, out *printer, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%w: import status <job>", errUsage)
+	}
+
+	res, err := b.GetImport(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	return out.print(res)
+}
+
+// writeImportResults writes every result of the job to path, page by page.
+func writeImportResults(ctx context.Context, b backend, jobID, path, format string) (err error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, f.Close())
+	}()
+
+	w, err := bulk.NewWriter(f, format, model.ImportResultColumns...)
+	if err != nil {
+		return err
+	}
+	for after := int64(0); ; {
+		page, err := b.ListImportResults(ctx, jobID, after, 0)
+		if err != nil {
+			return err
+		}
+		for _, r := range page.Results {
+			if err := w.Write(r.Values()...); err != nil {
+				return err
+			}
+		}
+		if page.NextAfterRow == 0 {
+			break
+		}
+		after = page.NextAfterRow
+	}
+	return w.Flush()
+}
+
+func formatOf(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return bulk.CSV
+	case ".ndjson", ".jsonl":
+		return bulk.NDJSON
+	default:
+		return ""
+	}
+}
+
+// subcommand returns a quiet flag set; a parse error is reported with the command's usage.
+func subcommand(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
+
+func parseID(s string) (int64, error) {
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || v <= 0 {
+		return 0, fmt.Errorf("invalid ID %q", s)
+	}
+	return v, nil
+}
+
+func envOr(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
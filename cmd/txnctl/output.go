@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"txn-processor/internal/core/model"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+// printer writes command results as aligned tables for people or as JSON for scripts.
+// Streams of events are written one JSON object per line.
+type printer struct {
+	w      io.Writer
+	format string
+}
+
+func newPrinter(w io.Writer, format string) (*printer, error) {
+	if format != outputTable && format != outputJSON {
+		return nil, fmt.Errorf("unknown output format %q, want %s or %s", format, outputTable, outputJSON)
+	}
+	return &printer{w: w, format: format}, nil
+}
+
+func (p *printer) print(v any) error {
+	if p.format == outputJSON {
+		enc := json.NewEncoder(p.w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+
+	switch v := v.(type) {
+	case *model.AccountCreateResponse:
+		return p.table([]string{"ACCOUNT"}, [][]string{{id(v.AccountID)}})
+	case *model.AccountGetResponse:
+		return p.table(accountHeader, [][]string{accountRow(*v)})
+	case *model.AccountListResponse:
+		rows := make([][]string, 0, len(v.Accounts))
+		for _, a := range v.Accounts {
+			rows = append(rows, accountRow(a))
+		}
+		return p.table(accountHeader, rows)
+	case *model.AccountFreezeResponse:
+		return p.table([]string{"ACCOUNT", "FROZEN"}, [][]string{{id(v.AccountID), strconv.FormatBool(v.Frozen)}})
+	case *model.TransferResponse:
+		return p.table(transferHeader, [][]string{transferRow(*v)})
+	case *model.TransferStatusResponse:
+		return p.transferStatus(*v)
+	case *model.ReconcileReport:
+		return p.reconcileReport(*v)
+	case *model.ImportJob:
+		return p.table([]string{"JOB", "KIND", "STATUS", "ROWS", "CREATED", "EXISTING", "FAILED"}, [][]string{
+			{v.ID, v.Kind, v.Status, id(v.Rows), id(v.Created), id(v.Existing), id(v.Failed)},
+		})
+	default:
+		return fmt.Errorf("no table format for %T", v)
+	}
+}
+
+// events writes one page of a tail; the table header comes with the first page only.
+func (p *printer) events(events []model.Event, first bool) error {
+	if p.format == outputJSON {
+		enc := json.NewEncoder(p.w)
+		for _, e := range events {
+			if err := enc.Encode(e); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var header []string
+	if first {
+		header = []string{"ID", "TYPE", "ACCOUNT", "CREATED", "PAYLOAD"}
+	}
+	rows := make([][]string, 0, len(events))
+	for _, e := range events {
+		rows = append(rows, []string{id(e.ID), e.Type, id(e.AccountID), e.CreatedAt.UTC().Format(time.RFC3339), string(e.Payload)})
+	}
+	return p.table(header, rows)
+}
+
+var (
+	accountHeader  = []string{"ACCOUNT", "BALANCE", "VERSION", "OWNER", "FROZEN"}
+	transferHeader = []string{"TRANSACTION", "REQUEST", "FROM", "TO", "AMOUNT", "CREATED"}
+)
+
+func accountRow(a model.AccountGetResponse) []string {
+	return []string{id(a.AccountID), a.Balance, id(a.Version), a.Owner, strconv.FormatBool(a.Frozen)}
+}
+
+func transferRow(t model.TransferResponse) []string {
+	return []string{id(t.TransactionID), t.RequestID, id(t.SourceAccountID), id(t.DestinationAccountID), t.Amount, t.CreatedAt.UTC().Format(time.RFC3339)}
+}
+
+func (p *printer) transferStatus(s model.TransferStatusResponse) error {
+	if err := p.table([]string{"TRANSFER", "STATUS", "FROM", "TO", "REASON"}, [][]string{
+		{s.TransferID, s.Status, id(s.SourceAccountID), id(s.DestinationAccountID), s.FailureReason},
+	}); err != nil {
+		return err
+	}
+	if s.Transfer == nil {
+		return nil
+	}
+	fmt.Fprintln(p.w)
+	return p.table(transferHeader, [][]string{transferRow(*s.Transfer)})
+}
+
+func (p *printer) reconcileReport(r model.ReconcileReport) error {
+	if err := p.table([]string{"ACCOUNTS", "TOTAL", "ISSUED", "MISMATCHES"}, [][]string{
+		{id(r.Accounts), r.Total, r.Issued, strconv.Itoa(len(r.Mismatches))},
+	}); err != nil {
+		return err
+	}
+	if len(r.Mismatches) == 0 {
+		return nil
+	}
+	rows := make([][]string, 0, len(r.Mismatches))
+	for _, m := range r.Mismatches {
+		rows = append(rows, []string{id(m.AccountID), m.Live, m.Ledger})
+	}
+	fmt.Fprintln(p.w)
+	return p.table([]string{"ACCOUNT", "LIVE", "LEDGER"}, rows)
+}
+
+func (p *printer) table(header []string, rows [][]string) error {
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	if header != nil {
+		fmt.Fprintln(tw, strings.Join(header, "\t"))
+	}
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+func id(v int64) string {
+	return strconv.FormatInt(v, 10)
+}
@@ -7,13 +7,25 @@ import (
 )
 
 type App struct {
-	Name     string `env:"APP_NAME" envDefault:"txn-processor"`
-	Port     string `env:"APP_PORT" envDefault:"9999"`
-	LogLevel int    `env:"APP_LOG_LEVEL" envDefault:"-4"`
-	Env      string `env:"APP_ENV" envDefault:"default"`
-	DB       DB
-	Cache    Cache
-	Otel     Otel
+	Name      string `env:"APP_NAME" envDefault:"txn-processor"`
+	Port      string `env:"APP_PORT" envDefault:"9999"`
+	LogLevel  int    `env:"APP_LOG_LEVEL" envDefault:"-4"`
+	Env       string `env:"APP_ENV" envDefault:"default"`
+	DB        DB
+	Cache     Cache
+	Async     Async
+	Import    Import
+	Outbox    Outbox
+	Stream    Stream
+	Webhook   Webhook
+	Realtime  Realtime
+	Changes   Changes
+	Chain     Chain
+	Receipt   Receipt
+	Auth      Auth
+	RateLimit RateLimit
+	GRPC      GRPC
+	Otel      Otel
 }
 
 type DB struct {
@@ -25,6 +37,12 @@ type DB struct {
 	LogLevel      int    `env:"DB_LOG_LEVEL" envDefault:"4"`
 	IsAutoMigrate bool   `env:"DB_IS_AUTO_MIGRATE" envDefault:"true"`
 
+	// LockStrategy is one of pessimistic, optimistic or conditional.
+	LockStrategy      string `env:"DB_LOCK_STRATEGY" envDefault:"pessimistic"`
+	OptimisticRetries int    `env:"DB_OPTIMISTIC_RETRIES" envDefault:"5"`
+
+	GroupCommit GroupCommit
+
 	Tuning struct {
 		MaxOpenConns       int `env:"DB_MAX_OPEN_CONNS" envDefault:"50"`
 		MaxIdleConns       int `env:"DB_MAX_IDLE_CONNS" envDefault:"25"`
@@ -32,6 +50,13 @@ type DB struct {
 	} `envPrefix:"DB_"`
 }
 
+// GroupCommit batches concurrent transfers into a single DB transaction.
+type GroupCommit struct {
+	IsEnabled bool `env:"DB_GROUP_COMMIT_ENABLED" envDefault:"false"`
+	WindowMs  int  `env:"DB_GROUP_COMMIT_WINDOW_MS" envDefault:"5"`
+	MaxBatch  int  `env:"DB_GROUP_COMMIT_MAX_BATCH" envDefault:"100"`
+}
+
 type Cache struct {
 	Host     string `env:"CACHE_HOST" envDefault:"localhost"`
 	Port     string `env:"CACHE_PORT" envDefault:"6379"`
@@ -48,6 +73,137 @@ type Cache struct {
 	} `envPrefix:"CACHE_"`
 }
 
+// Async sizes the worker pool behind POST /v1/transfers?mode=async.
+type Async struct {
+	Workers   int `env:"ASYNC_WORKERS" envDefault:"8"`
+	QueueSize int `env:"ASYNC_QUEUE_SIZE" envDefault:"1000"`
+}
+
+// Import sizes the chunks bulk imports are applied in; each chunk commits on its own, so a
+// broken upload resumes after the last one.
+type Import struct {
+	ChunkSize int `env:"IMPORT_CHUNK_SIZE" envDefault:"500"`
+}
+
+// Outbox controls the relay that publishes domain events.
+type Outbox struct {
+	IsEnabled      bool   `env:"OUTBOX_ENABLED" envDefault:"true"`
+	Publisher      string `env:"OUTBOX_PUBLISHER" envDefault:"log"` // log or redis
+	PollIntervalMs int    `env:"OUTBOX_POLL_INTERVAL_MS" envDefault:"500"`
+	BatchSize      int    `env:"OUTBOX_BATCH_SIZE" envDefault:"100"`
+}
+
+// Stream configures the Redis Streams used for domain events.
+type Stream struct {
+	EventsPrefix string `env:"STREAM_EVENTS_PREFIX" envDefault:"txn:events"`
+	Partitions   int    `env:"STREAM_PARTITIONS" envDefault:"8"`
+	MaxLen       int64  `env:"STREAM_MAXLEN" envDefault:"100000"`
+
+	Commands Commands
+}
+
+// Commands configures the inbound transfer command queue.
+type Commands struct {
+	IsEnabled     bool   `env:"STREAM_COMMANDS_ENABLED" envDefault:"false"`
+	Stream        string `env:"STREAM_COMMANDS" envDefault:"txn:commands"`
+	Group         string `env:"STREAM_COMMANDS_GROUP" envDefault:"txn-processor"`
+	ReplyStream   string `env:"STREAM_COMMANDS_REPLY" envDefault:"txn:replies"`
+	DeadStream    string `env:"STREAM_COMMANDS_DEAD" envDefault:"txn:commands:dead"`
+	MaxDeliveries int64  `env:"STREAM_COMMANDS_MAX_DELIVERIES" envDefault:"5"`
+	ClaimIdleSec  int    `env:"STREAM_COMMANDS_CLAIM_IDLE_SEC" envDefault:"30"`
+}
+
+// Webhook controls delivery of outbox events to subscribed partner endpoints.
+// Deliveries are fed by the outbox relay, so they also need OUTBOX_ENABLED.
+type Webhook struct {
+	IsEnabled      bool `env:"WEBHOOK_ENABLED" envDefault:"true"`
+	PollIntervalMs int  `env:"WEBHOOK_POLL_INTERVAL_MS" envDefault:"500"`
+	BatchSize      int  `env:"WEBHOOK_BATCH_SIZE" envDefault:"50"`
+	TimeoutMs      int  `env:"WEBHOOK_TIMEOUT_MS" envDefault:"5000"`
+
+	// A delivery is retried after RetryBaseMs * 2^(attempt-1), capped at RetryMaxSec.
+	MaxAttempts int `env:"WEBHOOK_MAX_ATTEMPTS" envDefault:"8"`
+	RetryBaseMs int `env:"WEBHOOK_RETRY_BASE_MS" envDefault:"1000"`
+	RetryMaxSec int `env:"WEBHOOK_RETRY_MAX_SEC" envDefault:"3600"`
+
+	// DisableAfter consecutive failed attempts disables the subscription.
+	DisableAfter int `env:"WEBHOOK_DISABLE_AFTER" envDefault:"20"`
+}
+
+// Realtime controls the balance streams. Updates are fed by the outbox relay, so they also
+// need OUTBOX_ENABLED.
+type Realtime struct {
+	IsEnabled  bool  `env:"REALTIME_ENABLED" envDefault:"true"`
+	History    int64 `env:"REALTIME_HISTORY" envDefault:"1000"`
+	BufferSize int   `env:"REALTIME_BUFFER_SIZE" envDefault:"64"`
+}
+
+// Changes controls the sequencer that numbers hot-account entries of the change feed.
+type Changes struct {
+	SequenceIntervalMs int `env:"CHANGES_SEQUENCE_INTERVAL_MS" envDefault:"200"`
+	SequenceBatch      int `env:"CHANGES_SEQUENCE_BATCH" envDefault:"100"`
+}
+
+// Chain controls the transfer hash chain and its signed checkpoints. A checkpoint is written
+// once CheckpointEvery transfers were linked since the last one, or CheckpointIntervalSec
+// passed with at least one. Without SigningKey, a base64 Ed25519 seed, no checkpoints are
+// written; PublicKey lets verify-chain check signatures on machines without the seed.
+type Chain struct {
+	IsEnabled             bool   `env:"CHAIN_ENABLED" envDefault:"true"`
+	IntervalMs            int    `env:"CHAIN_INTERVAL_MS" envDefault:"500"`
+	BatchSize             int    `env:"CHAIN_BATCH_SIZE" envDefault:"500"`
+	CheckpointEvery       int64  `env:"CHAIN_CHECKPOINT_EVERY" envDefault:"1000"`
+	CheckpointIntervalSec int    `env:"CHAIN_CHECKPOINT_INTERVAL_SEC" envDefault:"3600"`
+	SigningKey            string `env:"CHAIN_SIGNING_KEY"`
+	PublicKey             string `env:"CHAIN_PUBLIC_KEY"`
+	KeyID                 string `env:"CHAIN_KEY_ID" envDefault:"chain-1"`
+}
+
+// Receipt configures signed transfer receipts. SigningKeys maps key IDs to base64 Ed25519
+// seeds ("kid:seed,kid:seed") and ActiveKeyID picks the one that signs. The other signing keys
+// and the retired keys in PublicKeys ("kid:pubkey") stay in the JWKS, so receipts issued before
+// a rotation keep verifying.
+type Receipt struct {
+	IsEnabled   bool              `env:"RECEIPT_ENABLED" envDefault:"true"`
+	SigningKeys map[string]string `env:"RECEIPT_SIGNING_KEYS" envKeyValSeparator:":"`
+	ActiveKeyID string            `env:"RECEIPT_ACTIVE_KEY_ID"`
+	PublicKeys  map[string]string `env:"RECEIPT_PUBLIC_KEYS" envKeyValSeparator:":"`
+}
+
+// Auth controls API authentication. Callers present an API key, or a JWT signed by a key in
+// the JWKS document at JWKSFile; without JWKSFile only API keys are accepted. Issuer and
+// Audience are checked against the token when set. Partners may instead sign requests with a
+// shared secret from SigningKeys ("partner:secret,partner:secret"); signatures older or newer
+// than SigningMaxSkewSec are refused.
+type Auth struct {
+	IsEnabled         bool              `env:"AUTH_ENABLED" envDefault:"true"`
+	JWKSFile          string            `env:"AUTH_JWKS_FILE"`
+	Issuer            string            `env:"AUTH_JWT_ISSUER"`
+	Audience          string            `env:"AUTH_JWT_AUDIENCE"`
+	LeewaySec         int               `env:"AUTH_JWT_LEEWAY_SEC" envDefault:"30"`
+	SigningKeys       map[string]string `env:"AUTH_SIGNING_KEYS" envKeyValSeparator:":"`
+	SigningMaxSkewSec int               `env:"AUTH_SIGNING_MAX_SKEW_SEC" envDefault:"300"`
+}
+
+// RateLimit throttles the API per caller, counting in Redis so limits hold across replicas.
+// Limits are written "<requests>/<period>", e.g. "100/1s". Default applies to every route group
+// without an entry in Routes ("transfers:50/1s,webhooks:5/1s"); Account additionally limits
+// transfers out of any one source account, whoever sends them. Auth limits failed
+// authentications per client IP. "none" turns a limit off.
+type RateLimit struct {
+	IsEnabled bool              `env:"RATELIMIT_ENABLED" envDefault:"true"`
+	Default   string            `env:"RATELIMIT_DEFAULT" envDefault:"100/1s"`
+	Routes    map[string]string `env:"RATELIMIT_ROUTES" envKeyValSeparator:":" envDefault:"transfers:50/1s"`
+	Account   string            `env:"RATELIMIT_ACCOUNT" envDefault:"20/1s"`
+	Auth      string            `env:"RATELIMIT_AUTH" envDefault:"10/1m"`
+}
+
+// GRPC serves the txn.v1 gRPC API alongside HTTP.
+type GRPC struct {
+	IsEnabled bool   `env:"GRPC_ENABLED" envDefault:"true"`
+	Port      string `env:"GRPC_PORT" envDefault:"9090"`
+}
+
 type Otel struct {
 	Metrics Metrics
 	Tracer  Tracer
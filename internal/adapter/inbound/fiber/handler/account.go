@@ -32,6 +32,10 @@ func (h *AccountHandler) Create(c *fiber.Ctx) error {
 		switch {
 		case errors.Is(err, service.ErrValidation):
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, service.ErrUnauthenticated):
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, service.ErrForbidden):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
 		case errors.Is(err, service.ErrConflict):
 			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
 		default:
@@ -57,6 +61,10 @@ func (h *AccountHandler) Get(c *fiber.Ctx) error {
 		switch {
 		case errors.Is(err, service.ErrValidation):
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, service.ErrUnauthenticated):
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, service.ErrForbidden):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
 		case errors.Is(err, service.ErrNotFound):
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "account not found"})
 		default:
@@ -66,3 +74,178 @@ func (h *AccountHandler) Get(c *fiber.Ctx) error {
 
 	return c.Status(fiber.StatusOK).JSON(res)
 }
+
+func (h *AccountHandler) SetShards(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).
+			JSON(fiber.Map{"error": "invalid account id"})
+	}
+
+	var req model.AccountShardRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).
+			JSON(fiber.Map{"error": "invalid request"})
+	}
+
+	res, err := h.accountService.SetAccountShards(ctx, id, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrValidation):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, service.ErrUnauthenticated):
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, service.ErrForbidden):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, service.ErrNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "account not found"})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(res)
+}
+
+// Changes returns the account's change feed after ?since=<version>, oldest first.
+func (h *AccountHandler) Changes(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).
+			JSON(fiber.Map{"error": "invalid account id"})
+	}
+
+	var since int64
+	if v := c.Query("since"); v != "" {
+		if since, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return c.Status(fiber.StatusBadRequest).
+				JSON(fiber.Map{"error": "invalid since"})
+		}
+	}
+
+	res, err := h.accountService.GetAccountChanges(ctx, id, since, c.QueryInt("limit"))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrValidation):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, service.ErrUnauthenticated):
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, service.ErrForbidden):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, service.ErrNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "account not found"})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(res)
+}
+
+// Grant lets the subject in the path act on the account as a delegate.
+func (h *AccountHandler) Grant(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).
+			JSON(fiber.Map{"error": "invalid account id"})
+	}
+
+	if err := h.accountService.GrantAccount(c.UserContext(), id, c.Params("subject")); err != nil {
+		return grantError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *AccountHandler) Revoke(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).
+			JSON(fiber.Map{"error": "invalid account id"})
+	}
+
+	if err := h.accountService.RevokeAccountGrant(c.UserContext(), id, c.Params("subject")); err != nil {
+		return grantError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func grantError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, service.ErrValidation):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, service.ErrUnauthenticated):
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, service.ErrForbidden):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, service.ErrNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "grant not found"})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+}
+
+// List pages through all accounts in ID order. Pass next_after_id from a response as after_id
+// for the next page.
+func (h *AccountHandler) List(c *fiber.Ctx) error {
+	res, err := h.accountService.ListAccounts(c.UserContext(), int64(c.QueryInt("after_id")), c.QueryInt("limit"))
+	if err != nil {
+		return adminAccountError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(res)
+}
+
+// Freeze stops transfers from and to the account until Unfreeze.
+func (h *AccountHandler) Freeze(c *fiber.Ctx) error {
+	return h.setFrozen(c, true)
+}
+
+func (h *AccountHandler) Unfreeze(c *fiber.Ctx) error {
+	return h.setFrozen(c, false)
+}
+
+func (h *AccountHandler) setFrozen(c *fiber.Ctx, frozen bool) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).
+			JSON(fiber.Map{"error": "invalid account id"})
+	}
+
+	res, err := h.accountService.SetAccountFrozen(c.UserContext(), id, frozen)
+	if err != nil {
+		return adminAccountError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(res)
+}
+
+// Reconcile reports accounts whose balance differs from their change log.
+func (h *AccountHandler) Reconcile(c *fiber.Ctx) error {
+	res, err := h.accountService.Reconcile(c.UserContext())
+	if err != nil {
+		return adminAccountError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(res)
+}
+
+func adminAccountError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, service.ErrValidation):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, service.ErrUnauthenticated):
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, service.ErrForbidden):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, service.ErrNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "account not found"})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"errors"
+	"txn-processor/internal/core/model"
+	"txn-processor/internal/core/service"
+	"txn-processor/internal/port"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AuditHandler struct {
+	auditService port.AuditService
+}
+
+func NewAuditHandler(auditService port.AuditService) *AuditHandler {
+	return &AuditHandler{auditService: auditService}
+}
+
+// List returns audit records newest first, filtered by action, resource_type, resource_id and
+// actor. Pass next_before_id from a response as before_id for the next page.
+func (h *AuditHandler) List(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	filter := model.AuditFilter{
+		Action:       c.Query("action"),
+		ResourceType: c.Query("resource_type"),
+		ResourceID:   c.Query("resource_id"),
+		Actor:        c.Query("actor"),
+		BeforeID:     int64(c.QueryInt("before_id")),
+		Limit:        c.QueryInt("limit"),
+	}
+
+	res, err := h.auditService.ListAudit(ctx, filter)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrValidation):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, service.ErrUnauthenticated):
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, service.ErrForbidden):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(res)
+}
+
+// Events returns outbox events in commit order after ?after_id=. A tail polls with the ID of
+// the last event it received.
+func (h *AuditHandler) Events(c *fiber.Ctx) error {
+	res, err := h.auditService.ListEvents(c.UserContext(), int64(c.QueryInt("after_id")), c.QueryInt("limit"))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrValidation):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, service.ErrUnauthenticated):
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, service.ErrForbidden):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+		default:
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(res)
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"txn-processor/internal/core/model"
+	"txn-processor/internal/core/service"
+	"txn-processor/internal/port"
+	"txn-processor/pkg/bulk"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ImportHandler struct {
+	importService port.ImportService
+}
+
+func NewImportHandler(importService port.ImportService) *ImportHandler {
+	return &ImportHandler{importService: importService}
+}
+
+// Upload applies a CSV or NDJSON body of the kind given in the query to the import job :id and
+// answers with the job once the whole file went through. The body is read as it arrives, so
+// its size is not limited; uploading the same file again resumes a job that broke off.
+func (h *ImportHandler) Upload(c *fiber.Ctx) error {
+	format := bulk.FormatOf(c.Get(fiber.HeaderContentType))
+	if format == "" {
+		return c.Status(fiber.StatusUnsupportedMediaType).
+			JSON(fiber.Map{"error": "body must be text/csv or application/x-ndjson"})
+	}
+
+	body := c.Context().RequestBodyStream()
+	if body == nil {
+		body = bytes.NewReader(c.Request().Body())
+	}
+
+	job, err := h.importService.Import(c.UserContext(), model.ImportRequest{
+		JobID:  c.Params("id"),
+		Kind:   c.Query("kind"),
+		Format: format,
+	}, body)
+	if err != nil {
+		return importError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(job)
+}
+
+func (h *ImportHandler) Get(c *fiber.Ctx) error {
+	job, err := h.importService.GetImport(c.UserContext(), c.Params("id"))
+	if err != nil {
+		return importError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(job)
+}
+
+// Results returns a page of row outcomes after after_row, or, when the client accepts
+// text/csv or application/x-ndjson, every outcome as a file.
+func (h *ImportHandler) Results(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	id := c.Params("id")
+
+	var format string
+	switch c.Accepts(fiber.MIMEApplicationJSON, bulk.ContentType(bulk.CSV), bulk.ContentType(bulk.NDJSON)) {
+	case bulk.ContentType(bulk.CSV):
+		format = bulk.CSV
+	case bulk.ContentType(bulk.NDJSON):
+		format = bulk.NDJSON
+	}
+
+	if format == "" {
+		res, err := h.importService.ListImportResults(ctx, id, int64(c.QueryInt("after_row")), c.QueryInt("limit"))
+		if err != nil {
+			return importError(c, err)
+		}
+		return c.Status(fiber.StatusOK).JSON(res)
+	}
+
+	page, err := h.importService.ListImportResults(ctx, id, 0, 0)
+	if err != nil {
+		return importError(c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, bulk.ContentType(format))
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", id+"-results."+format))
+
+	// The file is written after the handler returned.
+	ctx = context.WithoutCancel(ctx)
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		out, err := bulk.NewWriter(w, format, model.ImportResultColumns...)
+		if err != nil {
+			return
+		}
+		for {
+			for _, r := range page.Results {
+				if err := out.Write(r.Values()...); err != nil {
+					return
+				}
+			}
+			if page.NextAfterRow == 0 {
+				break
+			}
+			if page, err = h.importService.ListImportResults(ctx, id, page.NextAfterRow, 0); err != nil {
+				// The status is sent already; the client sees a short file.
+				slog.ErrorContext(ctx, "import results cut short", "import", id, "error", err)
+				return
+			}
+		}
+		_ = out.Flush()
+	})
+	return nil
+}
+
+func importError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, service.ErrValidation):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, service.ErrUnauthenticated):
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, service.ErrForbidden):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, service.ErrNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "import not found"})
+	case errors.Is(err, service.ErrConflict):
+		return c.Status(fiber.StatusConflict).
+			JSON(fiber.Map{"error": "import job is of another kind or being uploaded already"})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+}
@@ -0,0 +1,17 @@
+package handler
+
+import "github.com/gofiber/fiber/v2"
+
+type OpenAPIHandler struct {
+	spec []byte
+}
+
+func NewOpenAPIHandler(spec []byte) *OpenAPIHandler {
+	return &OpenAPIHandler{spec: spec}
+}
+
+// Spec serves the OpenAPI document as embedded, byte for byte.
+func (h *OpenAPIHandler) Spec(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	return c.Status(fiber.StatusOK).Send(h.spec)
+}
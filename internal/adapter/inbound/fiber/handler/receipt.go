@@ -0,0 +1,23 @@
+package handler
+
+import (
+	"txn-processor/internal/port"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ReceiptHandler struct {
+	receiptService port.ReceiptService
+}
+
+func NewReceiptHandler(receiptService port.ReceiptService) *ReceiptHandler {
+	return &ReceiptHandler{receiptService: receiptService}
+}
+
+// JWKS publishes the public keys that verify transfer receipts, retired ones included.
+func (h *ReceiptHandler) JWKS(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	return c.Status(fiber.StatusOK).JSON(h.receiptService.ReceiptKeys(ctx), "application/jwk-set+json")
+}
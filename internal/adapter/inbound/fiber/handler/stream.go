@@ -0,0 +1,209 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+	"txn-processor/internal/core/service"
+	"txn-processor/internal/port"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+)
+
+const streamHeartbeat = 15 * time.Second
+
+type StreamHandler struct {
+	balanceService port.BalanceService
+}
+
+func NewStreamHandler(balanceService port.BalanceService) *StreamHandler {
+	return &StreamHandler{balanceService: balanceService}
+}
+
+// SSE streams balance updates as server-sent events. The event ID is the update's sequence
+// number, so a reconnecting EventSource resumes through Last-Event-ID automatically.
+func (h *StreamHandler) SSE(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).
+			JSON(fiber.Map{"error": "invalid account id"})
+	}
+
+	lastSeq, err := lastEventID(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).
+			JSON(fiber.Map{"error": "invalid Last-Event-ID"})
+	}
+
+	// The stream outlives the handler, so it gets its own context; values such as the
+	// trace span are kept.
+	ctx, cancel := context.WithCancel(context.WithoutCancel(c.UserContext()))
+
+	updates, err := h.balanceService.StreamBalance(ctx, id, lastSeq)
+	if err != nil {
+		cancel()
+		return streamError(c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer cancel()
+
+		ticker := time.NewTicker(streamHeartbeat)
+		defer ticker.Stop()
+
+		fmt.Fprint(w, "retry: 3000\n\n")
+		if err := w.Flush(); err != nil {
+			return
+		}
+
+		for {
+			select {
+			case u, ok := <-updates:
+				if !ok {
+					return
+				}
+				b, _ := json.Marshal(u)
+				fmt.Fprintf(w, "id: %d\nevent: balance\ndata: %s\n\n", u.Seq, b)
+			case <-ticker.C:
+				fmt.Fprint(w, ": ping\n\n")
+			}
+			// A failed flush means the client went away.
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	})
+
+	return nil
+}
+
+// Upgrade validates a WebSocket request before the handshake so errors are plain HTTP.
+func (h *StreamHandler) Upgrade(c *fiber.Ctx) error {
+	if !websocket.IsWebSocketUpgrade(c) {
+		return c.Status(fiber.StatusUpgradeRequired).
+			JSON(fiber.Map{"error": "websocket upgrade required"})
+	}
+
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).
+			JSON(fiber.Map{"error": "invalid account id"})
+	}
+
+	lastSeq, err := lastEventID(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).
+			JSON(fiber.Map{"error": "invalid Last-Event-ID"})
+	}
+
+	c.Locals("account_id", id)
+	c.Locals("last_seq", lastSeq)
+	// The connection only keeps locals, so carry the request context (and its principal) over.
+	c.Locals("user_ctx", c.UserContext())
+	return c.Next()
+}
+
+// WebSocket sends each balance update as a JSON text message. Clients resume by passing the
+// last seq they saw as ?last_event_id=.
+func (h *StreamHandler) WebSocket() fiber.Handler {
+	return websocket.New(func(conn *websocket.Conn) {
+		id, _ := conn.Locals("account_id").(int64)
+		lastSeq, _ := conn.Locals("last_seq").(int64)
+		userCtx, ok := conn.Locals("user_ctx").(context.Context)
+		if !ok {
+			userCtx = context.Background()
+		}
+
+		ctx, cancel := context.WithCancel(context.WithoutCancel(userCtx))
+		defer cancel()
+
+		updates, err := h.balanceService.StreamBalance(ctx, id, lastSeq)
+		if err != nil {
+			msg := err.Error()
+			if errors.Is(err, service.ErrNotFound) {
+				msg = "account not found"
+			}
+			// Application close codes 4401 and 4403 mirror the HTTP statuses.
+			code := websocket.ClosePolicyViolation
+			switch {
+			case errors.Is(err, service.ErrUnauthenticated):
+				code = 4401
+			case errors.Is(err, service.ErrForbidden):
+				code = 4403
+			}
+			_ = conn.WriteJSON(fiber.Map{"error": msg})
+			_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(code, msg))
+			return
+		}
+
+		// Reading is only needed to notice the client closing the connection.
+		go func() {
+			defer cancel()
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}()
+
+		ticker := time.NewTicker(streamHeartbeat)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case u, ok := <-updates:
+				if !ok {
+					_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "resume with last_event_id"))
+					return
+				}
+				if err := conn.WriteJSON(u); err != nil {
+					return
+				}
+			case <-ticker.C:
+				if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(5*time.Second)); err != nil {
+					return
+				}
+			}
+		}
+	})
+}
+
+// lastEventID reads the resume point from the Last-Event-ID header or, for clients that cannot
+// set headers, the last_event_id query parameter.
+func lastEventID(c *fiber.Ctx) (int64, error) {
+	v := c.Get("Last-Event-ID")
+	if v == "" {
+		v = c.Query("last_event_id")
+	}
+	if v == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(v, 10, 64)
+}
+
+func streamError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, service.ErrValidation):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, service.ErrUnauthenticated):
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, service.ErrForbidden):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, service.ErrNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "account not found"})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+}
@@ -2,6 +2,8 @@ package handler
 
 import (
 	"errors"
+	"strconv"
+	"txn-processor/internal/adapter/inbound/fiber/middleware"
 	"txn-processor/internal/core/model"
 	"txn-processor/internal/core/service"
 	"txn-processor/internal/port"
@@ -26,17 +28,87 @@ func (h *TransferHandler) Create(c *fiber.Ctx) error {
 			JSON(fiber.Map{"error": "invalid request"})
 	}
 
+	if c.Query("mode") == "async" {
+		res, err := h.transferService.SubmitTransfer(ctx, req)
+		if err != nil {
+			return transferError(c, err)
+		}
+		return c.Status(fiber.StatusAccepted).JSON(res)
+	}
+
 	res, err := h.transferService.ProcessTransfer(ctx, req)
+	if err != nil {
+		return transferError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(res)
+}
+
+func (h *TransferHandler) Get(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	res, err := h.transferService.GetTransfer(ctx, c.Params("id"))
 	if err != nil {
 		switch {
 		case errors.Is(err, service.ErrValidation):
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, service.ErrUnauthenticated):
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, service.ErrForbidden):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
 		case errors.Is(err, service.ErrNotFound):
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "account not found"})
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "transfer not found"})
 		default:
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 		}
 	}
 
+	return c.Status(fiber.StatusOK).JSON(res)
+}
+
+// Reverse moves a committed transfer's amount back with a new transfer. Reversing it again
+// returns the same reversal.
+func (h *TransferHandler) Reverse(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).
+			JSON(fiber.Map{"error": "invalid transfer id"})
+	}
+
+	res, err := h.transferService.ReverseTransfer(c.UserContext(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "transfer not found"})
+		}
+		return transferError(c, err)
+	}
+
 	return c.Status(fiber.StatusCreated).JSON(res)
 }
+
+func transferError(c *fiber.Ctx, err error) error {
+	var limited *service.RateLimitError
+	if errors.As(err, &limited) {
+		return middleware.TooManyRequests(c, limited.Result)
+	}
+
+	switch {
+	case errors.Is(err, service.ErrValidation):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, service.ErrUnauthenticated):
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, service.ErrForbidden):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, service.ErrNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "account not found"})
+	case errors.Is(err, service.ErrInsufficientFunds):
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, service.ErrAccountFrozen), errors.Is(err, service.ErrConflict):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, service.ErrBusy):
+		c.Set(fiber.HeaderRetryAfter, "1")
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": err.Error()})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+}
@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+	"txn-processor/internal/core/model"
+	"txn-processor/internal/core/service"
+	"txn-processor/internal/port"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type WebhookHandler struct {
+	webhookService port.WebhookService
+}
+
+func NewWebhookHandler(webhookService port.WebhookService) *WebhookHandler {
+	return &WebhookHandler{webhookService: webhookService}
+}
+
+func (h *WebhookHandler) Create(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	var req model.WebhookCreateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).
+			JSON(fiber.Map{"error": "invalid request"})
+	}
+
+	res, err := h.webhookService.CreateWebhook(ctx, req)
+	if err != nil {
+		return webhookError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(res)
+}
+
+func (h *WebhookHandler) Get(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).
+			JSON(fiber.Map{"error": "invalid webhook id"})
+	}
+
+	res, err := h.webhookService.GetWebhook(ctx, id)
+	if err != nil {
+		return webhookError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(res)
+}
+
+func (h *WebhookHandler) Delete(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).
+			JSON(fiber.Map{"error": "invalid webhook id"})
+	}
+
+	if err := h.webhookService.DeleteWebhook(ctx, id); err != nil {
+		return webhookError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *WebhookHandler) Enable(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).
+			JSON(fiber.Map{"error": "invalid webhook id"})
+	}
+
+	res, err := h.webhookService.EnableWebhook(ctx, id)
+	if err != nil {
+		return webhookError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(res)
+}
+
+func (h *WebhookHandler) Deliveries(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).
+			JSON(fiber.Map{"error": "invalid webhook id"})
+	}
+
+	res, err := h.webhookService.ListDeliveries(ctx, id, c.QueryInt("limit"))
+	if err != nil {
+		return webhookError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(res)
+}
+
+// Replay re-sends every matching event since the given RFC 3339 timestamp.
+func (h *WebhookHandler) Replay(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).
+			JSON(fiber.Map{"error": "invalid webhook id"})
+	}
+
+	var req model.WebhookReplayRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).
+			JSON(fiber.Map{"error": "invalid request"})
+	}
+
+	res, err := h.webhookService.ReplayWebhook(ctx, id, req)
+	if err != nil {
+		return webhookError(c, err)
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(res)
+}
+
+func webhookError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, service.ErrValidation):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, service.ErrUnauthenticated):
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, service.ErrForbidden):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, service.ErrNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "webhook not found"})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+}
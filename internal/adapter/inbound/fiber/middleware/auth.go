@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"errors"
+	"strings"
+	"txn-processor/internal/core/model"
+	"txn-processor/internal/core/service"
+	"txn-processor/internal/port"
+	"txn-processor/pkg/audit"
+	"txn-processor/pkg/auth"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const APIKeyHeader = "X-API-Key"
+
+// Authenticate rejects requests without valid credentials and puts the caller into the user
+// context, both as the principal for the service layer and as the actor of audit records.
+// A client IP with too many failed attempts gets 429 until its limit refills.
+// It must run after RequestLogger, whose actor it refines. Requests VerifySignature already
+// authenticated pass straight through.
+func Authenticate(svc port.AuthService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx := c.UserContext()
+		if _, ok := auth.FromContext(ctx); ok {
+			return c.Next()
+		}
+
+		creds := model.Credentials{APIKey: c.Get(APIKeyHeader)}
+		if scheme, token, ok := strings.Cut(c.Get(fiber.HeaderAuthorization), " "); ok && strings.EqualFold(scheme, "Bearer") {
+			creds.BearerToken = strings.TrimSpace(token)
+		}
+
+		p, err := svc.Authenticate(ctx, creds)
+		if err != nil {
+			var limited *service.RateLimitError
+			if errors.As(err, &limited) {
+				return TooManyRequests(c, limited.Result)
+			}
+			if errors.Is(err, service.ErrUnauthenticated) {
+				c.Set(fiber.HeaderWWWAuthenticate, `Bearer realm="txn-processor"`)
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": service.ErrUnauthenticated.Error()})
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		if p != nil {
+			actor := audit.FromContext(ctx)
+			actor.ID = p.Subject
+			ctx = audit.WithActor(auth.WithPrincipal(ctx, *p), actor)
+			c.SetUserContext(ctx)
+		}
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"io"
+	"txn-processor/internal/core/model"
+	"txn-processor/pkg/reqsign"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// LimitBody reads request bodies of up to MaxBodyBytes into memory and refuses larger ones, so
+// that no middleware or handler calling Body buffers a stream of any size. Bodies of requests
+// for which streamed reports true are left as streams for the handler; a signed request never
+// is, as its signature covers the whole body.
+func LimitBody(streamed func(c *fiber.Ctx) bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		req := c.Request()
+		if !req.IsBodyStream() || (streamed(c) && c.Get(reqsign.Header) == "") {
+			return c.Next()
+		}
+
+		if req.Header.ContentLength() > MaxBodyBytes {
+			return tooLarge(c)
+		}
+		// Chunked bodies have no declared length; read one byte past the limit to find out.
+		body, err := io.ReadAll(io.LimitReader(req.BodyStream(), MaxBodyBytes+1))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Error: "invalid request"})
+		}
+		if len(body) > MaxBodyBytes {
+			return tooLarge(c)
+		}
+		req.SetBody(body)
+		return c.Next()
+	}
+}
+
+// tooLarge refuses the request and closes the connection, which still holds the unread body.
+func tooLarge(c *fiber.Ctx) error {
+	c.Context().SetConnectionClose()
+	return c.Status(fiber.StatusRequestEntityTooLarge).JSON(model.ErrorResponse{Error: "request body too large"})
+}
@@ -0,0 +1,79 @@
+package middleware_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"txn-processor/internal/adapter/inbound/fiber/middleware"
+	"txn-processor/pkg/reqsign"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// bodyApp streams every body over 1 KiB and answers with the length of the body its handler read.
+func bodyApp() *fiber.App {
+	app := fiber.New(fiber.Config{StreamRequestBody: true, BodyLimit: 1 << 10})
+	app.Use(middleware.LimitBody(func(c *fiber.Ctx) bool { return c.Path() == "/upload" }))
+	app.Post("/whole", func(c *fiber.Ctx) error {
+		return c.SendString(strconv.Itoa(len(c.Body())))
+	})
+	app.Post("/upload", func(c *fiber.Ctx) error {
+		var n int64
+		if stream := c.Context().RequestBodyStream(); stream != nil {
+			n, _ = io.Copy(io.Discard, stream)
+		} else {
+			n = int64(len(c.Body()))
+		}
+		return c.SendString(strconv.FormatInt(n, 10))
+	})
+	return app
+}
+
+func post(t *testing.T, app *fiber.App, path string, size int, chunked bool, header map[string]string) (int, string) {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(bytes.Repeat([]byte("a"), size)))
+	if chunked {
+		req.ContentLength = -1
+		req.TransferEncoding = []string{"chunked"}
+	}
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	res, err := app.Test(req, -1)
+	require.NoError(t, err)
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	return res.StatusCode, string(body)
+}
+
+func TestLimitBody(t *testing.T) {
+	app := bodyApp()
+	big := middleware.MaxBodyBytes + 1
+
+	// Streamed bodies within the limit reach the handler whole.
+	status, body := post(t, app, "/whole", 10<<10, false, nil)
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, strconv.Itoa(10<<10), body)
+	status, body = post(t, app, "/whole", 10<<10, true, nil)
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, strconv.Itoa(10<<10), body)
+
+	// Larger ones are refused whether their length is declared or not.
+	status, _ = post(t, app, "/whole", big, false, nil)
+	require.Equal(t, http.StatusRequestEntityTooLarge, status)
+	status, _ = post(t, app, "/whole", big, true, nil)
+	require.Equal(t, http.StatusRequestEntityTooLarge, status)
+
+	// Upload routes read their stream, unless the request is signed.
+	status, body = post(t, app, "/upload", 4*big, true, nil)
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, strconv.Itoa(4*big), body)
+	status, _ = post(t, app, "/upload", 4*big, false, map[string]string{reqsign.Header: "sig"})
+	require.Equal(t, http.StatusRequestEntityTooLarge, status)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"log/slog"
 	"time"
+	"txn-processor/pkg/audit"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -20,8 +21,9 @@ func RequestLogger() fiber.Handler {
 			c.Set(CorrelationIDKey, corrID)
 		}
 
-		// Attach to context for downstream logging
+		// Attach to context for downstream logging and audit records
 		ctx := context.WithValue(c.Context(), CorrelationIDKey, corrID)
+		ctx = audit.WithActor(ctx, audit.Actor{ID: audit.Anonymous, CorrelationID: corrID, SourceIP: c.IP()})
 		c.SetUserContext(ctx)
 
 		start := time.Now()
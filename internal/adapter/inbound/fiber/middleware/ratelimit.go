@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"math"
+	"strconv"
+	"time"
+	"txn-processor/internal/core/model"
+	"txn-processor/internal/core/service"
+	"txn-processor/internal/port"
+	"txn-processor/pkg/auth"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Rate limit headers, as in the IETF draft: the limit, what is left of it and the seconds until
+// it is full again.
+const (
+	RateLimitLimitHeader     = "RateLimit-Limit"
+	RateLimitRemainingHeader = "RateLimit-Remaining"
+	RateLimitResetHeader     = "RateLimit-Reset"
+)
+
+// RateLimit limits each caller on the route group: by API key or token subject once
+// authenticated, by client IP otherwise. It must run after Authenticate.
+func RateLimit(svc port.RateLimitService, route string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return limit(c, svc, route, callerKey(c))
+	}
+}
+
+func limit(c *fiber.Ctx, svc port.RateLimitService, route, key string) error {
+	res, err := svc.Allow(c.UserContext(), route, key)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	if res == nil {
+		return c.Next()
+	}
+	if !res.Allowed {
+		return TooManyRequests(c, *res)
+	}
+
+	setRateLimit(c, *res)
+	return c.Next()
+}
+
+// TooManyRequests refuses a request over the limit res describes, telling the caller when to
+// retry. Handlers use it for the limits the service layer applies.
+func TooManyRequests(c *fiber.Ctx, res model.RateLimitResult) error {
+	setRateLimit(c, res)
+	c.Set(fiber.HeaderRetryAfter, seconds(res.RetryAfter))
+	return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{"error": service.ErrRateLimited.Error()})
+}
+
+func setRateLimit(c *fiber.Ctx, res model.RateLimitResult) {
+	c.Set(RateLimitLimitHeader, strconv.Itoa(res.Limit))
+	c.Set(RateLimitRemainingHeader, strconv.Itoa(res.Remaining))
+	c.Set(RateLimitResetHeader, seconds(res.Reset))
+}
+
+func callerKey(c *fiber.Ctx) string {
+	p, ok := auth.FromContext(c.UserContext())
+	switch {
+	case ok && p.Method == auth.MethodAPIKey:
+		return "key:" + p.KeyID
+	case ok:
+		return "sub:" + p.Subject
+	default:
+		return "ip:" + c.IP()
+	}
+}
+
+// seconds rounds up, so a client waiting that long is never early.
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
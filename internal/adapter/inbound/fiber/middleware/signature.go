@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"errors"
+	"txn-processor/internal/core/model"
+	"txn-processor/internal/core/service"
+	"txn-processor/internal/port"
+	"txn-processor/pkg/audit"
+	"txn-processor/pkg/auth"
+	"txn-processor/pkg/reqsign"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// VerifySignature authenticates partner requests that carry a reqsign signature header and
+// passes all others through for Authenticate. A signed request that fails is refused rather
+// than retried with other credentials. It must run after RequestLogger, whose actor it refines.
+func VerifySignature(svc port.AuthService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		header := c.Get(reqsign.Header)
+		if header == "" {
+			return c.Next()
+		}
+
+		ctx := c.UserContext()
+		p, err := svc.AuthenticateSigned(ctx, model.SignedRequest{
+			Signature: header,
+			Method:    c.Method(),
+			URI:       c.OriginalURL(),
+			// The raw bytes, as signed: Body would decompress them.
+			Body: c.Request().Body(),
+		})
+		if err != nil {
+			if errors.Is(err, service.ErrUnauthenticated) {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		if p != nil {
+			actor := audit.FromContext(ctx)
+			actor.ID = p.Subject
+			ctx = audit.WithActor(auth.WithPrincipal(ctx, *p), actor)
+			c.SetUserContext(ctx)
+		}
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"txn-processor/internal/core/model"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp/fasthttpadaptor"
+)
+
+// MaxBodyBytes bounds request bodies read whole, by LimitBody and ValidateRequest.
+const MaxBodyBytes = 64 << 10
+
+// ValidateRequest refuses requests that do not match doc: unknown routes are left to the
+// router, but parameters and bodies of known operations must have the documented types and
+// bodies must not carry unknown fields. Credentials are checked by Authenticate, not here.
+func ValidateRequest(doc *openapi3.T) fiber.Handler {
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		panic(err)
+	}
+	options := &openapi3filter.Options{
+		MultiError:          true,
+		SkipSettingDefaults: true,
+		AuthenticationFunc:  openapi3filter.NoopAuthenticationFunc,
+	}
+
+	return func(c *fiber.Ctx) error {
+		// LimitBody has buffered the body already; the length check covers apps without it.
+		if c.Request().Header.ContentLength() > MaxBodyBytes || len(c.Request().Body()) > MaxBodyBytes {
+			return tooLarge(c)
+		}
+
+		var req http.Request
+		if err := fasthttpadaptor.ConvertRequest(c.Context(), &req, true); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(model.ErrorResponse{Error: "invalid request"})
+		}
+		// Fiber routes match with or without a trailing slash; the document has none.
+		if p := strings.TrimSuffix(req.URL.Path, "/"); p != "" {
+			req.URL.Path = p
+		}
+
+		route, params, err := router.FindRoute(&req)
+		if err != nil {
+			return c.Next()
+		}
+
+		err = openapi3filter.ValidateRequest(req.Context(), &openapi3filter.RequestValidationInput{
+			Request:    &req,
+			PathParams: params,
+			Route:      route,
+			Options:    options,
+		})
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).
+				JSON(model.ErrorResponse{Error: "invalid request", Details: details(err)})
+		}
+		return c.Next()
+	}
+}
+
+// details flattens the validator's errors into one entry per offending value.
+func details(err error) []model.ErrorDetail {
+	switch e := err.(type) {
+	case openapi3.MultiError:
+		var out []model.ErrorDetail
+		for _, inner := range e {
+			out = append(out, details(inner)...)
+		}
+		return out
+	case *openapi3filter.RequestError:
+		if e.Parameter != nil {
+			return []model.ErrorDetail{{In: e.Parameter.In, Field: e.Parameter.Name, Reason: reason(e.Err, e.Reason)}}
+		}
+		if schemaErrs, ok := e.Err.(openapi3.MultiError); ok {
+			var out []model.ErrorDetail
+			for _, inner := range schemaErrs {
+				out = append(out, bodyDetail(inner, e.Reason))
+			}
+			return out
+		}
+		return []model.ErrorDetail{bodyDetail(e.Err, e.Reason)}
+	default:
+		return []model.ErrorDetail{{In: "body", Reason: err.Error()}}
+	}
+}
+
+func bodyDetail(err error, fallback string) model.ErrorDetail {
+	var schemaErr *openapi3.SchemaError
+	if errors.As(err, &schemaErr) {
+		return model.ErrorDetail{In: "body", Field: strings.Join(schemaErr.JSONPointer(), "."), Reason: schemaErr.Reason}
+	}
+	return model.ErrorDetail{In: "body", Reason: reason(err, fallback)}
+}
+
+// reason prefers the schema's explanation, then the parser's, over the validator's summary.
+func reason(err error, fallback string) string {
+	var schemaErr *openapi3.SchemaError
+	if errors.As(err, &schemaErr) {
+		return schemaErr.Reason
+	}
+	if err != nil {
+		return err.Error()
+	}
+	return fallback
+}
@@ -0,0 +1,32 @@
+// Package openapi holds the OpenAPI document of the HTTP API.
+package openapi
+
+import (
+	"context"
+	_ "embed"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Spec is the document as served at /v1/openapi.json. It describes every route registered
+// by router.SetupRoutes; the router tests fail when the two drift apart.
+//
+//go:embed openapi.json
+var Spec []byte
+
+var load = sync.OnceValues(func() (*openapi3.T, error) {
+	doc, err := openapi3.NewLoader().LoadFromData(Spec)
+	if err != nil {
+		return nil, err
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, err
+	}
+	return doc, nil
+})
+
+// Load parses and validates Spec once.
+func Load() (*openapi3.T, error) {
+	return load()
+}
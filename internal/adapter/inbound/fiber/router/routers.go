@@ -1,18 +1,25 @@
 package router
 
 import (
+	"strings"
 	"txn-processor/internal/adapter/inbound/fiber/handler"
 	"txn-processor/internal/adapter/inbound/fiber/middleware"
+	"txn-processor/internal/adapter/inbound/fiber/openapi"
+	"txn-processor/internal/adapter/inbound/graphql"
 	"txn-processor/internal/core/service"
 	"txn-processor/internal/port"
 	"txn-processor/pkg/tracing"
 
+	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/gofiber/fiber/v2"
 )
 
 func New(svc *service.Service, tracer tracing.Tracer) *fiber.App {
 	app := fiber.New(fiber.Config{
 		DisableStartupMessage: true,
+		// Bodies over the limit are streamed to the handler instead of refused; only import
+		// uploads read them that way, LimitBody caps them everywhere else.
+		StreamRequestBody: true,
 	})
 	SetupRoutes(app, svc, tracer)
 	return app
@@ -20,6 +27,8 @@ func New(svc *service.Service, tracer tracing.Tracer) *fiber.App {
 
 func SetupRoutes(app *fiber.App, inbound port.Inbound, tracer tracing.Tracer) {
 	app.Use(middleware.RequestLogger())
+	app.Use(middleware.LimitBody(isImportUpload))
+	app.Use(middleware.VerifySignature(inbound))
 
 	if tracer.IsEnabled() {
 		app.Use(tracing.Middleware())
@@ -29,10 +38,49 @@ func SetupRoutes(app *fiber.App, inbound port.Inbound, tracer tracing.Tracer) {
 		app.Use(tracing.Middleware())
 	}
 
+	ReceiptRoutes(app, inbound)
+
 	v1 := app.Group("/v1")
 	HealthRoutes(v1, inbound)
+	OpenAPIRoutes(v1)
+
+	// Everything registered after this needs credentials; health, the JWKS and the OpenAPI
+	// document stay public.
+	v1.Use(middleware.Authenticate(inbound))
+	v1.Use("/accounts", middleware.RateLimit(inbound, "accounts"))
+	v1.Use("/transfers", middleware.RateLimit(inbound, "transfers"))
+	v1.Use("/webhooks", middleware.RateLimit(inbound, "webhooks"))
+	v1.Use("/admin", middleware.RateLimit(inbound, "admin"))
+	v1.Use("/graphql", middleware.RateLimit(inbound, "graphql"))
+	// Import uploads are streamed, which the validator would defeat by reading them whole.
+	ImportRoutes(v1, inbound)
+	v1.Use(middleware.ValidateRequest(spec()))
 	AccountRoutes(v1, inbound)
+	StreamRoutes(v1, inbound)
 	TransferRoutes(v1, inbound)
+	WebhookRoutes(v1, inbound)
+	AdminRoutes(v1, inbound, inbound)
+	AuditRoutes(v1, inbound)
+	GraphQLRoutes(v1, inbound)
+}
+
+// isImportUpload matches the one route that reads its body as a stream.
+func isImportUpload(c *fiber.Ctx) bool {
+	return c.Method() == fiber.MethodPut && strings.HasPrefix(c.Path(), "/v1/admin/imports/")
+}
+
+// spec is the OpenAPI document; it is embedded, so failing to load it is a build defect.
+func spec() *openapi3.T {
+	doc, err := openapi.Load()
+	if err != nil {
+		panic(err)
+	}
+	return doc
+}
+
+func OpenAPIRoutes(router fiber.Router) {
+	h := handler.NewOpenAPIHandler(openapi.Spec)
+	router.Get("/openapi.json", h.Spec)
 }
 
 func HealthRoutes(router fiber.Router, svc port.HealthService) {
@@ -45,10 +93,70 @@ func AccountRoutes(router fiber.Router, svc port.AccountService) {
 	r := router.Group("/accounts")
 	r.Post("/", h.Create)
 	r.Get("/:id", h.Get)
+	r.Get("/:id/changes", h.Changes)
+	r.Put("/:id/grants/:subject", h.Grant)
+	r.Delete("/:id/grants/:subject", h.Revoke)
+}
+
+func StreamRoutes(router fiber.Router, svc port.BalanceService) {
+	h := handler.NewStreamHandler(svc)
+	r := router.Group("/accounts")
+	r.Get("/:id/stream", h.SSE)
+	r.Get("/:id/ws", h.Upgrade, h.WebSocket())
 }
 
 func TransferRoutes(router fiber.Router, svc port.TransferService) {
 	h := handler.NewTransferHandler(svc)
 	r := router.Group("/transfers")
 	r.Post("/", h.Create)
+	r.Get("/:id", h.Get)
+}
+
+func WebhookRoutes(router fiber.Router, svc port.WebhookService) {
+	h := handler.NewWebhookHandler(svc)
+	r := router.Group("/webhooks")
+	r.Post("/", h.Create)
+	r.Get("/:id", h.Get)
+	r.Delete("/:id", h.Delete)
+	r.Post("/:id/enable", h.Enable)
+	r.Get("/:id/deliveries", h.Deliveries)
+	r.Post("/:id/replay", h.Replay)
+}
+
+func AdminRoutes(router fiber.Router, accounts port.AccountService, transfers port.TransferService) {
+	h := handler.NewAccountHandler(accounts)
+	t := handler.NewTransferHandler(transfers)
+	r := router.Group("/admin")
+	r.Get("/accounts", h.List)
+	r.Put("/accounts/:id/shards", h.SetShards)
+	r.Put("/accounts/:id/freeze", h.Freeze)
+	r.Delete("/accounts/:id/freeze", h.Unfreeze)
+	r.Post("/transfers/:id/reverse", t.Reverse)
+	r.Get("/reconcile", h.Reconcile)
+}
+
+func ImportRoutes(router fiber.Router, svc port.ImportService) {
+	h := handler.NewImportHandler(svc)
+	r := router.Group("/admin/imports")
+	r.Put("/:id", h.Upload)
+	r.Get("/:id", h.Get)
+	r.Get("/:id/results", h.Results)
+}
+
+func AuditRoutes(router fiber.Router, svc port.AuditService) {
+	h := handler.NewAuditHandler(svc)
+	r := router.Group("/admin")
+	r.Get("/audit", h.List)
+	r.Get("/events", h.Events)
+}
+
+// GraphQLRoutes serves the GraphQL API, which reads and moves money through every service.
+func GraphQLRoutes(router fiber.Router, svc port.Inbound) {
+	router.Post("/graphql", graphql.NewHandler(svc))
+}
+
+// ReceiptRoutes serves the JWKS at its well-known location, outside the versioned API.
+func ReceiptRoutes(router fiber.Router, svc port.ReceiptService) {
+	h := handler.NewReceiptHandler(svc)
+	router.Get("/.well-known/jwks.json", h.JWKS)
 }
@@ -0,0 +1,50 @@
+package router_test
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"txn-processor/internal/adapter/inbound/fiber/openapi"
+	"txn-processor/internal/adapter/inbound/fiber/router"
+	"txn-processor/pkg/tracing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+)
+
+var param = regexp.MustCompile(`:(\w+)`)
+
+// TestOpenAPIMatchesRoutes fails when a route is added without documenting it, or the
+// document describes a route that no longer exists.
+func TestOpenAPIMatchesRoutes(t *testing.T) {
+	doc, err := openapi.Load()
+	require.NoError(t, err)
+
+	app := fiber.New()
+	router.SetupRoutes(app, nil, tracing.NewBlankTracer())
+
+	var routes []string
+	for _, r := range app.GetRoutes(true) {
+		if r.Method == fiber.MethodHead {
+			continue
+		}
+		path := param.ReplaceAllString(r.Path, "{$1}")
+		if p := strings.TrimSuffix(path, "/"); p != "" {
+			path = p
+		}
+		routes = append(routes, r.Method+" "+path)
+	}
+
+	var documented []string
+	for path, item := range doc.Paths.Map() {
+		for method := range item.Operations() {
+			documented = append(documented, method+" "+path)
+		}
+	}
+
+	slices.Sort(routes)
+	slices.Sort(documented)
+	require.Equal(t, slices.Compact(documented), slices.Compact(routes))
+}
@@ -0,0 +1,126 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"txn-processor/internal/core/service"
+	"txn-processor/internal/port"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/executor"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/gofiber/fiber/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// MaxComplexity bounds the cost of one operation. Every field costs one, and list fields
+// multiply their children by the page size they ask for, so a query that would fan out into
+// thousands of rows is refused before it touches the database.
+const MaxComplexity = 1000
+
+// NewHandler serves GraphQL over POST. It expects Authenticate to have run, like the REST
+// handlers, and reads the caller from the user context.
+func NewHandler(inbound port.Inbound) fiber.Handler {
+	exec := executor.New(NewExecutableSchema(Config{
+		Resolvers:  &resolver{svc: inbound},
+		Complexity: complexity(),
+	}))
+	exec.Use(extension.Introspection{})
+	exec.Use(extension.FixedComplexityLimit(MaxComplexity))
+	exec.SetQueryCache(lru.New[*ast.QueryDocument](1000))
+	exec.SetErrorPresenter(presentError)
+
+	return func(c *fiber.Ctx) error {
+		ctx := graphql.StartOperationTrace(c.UserContext())
+		start := graphql.Now()
+
+		// Numbers stay json.Number so large IDs in variables keep their precision.
+		var params graphql.RawParams
+		dec := json.NewDecoder(bytes.NewReader(c.Body()))
+		dec.UseNumber()
+		if err := dec.Decode(&params); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request"})
+		}
+		params.ReadTime = graphql.TraceTiming{Start: start, End: graphql.Now()}
+
+		op, errs := exec.CreateOperationContext(ctx, &params)
+		if errs != nil {
+			return c.Status(fiber.StatusUnprocessableEntity).
+				JSON(exec.DispatchError(graphql.WithOperationContext(ctx, op), errs))
+		}
+
+		ctx = withLoaders(ctx, newLoaders(inbound))
+		responses, ctx := exec.DispatchOperation(ctx, op)
+		return c.Status(fiber.StatusOK).JSON(responses(ctx))
+	}
+}
+
+func complexity() ComplexityRoot {
+	var c ComplexityRoot
+	c.Query.Statement = func(child int, _ int64, _ int, limit int) int { return 1 + statementRows(limit)*child }
+	c.Account.Statement = func(child int, _ int, limit int) int { return 1 + statementRows(limit)*child }
+	c.Account.RecentTransfers = func(child int, last int) int {
+		if last <= 0 || last > MaxRecentTransfers {
+			last = MaxRecentTransfers
+		}
+		return 1 + last*child
+	}
+	return c
+}
+
+// statementRows is the page size a statement limit is priced at: the rows the service serves
+// for it. A limit the service refuses costs as much as the largest page, because the fields
+// next to the failing one still resolve.
+func statementRows(limit int) int {
+	switch {
+	case limit == 0:
+		return service.DefaultChangesPage
+	case limit < 0 || limit > service.MaxChangesPage:
+		return service.MaxChangesPage
+	default:
+		return limit
+	}
+}
+
+// presentError tags service errors with a code clients can switch on, the way the REST
+// handlers pick a status. Parse and validation errors pass through untouched.
+func presentError(ctx context.Context, err error) *gqlerror.Error {
+	presented := graphql.DefaultErrorPresenter(ctx, err)
+	if presented.Err == nil {
+		return presented
+	}
+	if presented.Extensions == nil {
+		presented.Extensions = map[string]any{}
+	}
+	presented.Extensions["code"] = errorCode(err)
+	return presented
+}
+
+func errorCode(err error) string {
+	switch {
+	case errors.Is(err, service.ErrValidation):
+		return "BAD_USER_INPUT"
+	case errors.Is(err, service.ErrUnauthenticated):
+		return "UNAUTHENTICATED"
+	case errors.Is(err, service.ErrForbidden):
+		return "FORBIDDEN"
+	case errors.Is(err, service.ErrNotFound):
+		return "NOT_FOUND"
+	case errors.Is(err, service.ErrConflict):
+		return "CONFLICT"
+	case errors.Is(err, service.ErrInsufficientFunds):
+		return "INSUFFICIENT_FUNDS"
+	case errors.Is(err, service.ErrAccountFrozen):
+		return "ACCOUNT_FROZEN"
+	case errors.Is(err, service.ErrBusy):
+		return "BUSY"
+	case errors.Is(err, service.ErrRateLimited):
+		return "RATE_LIMITED"
+	default:
+		return "INTERNAL"
+	}
+}
@@ -0,0 +1,71 @@
+package graphql
+
+import (
+	"context"
+	"time"
+	"txn-processor/internal/core/model"
+	"txn-processor/internal/core/service"
+	"txn-processor/internal/port"
+
+	"github.com/graph-gophers/dataloader/v7"
+)
+
+// batchWait is how long a loader collects keys before it queries.
+const batchWait = 2 * time.Millisecond
+
+// loaders batch the account and transfer lookups of one request, so a list of n transfers
+// with their accounts costs two queries instead of 2n+1. They also cache per request.
+type loaders struct {
+	accounts  *dataloader.Loader[int64, *model.AccountGetResponse]
+	transfers *dataloader.Loader[int64, *model.TransferResponse]
+}
+
+type loadersKey struct{}
+
+func newLoaders(inbound port.Inbound) *loaders {
+	return &loaders{
+		accounts: dataloader.NewBatchedLoader(
+			batch(inbound.GetAccounts, func(a model.AccountGetResponse) int64 { return a.AccountID }),
+			dataloader.WithBatchCapacity[int64, *model.AccountGetResponse](service.MaxBatchLookup),
+			dataloader.WithWait[int64, *model.AccountGetResponse](batchWait),
+		),
+		transfers: dataloader.NewBatchedLoader(
+			batch(inbound.GetTransfers, func(t model.TransferResponse) int64 { return t.TransactionID }),
+			dataloader.WithBatchCapacity[int64, *model.TransferResponse](service.MaxBatchLookup),
+			dataloader.WithWait[int64, *model.TransferResponse](batchWait),
+		),
+	}
+}
+
+// batch adapts a service lookup to a loader. Keys the service leaves out resolve to nil, so
+// an unreadable account or transfer shows up as null rather than failing its parent.
+func batch[V any](get func(context.Context, []int64) ([]V, error), id func(V) int64) dataloader.BatchFunc[int64, *V] {
+	return func(ctx context.Context, keys []int64) []*dataloader.Result[*V] {
+		results := make([]*dataloader.Result[*V], len(keys))
+
+		found, err := get(ctx, keys)
+		if err != nil {
+			for i := range results {
+				results[i] = &dataloader.Result[*V]{Error: err}
+			}
+			return results
+		}
+
+		byID := make(map[int64]*V, len(found))
+		for i := range found {
+			byID[id(found[i])] = &found[i]
+		}
+		for i, key := range keys {
+			results[i] = &dataloader.Result[*V]{Data: byID[key]}
+		}
+		return results
+	}
+}
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
@@ -0,0 +1,110 @@
+package graphql
+
+import (
+	"context"
+	"slices"
+	"txn-processor/internal/core/model"
+	"txn-processor/internal/core/service"
+	"txn-processor/internal/port"
+)
+
+// MaxRecentTransfers bounds Account.recentTransfers.
+const MaxRecentTransfers = 100
+
+// resolver answers every field through port.Inbound, so the services apply the same checks
+// as for REST and gRPC. Nested accounts and transfers go through the request's loaders.
+type resolver struct {
+	svc port.Inbound
+}
+
+var _ ResolverRoot = (*resolver)(nil)
+
+func (r *resolver) Query() QueryResolver                   { return (*queryResolver)(r) }
+func (r *resolver) Mutation() MutationResolver             { return (*mutationResolver)(r) }
+func (r *resolver) Account() AccountResolver               { return (*accountResolver)(r) }
+func (r *resolver) StatementEntry() StatementEntryResolver { return (*statementEntryResolver)(r) }
+func (r *resolver) Transfer() TransferResolver             { return (*transferResolver)(r) }
+
+type queryResolver resolver
+
+func (r *queryResolver) Account(ctx context.Context, id int64) (*model.AccountGetResponse, error) {
+	return r.svc.GetAccount(ctx, id)
+}
+
+func (r *queryResolver) Transfer(ctx context.Context, id string) (*model.TransferStatusResponse, error) {
+	return r.svc.GetTransfer(ctx, id)
+}
+
+func (r *queryResolver) Statement(ctx context.Context, accountID int64, since int, limit int) (*model.AccountChangesResponse, error) {
+	return r.svc.GetAccountChanges(ctx, accountID, int64(since), limit)
+}
+
+type mutationResolver resolver
+
+func (r *mutationResolver) CreateTransfer(ctx context.Context, input model.TransferRequest) (*model.TransferResponse, error) {
+	return r.svc.ProcessTransfer(ctx, input)
+}
+
+func (r *mutationResolver) SubmitTransfer(ctx context.Context, input model.TransferRequest) (*model.TransferStatusResponse, error) {
+	return r.svc.SubmitTransfer(ctx, input)
+}
+
+type accountResolver resolver
+
+func (r *accountResolver) Statement(ctx context.Context, obj *model.AccountGetResponse, since int, limit int) (*model.AccountChangesResponse, error) {
+	return r.svc.GetAccountChanges(ctx, obj.AccountID, int64(since), limit)
+}
+
+// RecentTransfers reads the account's last changes; versions have no gaps, so they start
+// right after version-last.
+func (r *accountResolver) RecentTransfers(ctx context.Context, obj *model.AccountGetResponse, last int) ([]*model.TransferResponse, error) {
+	if last <= 0 || last > MaxRecentTransfers {
+		return nil, service.ErrValidation
+	}
+
+	changes, err := r.svc.GetAccountChanges(ctx, obj.AccountID, max(obj.Version-int64(last), 0), last)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []int64
+	for _, c := range slices.Backward(changes.Changes) {
+		if c.TransferID != 0 {
+			ids = append(ids, c.TransferID)
+		}
+	}
+	if len(ids) == 0 {
+		return []*model.TransferResponse{}, nil
+	}
+
+	found, errs := loadersFrom(ctx).transfers.LoadMany(ctx, ids)()
+	transfers := make([]*model.TransferResponse, 0, len(found))
+	for i, tr := range found {
+		if len(errs) > i && errs[i] != nil {
+			return nil, errs[i]
+		}
+		if tr != nil {
+			transfers = append(transfers, tr)
+		}
+	}
+	return transfers, nil
+}
+
+type statementEntryResolver resolver
+
+func (r *statementEntryResolver) Transfer(ctx context.Context, obj *model.AccountChange) (*model.TransferResponse, error) {
+	if obj.TransferID == 0 {
+		return nil, nil
+	}
+	return loadersFrom(ctx).transfers.Load(ctx, obj.TransferID)()
+}
+
+type transferResolver resolver
+
+func (r *transferResolver) Source(ctx context.Context, obj *model.TransferResponse) (*model.AccountGetResponse, error) {
+	return loadersFrom(ctx).accounts.Load(ctx, obj.SourceAccountID)()
+}
+
+func (r *transferResolver) Destination(ctx context.Context, obj *model.TransferResponse) (*model.AccountGetResponse, error) {
+	return loadersFrom(ctx).accounts.Load(ctx, obj.DestinationAccountID)()
+}
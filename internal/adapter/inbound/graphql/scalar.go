@@ -0,0 +1,41 @@
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"txn-processor/internal/core/service"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+// MarshalInt64ID writes a numeric ID as a string, as the spec asks of ID, so clients never
+// round it through a float.
+func MarshalInt64ID(id int64) graphql.Marshaler {
+	return graphql.WriterFunc(func(w io.Writer) {
+		_, _ = io.WriteString(w, strconv.Quote(strconv.FormatInt(id, 10)))
+	})
+}
+
+// UnmarshalInt64ID accepts an ID given as a string or as an integer.
+func UnmarshalInt64ID(v any) (int64, error) {
+	var id int64
+	var err error
+	switch v := v.(type) {
+	case string:
+		id, err = strconv.ParseInt(v, 10, 64)
+	case json.Number:
+		id, err = v.Int64()
+	case int:
+		id = int64(v)
+	case int64:
+		id = v
+	default:
+		err = fmt.Errorf("%T is not an ID", v)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", service.ErrValidation, err)
+	}
+	return id, nil
+}
@@ -0,0 +1,132 @@
+package grpc
+
+import (
+	"context"
+	txnv1 "txn-processor/internal/adapter/inbound/grpc/pb/txn/v1"
+	"txn-processor/internal/core/model"
+	"txn-processor/internal/port"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type healthServer struct {
+	txnv1.UnimplementedHealthServiceServer
+	svc port.HealthService
+}
+
+func (s *healthServer) Check(ctx context.Context, _ *txnv1.CheckRequest) (*txnv1.CheckResponse, error) {
+	return &txnv1.CheckResponse{Serving: s.svc.Check(ctx) == nil}, nil
+}
+
+type accountServer struct {
+	txnv1.UnimplementedAccountServiceServer
+	svc port.AccountService
+}
+
+func (s *accountServer) CreateAccount(ctx context.Context, req *txnv1.CreateAccountRequest) (*txnv1.CreateAccountResponse, error) {
+	res, err := s.svc.CreateAccount(ctx, model.AccountCreateRequest{
+		AccountID:      req.GetAccountId(),
+		InitialBalance: req.GetInitialBalance(),
+		Owner:          req.GetOwner(),
+	})
+	if err != nil {
+		return nil, toStatus(ctx, err, "account not found")
+	}
+	return &txnv1.CreateAccountResponse{AccountId: res.AccountID}, nil
+}
+
+func (s *accountServer) GetAccount(ctx context.Context, req *txnv1.GetAccountRequest) (*txnv1.Account, error) {
+	res, err := s.svc.GetAccount(ctx, req.GetAccountId())
+	if err != nil {
+		return nil, toStatus(ctx, err, "account not found")
+	}
+	return toAccount(res), nil
+}
+
+func (s *accountServer) GetAccountChanges(ctx context.Context, req *txnv1.GetAccountChangesRequest) (*txnv1.GetAccountChangesResponse, error) {
+	res, err := s.svc.GetAccountChanges(ctx, req.GetAccountId(), req.GetSince(), int(req.GetLimit()))
+	if err != nil {
+		return nil, toStatus(ctx, err, "account not found")
+	}
+
+	out := &txnv1.GetAccountChangesResponse{AccountId: res.AccountID, Next: res.Next, HasMore: res.HasMore}
+	for _, c := range res.Changes {
+		out.Changes = append(out.Changes, &txnv1.AccountChange{
+			Version:    c.Version,
+			Kind:       c.Kind,
+			Amount:     c.Amount,
+			Balance:    c.Balance,
+			TransferId: c.TransferID,
+			CreatedAt:  timestamppb.New(c.CreatedAt),
+		})
+	}
+	return out, nil
+}
+
+type transferServer struct {
+	txnv1.UnimplementedTransferServiceServer
+	svc port.TransferService
+}
+
+func (s *transferServer) CreateTransfer(ctx context.Context, req *txnv1.CreateTransferRequest) (*txnv1.Transfer, error) {
+	res, err := s.svc.ProcessTransfer(ctx, toTransferRequest(req))
+	if err != nil {
+		return nil, toStatus(ctx, err, "account not found")
+	}
+	return toTransfer(res), nil
+}
+
+func (s *transferServer) SubmitTransfer(ctx context.Context, req *txnv1.CreateTransferRequest) (*txnv1.TransferStatus, error) {
+	res, err := s.svc.SubmitTransfer(ctx, toTransferRequest(req))
+	if err != nil {
+		return nil, toStatus(ctx, err, "account not found")
+	}
+	return toTransferStatus(res), nil
+}
+
+func (s *transferServer) GetTransfer(ctx context.Context, req *txnv1.GetTransferRequest) (*txnv1.TransferStatus, error) {
+	res, err := s.svc.GetTransfer(ctx, req.GetTransferId())
+	if err != nil {
+		return nil, toStatus(ctx, err, "transfer not found")
+	}
+	return toTransferStatus(res), nil
+}
+
+func toAccount(a *model.AccountGetResponse) *txnv1.Account {
+	return &txnv1.Account{AccountId: a.AccountID, Balance: a.Balance, Version: a.Version, Owner: a.Owner}
+}
+
+func toTransferRequest(req *txnv1.CreateTransferRequest) model.TransferRequest {
+	return model.TransferRequest{
+		RequestID:            req.GetRequestId(),
+		SourceAccountID:      req.GetSourceAccountId(),
+		DestinationAccountID: req.GetDestinationAccountId(),
+		Amount:               req.GetAmount(),
+	}
+}
+
+func toTransfer(t *model.TransferResponse) *txnv1.Transfer {
+	if t == nil {
+		return nil
+	}
+	return &txnv1.Transfer{
+		TransactionId:        t.TransactionID,
+		RequestId:            t.RequestID,
+		SourceAccountId:      t.SourceAccountID,
+		DestinationAccountId: t.DestinationAccountID,
+		Amount:               t.Amount,
+		CreatedAt:            timestamppb.New(t.CreatedAt),
+		Receipt:              t.Receipt,
+	}
+}
+
+func toTransferStatus(s *model.TransferStatusResponse) *txnv1.TransferStatus {
+	return &txnv1.TransferStatus{
+		TransferId:           s.TransferID,
+		Status:               s.Status,
+		SourceAccountId:      s.SourceAccountID,
+		DestinationAccountId: s.DestinationAccountID,
+		FailureReason:        s.FailureReason,
+		Transfer:             toTransfer(s.Transfer),
+	}
+}
@@ -0,0 +1,185 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+	txnv1 "txn-processor/internal/adapter/inbound/grpc/pb/txn/v1"
+	"txn-processor/internal/core/model"
+	"txn-processor/internal/core/service"
+	"txn-processor/internal/port"
+	"txn-processor/pkg/audit"
+	"txn-processor/pkg/auth"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Metadata keys, the lower-case forms of the HTTP headers.
+const (
+	CorrelationIDKey = "x-correlation-id"
+	APIKeyKey        = "x-api-key"
+	AuthorizationKey = "authorization"
+)
+
+// requestContext is RequestLogger for gRPC: it sets the correlation ID and anonymous actor
+// and logs each call.
+func requestContext() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		corrID := first(ctx, CorrelationIDKey)
+		if corrID == "" {
+			corrID = uuid.NewString()
+		}
+		_ = grpc.SetHeader(ctx, metadata.Pairs(CorrelationIDKey, corrID))
+
+		var ip string
+		if p, ok := peer.FromContext(ctx); ok {
+			ip = p.Addr.String()
+		}
+
+		ctx = audit.WithActor(ctx, audit.Actor{ID: audit.Anonymous, CorrelationID: corrID, SourceIP: ip})
+
+		start := time.Now()
+		res, err := handler(ctx, req)
+
+		slog.InfoContext(ctx, "gRPC call", "correlation_id", corrID, "method", info.FullMethod, "ip", ip, "code", status.Code(err).String(), "latency_ms", time.Since(start).Milliseconds())
+		return res, err
+	}
+}
+
+// authenticate is the Authenticate middleware for gRPC. Health checks stay public.
+func authenticate(svc port.AuthService) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if info.FullMethod == txnv1.HealthService_Check_FullMethodName {
+			return handler(ctx, req)
+		}
+
+		creds := model.Credentials{APIKey: first(ctx, APIKeyKey)}
+		if scheme, token, ok := strings.Cut(first(ctx, AuthorizationKey), " "); ok && strings.EqualFold(scheme, "Bearer") {
+			creds.BearerToken = strings.TrimSpace(token)
+		}
+
+		p, err := svc.Authenticate(ctx, creds)
+		if err != nil {
+			var limited *service.RateLimitError
+			if errors.As(err, &limited) {
+				return nil, resourceExhausted(ctx, limited.Result)
+			}
+			if errors.Is(err, service.ErrUnauthenticated) {
+				return nil, status.Error(codes.Unauthenticated, service.ErrUnauthenticated.Error())
+			}
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+
+		if p != nil {
+			actor := audit.FromContext(ctx)
+			actor.ID = p.Subject
+			ctx = audit.WithActor(auth.WithPrincipal(ctx, *p), actor)
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// RetryAfterKey tells a refused caller how many seconds to wait, like the Retry-After header.
+const RetryAfterKey = "retry-after"
+
+// rateLimit is RateLimit for gRPC, so both servers share one budget per caller: account calls
+// count against the "accounts" group, transfer calls against "transfers". The service counts
+// transfers against their source account. It must run after authenticate.
+func rateLimit(svc port.RateLimitService) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		var route string
+		switch {
+		case strings.HasPrefix(info.FullMethod, "/"+txnv1.AccountService_ServiceDesc.ServiceName+"/"):
+			route = "accounts"
+		case strings.HasPrefix(info.FullMethod, "/"+txnv1.TransferService_ServiceDesc.ServiceName+"/"):
+			route = "transfers"
+		default:
+			return handler(ctx, req)
+		}
+
+		if err := allow(ctx, svc, route, callerKey(ctx)); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func allow(ctx context.Context, svc port.RateLimitService, route, key string) error {
+	res, err := svc.Allow(ctx, route, key)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	if res == nil || res.Allowed {
+		return nil
+	}
+	return resourceExhausted(ctx, *res)
+}
+
+// resourceExhausted refuses a call over the limit res describes, with the seconds to wait in
+// the retry-after header.
+func resourceExhausted(ctx context.Context, res model.RateLimitResult) error {
+	// Seconds round up, so a client waiting that long is never early.
+	_ = grpc.SetHeader(ctx, metadata.Pairs(RetryAfterKey, strconv.Itoa(int(math.Ceil(res.RetryAfter.Seconds())))))
+	return status.Error(codes.ResourceExhausted, service.ErrRateLimited.Error())
+}
+
+// callerKey matches the HTTP middleware's, so a caller has the same key on both servers.
+func callerKey(ctx context.Context) string {
+	p, ok := auth.FromContext(ctx)
+	switch {
+	case ok && p.Method == auth.MethodAPIKey:
+		return "key:" + p.KeyID
+	case ok:
+		return "sub:" + p.Subject
+	}
+	ip := audit.FromContext(ctx).SourceIP
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	return "ip:" + ip
+}
+
+func first(ctx context.Context, key string) string {
+	if vs := metadata.ValueFromIncomingContext(ctx, key); len(vs) > 0 {
+		return vs[0]
+	}
+	return ""
+}
+
+// toStatus maps service errors to gRPC codes the way the HTTP handlers map them to statuses.
+func toStatus(ctx context.Context, err error, notFound string) error {
+	var limited *service.RateLimitError
+	if errors.As(err, &limited) {
+		return resourceExhausted(ctx, limited.Result)
+	}
+
+	switch {
+	case errors.Is(err, service.ErrValidation):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, service.ErrUnauthenticated):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, service.ErrForbidden):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, service.ErrNotFound):
+		return status.Error(codes.NotFound, notFound)
+	case errors.Is(err, service.ErrConflict):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, service.ErrInsufficientFunds), errors.Is(err, service.ErrAccountFrozen):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, service.ErrBusy):
+		return status.Error(codes.Unavailable, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
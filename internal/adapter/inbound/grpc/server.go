@@ -0,0 +1,67 @@
+// Package grpc serves the txn.v1 gRPC API next to the HTTP one. It is a second inbound
+// adapter over the same port.Inbound services, so both apply the same validation and policy.
+package grpc
+
+import (
+	"context"
+	"net"
+	txnv1 "txn-processor/internal/adapter/inbound/grpc/pb/txn/v1"
+	"txn-processor/internal/port"
+	"txn-processor/pkg/tracing"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+)
+
+type Server struct {
+	srv *grpc.Server
+}
+
+func New(inbound port.Inbound, tracer tracing.Tracer) *Server {
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(requestContext(), authenticate(inbound), rateLimit(inbound)),
+	}
+	if tracer.IsEnabled() {
+		opts = append(opts, grpc.StatsHandler(otelgrpc.NewServerHandler()))
+	}
+
+	srv := grpc.NewServer(opts...)
+	txnv1.RegisterHealthServiceServer(srv, &healthServer{svc: inbound})
+	txnv1.RegisterAccountServiceServer(srv, &accountServer{svc: inbound})
+	txnv1.RegisterTransferServiceServer(srv, &transferServer{svc: inbound})
+	// Reflection lets grpcurl and similar tools discover the API.
+	reflection.Register(srv)
+
+	return &Server{srv: srv}
+}
+
+func (s *Server) Listen(addr string) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(lis)
+}
+
+// Serve accepts connections on lis until Shutdown.
+func (s *Server) Serve(lis net.Listener) error {
+	return s.srv.Serve(lis)
+}
+
+// Shutdown lets in-flight calls finish, cutting them off once ctx is done.
+func (s *Server) Shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.srv.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.srv.Stop()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,176 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+	"txn-processor/config"
+	"txn-processor/internal/core/model"
+	"txn-processor/internal/core/service"
+	"txn-processor/internal/port"
+	"txn-processor/pkg/audit"
+	"txn-processor/pkg/auth"
+	"txn-processor/pkg/stream"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+// Command fields. Values mirror the JSON names of model.TransferRequest.
+const (
+	FieldRequestID   = "request_id"
+	FieldSource      = "source_account_id"
+	FieldDestination = "destination_account_id"
+	FieldAmount      = "amount"
+	FieldStatus      = "status"
+	FieldTransfer    = "transfer"
+	FieldError       = "error"
+	// Credentials of the producer, checked like the API's X-API-Key and bearer token.
+	FieldAPIKey = "api_key"
+	FieldToken  = "token"
+)
+
+const errMalformedPrefix = "malformed command"
+
+// TransferConsumer takes TransferRequests from a Redis Stream and answers on the configured
+// reply stream; producers cannot name another, so a command never writes outside the queue's
+// own keys. Redelivered commands are safe because the request_id makes the transfer idempotent.
+type TransferConsumer struct {
+	client   redis.UniversalClient
+	svc      port.TransferService
+	auth     port.AuthService
+	conf     config.Commands
+	consumer *stream.Consumer
+	dead     stream.DeadLetterFunc
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewTransferConsumer(client redis.UniversalClient, svc port.TransferService, auth port.AuthService, conf config.Commands) *TransferConsumer {
+	name, err := os.Hostname()
+	if err != nil || name == "" {
+		name = uuid.NewString()
+	}
+
+	// Parked commands are read by operators, so the producer's credentials are not kept.
+	dead := stream.DeadLetterTo(client, conf.DeadStream, FieldAPIKey, FieldToken)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &TransferConsumer{
+		ctx:    ctx,
+		cancel: cancel,
+		client: client,
+		svc:    svc,
+		auth:   auth,
+		conf:   conf,
+		dead:   dead,
+		consumer: stream.NewConsumer(client, stream.ConsumerConfig{
+			Streams:       []string{conf.Stream},
+			Group:         conf.Group,
+			Consumer:      name,
+			ClaimIdle:     time.Duration(conf.ClaimIdleSec) * time.Second,
+			MaxDeliveries: conf.MaxDeliveries,
+			DeadLetter:    dead,
+		}),
+	}
+}
+
+// Listen consumes commands until Shutdown is called.
+func (t *TransferConsumer) Listen() error {
+	t.wg.Add(1)
+	defer t.wg.Done()
+
+	return t.consumer.Run(t.ctx, t.handle)
+}
+
+// Shutdown stops reading and waits for the command in flight.
+func (t *TransferConsumer) Shutdown() error {
+	t.cancel()
+	t.wg.Wait()
+	return nil
+}
+
+func (t *TransferConsumer) handle(ctx context.Context, msg stream.Message) error {
+	req, err := decodeCommand(msg)
+	if err != nil {
+		// A command we can never parse is poison: park it instead of redelivering it forever.
+		msg.Values[stream.FieldDeadReason] = err.Error()
+		return t.dead(ctx, msg)
+	}
+
+	// Commands are attributed to the queue, or to the producer once it authenticates; the
+	// request ID correlates them with the producer.
+	actor := audit.Actor{ID: "queue:" + t.conf.Stream, CorrelationID: req.RequestID}
+
+	// Each command carries its own credentials, so the service applies the same scopes and
+	// account ownership as it does to API requests.
+	p, err := t.auth.Authenticate(ctx, model.Credentials{APIKey: msg.Values[FieldAPIKey], BearerToken: msg.Values[FieldToken]})
+	switch {
+	case errors.Is(err, service.ErrUnauthenticated):
+		// Nothing is written on behalf of an unknown producer; operators find it with the poison.
+		// The reason stays generic, since err may describe the rejected credential.
+		msg.Values[stream.FieldDeadReason] = service.ErrUnauthenticated.Error()
+		return t.dead(ctx, msg)
+	case err != nil:
+		return err
+	case p != nil:
+		ctx = auth.WithPrincipal(ctx, *p)
+		actor.ID = p.Subject
+	}
+	ctx = audit.WithActor(ctx, actor)
+
+	res, err := t.svc.ProcessTransfer(ctx, req)
+	switch {
+	case err == nil:
+		return t.reply(ctx, req.RequestID, model.TransferCompleted, res, "")
+	case errors.Is(err, service.ErrValidation), errors.Is(err, service.ErrNotFound), errors.Is(err, service.ErrInsufficientFunds),
+		errors.Is(err, service.ErrAccountFrozen), errors.Is(err, service.ErrForbidden):
+		return t.reply(ctx, req.RequestID, model.TransferFailed, nil, err.Error())
+	default:
+		// Infrastructure failures and rate-limited transfers are retried through redelivery.
+		return err
+	}
+}
+
+func (t *TransferConsumer) reply(ctx context.Context, requestID, status string, res *model.TransferResponse, reason string) error {
+	values := map[string]interface{}{
+		FieldRequestID: requestID,
+		FieldStatus:    status,
+	}
+	if res != nil {
+		b, _ := json.Marshal(res)
+		values[FieldTransfer] = string(b)
+	}
+	if reason != "" {
+		values[FieldError] = reason
+	}
+
+	return t.client.XAdd(ctx, &redis.XAddArgs{Stream: t.conf.ReplyStream, Values: values}).Err()
+}
+
+func decodeCommand(msg stream.Message) (model.TransferRequest, error) {
+	req := model.TransferRequest{
+		RequestID: msg.Values[FieldRequestID],
+		Amount:    msg.Values[FieldAmount],
+	}
+	if req.RequestID == "" {
+		return req, fmt.Errorf("%s: missing %s", errMalformedPrefix, FieldRequestID)
+	}
+
+	var err error
+	if req.SourceAccountID, err = strconv.ParseInt(msg.Values[FieldSource], 10, 64); err != nil {
+		return req, fmt.Errorf("%s: %s: %w", errMalformedPrefix, FieldSource, err)
+	}
+	if req.DestinationAccountID, err = strconv.ParseInt(msg.Values[FieldDestination], 10, 64); err != nil {
+		return req, fmt.Errorf("%s: %s: %w", errMalformedPrefix, FieldDestination, err)
+	}
+
+	return req, nil
+}
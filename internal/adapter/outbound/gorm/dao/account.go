@@ -2,14 +2,18 @@ package dao
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"time"
 	"txn-processor/internal/adapter/outbound/gorm/entity"
 	"txn-processor/internal/core/model"
 	"txn-processor/internal/port"
+	"txn-processor/pkg/decimal"
 
 	"golang.org/x/sync/singleflight"
+	"gorm.io/gorm"
 )
 
 const accountTTL = 60 * time.Second
@@ -32,21 +36,47 @@ func (d *accountDAO) CreateAccount(ctx context.Context, req model.AccountCreateR
 	ctx, span := d.tracer.Start(ctx, "dao.account.create")
 	defer span.End()
 
-	e := entity.Account{
-		AccountID: req.AccountID,
-		Balance:   req.InitialBalance,
-	}
-
-	if err := d.db.WithContext(ctx).
-		Model(&entity.Account{}).
-		Create(&e).Error; err != nil {
+	balance, err := decimal.ParseMoney(req.InitialBalance)
+	if err != nil {
 		span.RecordError(err)
 		return err
 	}
 
+	e := entity.Account{
+		AccountID: req.AccountID,
+		Balance:   balance,
+		Version:   1,
+		Owner:     req.Owner,
+	}
+
 	resp := model.AccountGetResponse{
 		AccountID: e.AccountID,
-		Balance:   e.Balance,
+		Balance:   e.Balance.String(),
+		Version:   e.Version,
+		Owner:     e.Owner,
+	}
+
+	if err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.Account{}).
+			Create(&e).Error; err != nil {
+			return err
+		}
+		change := newChange(e, model.ChangeCreated, e.Balance, nil)
+		if err := tx.Create(&change).Error; err != nil {
+			return err
+		}
+		if err := writeAudit(tx, model.AuditEntry{
+			Action:       model.AuditAccountCreate,
+			ResourceType: "account",
+			ResourceID:   strconv.FormatInt(e.AccountID, 10),
+			After:        resp,
+		}); err != nil {
+			return err
+		}
+		return writeEvent(tx, model.EventAccountCreated, e.AccountID, resp)
+	}); err != nil {
+		span.RecordError(err)
+		return err
 	}
 
 	b, _ := json.Marshal(resp)
@@ -78,21 +108,12 @@ func (d *accountDAO) GetAccountByID(ctx context.Context, id int64) (*model.Accou
 	}
 
 	result, err, _ := d.sf.Do(key, func() (interface{}, error) {
-		var e entity.Account
-
-		if err := d.db.WithContext(ctx).
-			Model(&entity.Account{}).
-			Where("account_id = ?", id).
-			First(&e).Error; err != nil {
+		resp, err := d.loadAccount(ctx, id)
+		if err != nil {
 			span.RecordError(err)
 			return nil, err
 		}
 
-		resp := &model.AccountGetResponse{
-			AccountID: e.AccountID,
-			Balance:   e.Balance,
-		}
-
 		b, _ := json.Marshal(resp)
 		if err := d.cache.Set(ctx, key, b, accountTTL).Err(); err != nil {
 			span.RecordError(err)
@@ -108,3 +129,149 @@ func (d *accountDAO) GetAccountByID(ctx context.Context, id int64) (*model.Accou
 
 	return result.(*model.AccountGetResponse), nil
 }
+
+// ReadAccountBalance reads the committed balance from the database, bypassing the cache.
+func (d *accountDAO) ReadAccountBalance(ctx context.Context, id int64) (*model.AccountGetResponse, error) {
+	ctx, span := d.tracer.Start(ctx, "dao.account.read")
+	defer span.End()
+
+	resp, err := d.loadAccount(ctx, id)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetAccountsByIDs reads the accounts that exist among ids from the database in one query.
+func (d *accountDAO) GetAccountsByIDs(ctx context.Context, ids []int64) ([]model.AccountGetResponse, error) {
+	ctx, span := d.tracer.Start(ctx, "dao.account.get_many")
+	defer span.End()
+
+	accounts, err := d.readAccounts(ctx, func(tx *gorm.DB) *gorm.DB {
+		return tx.Where("account_id IN ?", ids)
+	})
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	return accounts, nil
+}
+
+// ListAccounts returns up to limit accounts with an ID above afterID, in account ID order.
+func (d *accountDAO) ListAccounts(ctx context.Context, afterID int64, limit int) ([]model.AccountGetResponse, error) {
+	ctx, span := d.tracer.Start(ctx, "dao.account.list")
+	defer span.End()
+
+	accounts, err := d.readAccounts(ctx, func(tx *gorm.DB) *gorm.DB {
+		return tx.Where("account_id > ?", afterID).Order("account_id").Limit(limit)
+	})
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	return accounts, nil
+}
+
+// readAccounts reads the rows picked by scope and the shard totals of the hot ones in one
+// REPEATABLE READ snapshot, so a hot balance never mixes a row and shards from different commits.
+func (d *accountDAO) readAccounts(ctx context.Context, scope func(*gorm.DB) *gorm.DB) ([]model.AccountGetResponse, error) {
+	var accounts []model.AccountGetResponse
+	err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var rows []entity.Account
+		if err := scope(tx.Model(&entity.Account{})).Find(&rows).Error; err != nil {
+			return err
+		}
+
+		var hot []int64
+		for _, e := range rows {
+			if e.ShardCount > 0 {
+				hot = append(hot, e.AccountID)
+			}
+		}
+		var totals map[int64]decimal.Money
+		if len(hot) > 0 {
+			var err error
+			if totals, err = shardTotals(tx, hot); err != nil {
+				return err
+			}
+		}
+
+		accounts = make([]model.AccountGetResponse, 0, len(rows))
+		for _, e := range rows {
+			balance := e.Balance
+			if e.ShardCount > 0 {
+				balance = balance.Add(totals[e.AccountID])
+			}
+			accounts = append(accounts, model.AccountGetResponse{
+				AccountID: e.AccountID,
+				Balance:   balance.String(),
+				Version:   e.Version,
+				Owner:     e.Owner,
+				Frozen:    e.Frozen,
+			})
+		}
+		return nil
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	return accounts, err
+}
+
+// SetAccountFrozen freezes or unfreezes the account. The row lock waits for transfers already
+// holding it, and every transfer after checks the flag inside its own transaction.
+func (d *accountDAO) SetAccountFrozen(ctx context.Context, id int64, frozen bool) error {
+	ctx, span := d.tracer.Start(ctx, "dao.account.freeze")
+	defer span.End()
+
+	if err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var acc entity.Account
+		if err := tx.Model(&entity.Account{}).
+			Clauses(LockClause).
+			Where("account_id = ?", id).
+			First(&acc).Error; err != nil {
+			return err
+		}
+		if acc.Frozen == frozen {
+			return nil
+		}
+
+		if err := tx.Model(&entity.Account{}).
+			Where("id = ?", acc.ID).
+			Update("frozen", frozen).Error; err != nil {
+			return err
+		}
+
+		action := model.AuditAccountFreeze
+		if !frozen {
+			action = model.AuditAccountUnfreeze
+		}
+		return writeAudit(tx, model.AuditEntry{
+			Action:       action,
+			ResourceType: "account",
+			ResourceID:   strconv.FormatInt(id, 10),
+			Before:       model.AccountFreezeResponse{AccountID: id, Frozen: acc.Frozen},
+			After:        model.AccountFreezeResponse{AccountID: id, Frozen: frozen},
+		})
+	}); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	if err := d.cache.Del(ctx, fmt.Sprintf("account:%d", id)).Err(); err != nil {
+		span.RecordError(err)
+	}
+	return nil
+}
+
+// loadAccount returns the row balance plus the sum of its shards for hot accounts.
+func (d *accountDAO) loadAccount(ctx context.Context, id int64) (*model.AccountGetResponse, error) {
+	accounts, err := d.readAccounts(ctx, func(tx *gorm.DB) *gorm.DB {
+		return tx.Where("account_id = ?", id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(accounts) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &accounts[0], nil
+}
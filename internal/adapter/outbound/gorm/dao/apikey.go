@@ -0,0 +1,163 @@
+package dao
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"txn-processor/internal/adapter/outbound/gorm/entity"
+	"txn-processor/internal/core/model"
+	"txn-processor/internal/port"
+
+	"gorm.io/gorm"
+)
+
+// apiKeyTTL bounds how long a cached key is trusted; revoking also drops it from the cache.
+const apiKeyTTL = time.Minute
+
+type apiKeyDAO struct {
+	*Connections
+}
+
+var _ port.APIKeyDao = (*apiKeyDAO)(nil)
+
+func NewAPIKeyDAO(conn *Connections) port.APIKeyDao {
+	return &apiKeyDAO{Connections: conn}
+}
+
+func (d *apiKeyDAO) CreateAPIKey(ctx context.Context, key model.APIKey) (*model.APIKey, error) {
+	ctx, span := d.tracer.Start(ctx, "dao.apikey.create")
+	defer span.End()
+
+	e := entity.APIKey{
+		KeyID:     key.ID,
+		Hash:      key.Hash,
+		Name:      key.Name,
+		Subject:   key.Subject,
+		Scopes:    strings.Join(key.Scopes, ","),
+		CreatedAt: time.Now(),
+	}
+
+	if err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&e).Error; err != nil {
+			return err
+		}
+		return writeAudit(tx, model.AuditEntry{
+			Action:       model.AuditAPIKeyIssue,
+			ResourceType: "api_key",
+			ResourceID:   e.KeyID,
+			After:        toAPIKey(e),
+		})
+	}); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	res := toAPIKey(e)
+	return &res, nil
+}
+
+// GetAPIKey looks a key up by its public ID, revoked keys included.
+func (d *apiKeyDAO) GetAPIKey(ctx context.Context, id string) (*model.APIKey, error) {
+	ctx, span := d.tracer.Start(ctx, "dao.apikey.get")
+	defer span.End()
+
+	cacheKey := fmt.Sprintf("apikey:%s", id)
+
+	val, err := d.cache.Get(ctx, cacheKey).Result()
+	if err == nil {
+		var cached cachedAPIKey
+		if json.Unmarshal([]byte(val), &cached) == nil {
+			cached.APIKey.Hash = cached.Hash
+			return &cached.APIKey, nil
+		}
+		span.RecordError(fmt.Errorf("cache unmarshal error for key %s", cacheKey))
+	}
+
+	var e entity.APIKey
+	if err := d.db.WithContext(ctx).
+		Where("key_id = ?", id).
+		First(&e).Error; err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	// The hash is left out of the JSON form, so the cache entry carries it separately.
+	res := toAPIKey(e)
+	b, _ := json.Marshal(cachedAPIKey{APIKey: res, Hash: res.Hash})
+	if err := d.cache.Set(ctx, cacheKey, b, apiKeyTTL).Err(); err != nil {
+		span.RecordError(err)
+	}
+
+	return &res, nil
+}
+
+func (d *apiKeyDAO) RevokeAPIKey(ctx context.Context, id string) error {
+	ctx, span := d.tracer.Start(ctx, "dao.apikey.revoke")
+	defer span.End()
+
+	if err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var e entity.APIKey
+		if err := tx.Clauses(LockClause).Where("key_id = ?", id).First(&e).Error; err != nil {
+			return err
+		}
+		if e.RevokedAt != nil {
+			return nil
+		}
+		before := toAPIKey(e)
+
+		now := time.Now()
+		if err := tx.Model(&e).Update("revoked_at", now).Error; err != nil {
+			return err
+		}
+		e.RevokedAt = &now
+		return writeAudit(tx, model.AuditEntry{
+			Action:       model.AuditAPIKeyRevoke,
+			ResourceType: "api_key",
+			ResourceID:   e.KeyID,
+			Before:       before,
+			After:        toAPIKey(e),
+		})
+	}); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	// Should dropping the cache entry fail, replicas stop accepting the key within apiKeyTTL.
+	if err := d.cache.Del(ctx, fmt.Sprintf("apikey:%s", id)).Err(); err != nil {
+		span.RecordError(err)
+	}
+	return nil
+}
+
+// ClaimNonce records a request signature's nonce for ttl. It reports false if the nonce was
+// already claimed, by this replica or another.
+func (d *apiKeyDAO) ClaimNonce(ctx context.Context, keyID, nonce string, ttl time.Duration) (bool, error) {
+	ctx, span := d.tracer.Start(ctx, "dao.apikey.nonce")
+	defer span.End()
+
+	ok, err := d.cache.SetNX(ctx, fmt.Sprintf("nonce:%s:%s", keyID, nonce), 1, ttl).Result()
+	if err != nil {
+		span.RecordError(err)
+		return false, err
+	}
+	return ok, nil
+}
+
+type cachedAPIKey struct {
+	model.APIKey
+	Hash string `json:"hash"`
+}
+
+func toAPIKey(e entity.APIKey) model.APIKey {
+	return model.APIKey{
+		ID:        e.KeyID,
+		Hash:      e.Hash,
+		Name:      e.Name,
+		Subject:   e.Subject,
+		Scopes:    strings.Split(e.Scopes, ","),
+		CreatedAt: e.CreatedAt,
+		RevokedAt: e.RevokedAt,
+	}
+}
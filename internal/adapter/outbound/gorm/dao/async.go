@@ -0,0 +1,142 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"txn-processor/internal/adapter/outbound/gorm/entity"
+	"txn-processor/internal/core/model"
+	"txn-processor/pkg/decimal"
+
+	"gorm.io/gorm"
+)
+
+func (d *transferDAO) CreatePendingTransfer(ctx context.Context, req model.TransferRequest) error {
+	ctx, span := d.tracer.Start(ctx, "dao.transfer.pending.create")
+	defer span.End()
+
+	amount, err := decimal.ParseMoney(req.Amount)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	e := entity.AsyncTransfer{
+		ID:                   req.RequestID,
+		SourceAccountID:      req.SourceAccountID,
+		DestinationAccountID: req.DestinationAccountID,
+		Amount:               amount,
+		Status:               model.TransferPending,
+	}
+
+	if err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.AsyncTransfer{}).
+			Create(&e).Error; err != nil {
+			return err
+		}
+		return writeAudit(tx, model.AuditEntry{
+			Action:       model.AuditTransferSubmit,
+			ResourceType: "transfer_request",
+			ResourceID:   req.RequestID,
+			After:        req,
+		})
+	}); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+// GetTransferStatus reports an async transfer as completed once its Transfer row exists,
+// regardless of whether the worker got to update the pending row.
+func (d *transferDAO) GetTransferStatus(ctx context.Context, requestID string) (*model.TransferStatusResponse, error) {
+	ctx, span := d.tracer.Start(ctx, "dao.transfer.pending.get")
+	defer span.End()
+
+	var e entity.AsyncTransfer
+	if err := d.db.WithContext(ctx).
+		Model(&entity.AsyncTransfer{}).
+		Where("id = ?", requestID).
+		First(&e).Error; err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	resp := &model.TransferStatusResponse{
+		TransferID:           e.ID,
+		Status:               e.Status,
+		SourceAccountID:      e.SourceAccountID,
+		DestinationAccountID: e.DestinationAccountID,
+		FailureReason:        e.FailureReason,
+	}
+
+	if e.Status == model.TransferFailed {
+		return resp, nil
+	}
+
+	tr, err := d.getTransferByRequestID(ctx, requestID)
+	switch {
+	case err == nil:
+		resp.Status = model.TransferCompleted
+		resp.Transfer = tr
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		resp.Status = model.TransferPending
+	default:
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (d *transferDAO) SettlePendingTransfer(ctx context.Context, requestID string, reason string) error {
+	ctx, span := d.tracer.Start(ctx, "dao.transfer.pending.settle")
+	defer span.End()
+
+	status := model.TransferCompleted
+	if reason != "" {
+		status = model.TransferFailed
+	}
+
+	if err := d.db.WithContext(ctx).
+		Model(&entity.AsyncTransfer{}).
+		Where("id = ? AND status = ?", requestID, model.TransferPending).
+		Updates(map[string]interface{}{
+			"status":         status,
+			"failure_reason": reason,
+		}).Error; err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+// ListPendingTransfers returns up to limit async transfers still waiting for a worker with an ID
+// above afterID, in ID order.
+func (d *transferDAO) ListPendingTransfers(ctx context.Context, afterID string, limit int) ([]model.TransferRequest, error) {
+	ctx, span := d.tracer.Start(ctx, "dao.transfer.pending.list")
+	defer span.End()
+
+	var rows []entity.AsyncTransfer
+	if err := d.db.WithContext(ctx).
+		Model(&entity.AsyncTransfer{}).
+		Where("status = ? AND id > ?", model.TransferPending, afterID).
+		Order("id").
+		Limit(limit).
+		Find(&rows).Error; err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	reqs := make([]model.TransferRequest, 0, len(rows))
+	for _, r := range rows {
+		reqs = append(reqs, model.TransferRequest{
+			RequestID:            r.ID,
+			SourceAccountID:      r.SourceAccountID,
+			DestinationAccountID: r.DestinationAccountID,
+			Amount:               r.Amount.String(),
+		})
+	}
+	return reqs, nil
+}
@@ -0,0 +1,182 @@
+package dao
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strconv"
+	"time"
+	"txn-processor/internal/adapter/outbound/gorm/entity"
+	"txn-processor/internal/core/model"
+	"txn-processor/internal/port"
+	"txn-processor/pkg/audit"
+	"txn-processor/pkg/decimal"
+	"txn-processor/pkg/tracing"
+
+	"gorm.io/gorm"
+)
+
+// auditDAO only appends and reads: there is deliberately no update or delete path.
+type auditDAO struct {
+	*Connections
+}
+
+var _ port.AuditDao = (*auditDAO)(nil)
+
+func NewAuditDAO(conn *Connections) port.AuditDao {
+	return &auditDAO{Connections: conn}
+}
+
+// AppendAudit records an operation that has no transaction of its own to join.
+func (d *auditDAO) AppendAudit(ctx context.Context, e model.AuditEntry) error {
+	ctx, span := d.tracer.Start(ctx, "dao.audit.append")
+	defer span.End()
+
+	if err := writeAudit(d.db.WithContext(ctx), e); err != nil {
+		span.RecordError(err)
+		return err
+	}
+	return nil
+}
+
+func (d *auditDAO) ListAudit(ctx context.Context, f model.AuditFilter) ([]model.AuditRecord, error) {
+	ctx, span := d.tracer.Start(ctx, "dao.audit.list")
+	defer span.End()
+
+	q := d.db.WithContext(ctx).Model(&entity.AuditRecord{})
+	if f.Action != "" {
+		q = q.Where("action = ?", f.Action)
+	}
+	if f.ResourceType != "" {
+		q = q.Where("resource_type = ?", f.ResourceType)
+	}
+	if f.ResourceID != "" {
+		q = q.Where("resource_id = ?", f.ResourceID)
+	}
+	if f.Actor != "" {
+		q = q.Where("actor = ?", f.Actor)
+	}
+	if f.BeforeID > 0 {
+		q = q.Where("id < ?", f.BeforeID)
+	}
+
+	var rows []entity.AuditRecord
+	if err := q.Order("id DESC").Limit(f.Limit).Find(&rows).Error; err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	records := make([]model.AuditRecord, 0, len(rows))
+	for _, r := range rows {
+		rec := model.AuditRecord{
+			ID:            int64(r.ID),
+			Action:        r.Action,
+			ResourceType:  r.ResourceType,
+			ResourceID:    r.ResourceID,
+			Actor:         r.Actor,
+			CorrelationID: r.CorrelationID,
+			SourceIP:      r.SourceIP,
+			TraceID:       r.TraceID,
+			CreatedAt:     r.CreatedAt,
+		}
+		if r.Before != "" {
+			rec.Before = json.RawMessage(r.Before)
+		}
+		if r.After != "" {
+			rec.After = json.RawMessage(r.After)
+		}
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+// writeAudit appends an audit record inside the caller's transaction, taking the actor and
+// trace from the statement's context.
+func writeAudit(tx *gorm.DB, e model.AuditEntry) error {
+	ctx := tx.Statement.Context
+	actor := audit.FromContext(ctx)
+
+	before, err := auditJSON(e.Before)
+	if err != nil {
+		return err
+	}
+	after, err := auditJSON(e.After)
+	if err != nil {
+		return err
+	}
+
+	return tx.Create(&entity.AuditRecord{
+		Action:        e.Action,
+		ResourceType:  e.ResourceType,
+		ResourceID:    e.ResourceID,
+		Actor:         actor.ID,
+		CorrelationID: actor.CorrelationID,
+		SourceIP:      actor.SourceIP,
+		TraceID:       tracing.TraceID(ctx),
+		Before:        before,
+		After:         after,
+		CreatedAt:     time.Now(),
+	}).Error
+}
+
+func auditJSON(v any) (string, error) {
+	if v == nil {
+		return "", nil
+	}
+	b, err := json.Marshal(v)
+	return string(b), err
+}
+
+// auditAccount is an account's state in an audit record. Hot accounts only carry their ID
+// because their total is not known inside a transfer.
+type auditAccount struct {
+	AccountID int64  `json:"account_id"`
+	Balance   string `json:"balance,omitempty"`
+	Version   int64  `json:"version,omitempty"`
+}
+
+type transferAuditState struct {
+	Transfer    *model.TransferResponse `json:"transfer,omitempty"`
+	Source      auditAccount            `json:"source"`
+	Destination auditAccount            `json:"destination"`
+}
+
+// transferAudit describes a committed transfer from the post-transfer rows of both accounts.
+func transferAudit(record entity.Transfer, source, dest entity.Account) model.AuditEntry {
+	srcBefore, srcAfter := auditAccounts(source, record.Amount.Neg())
+	dstBefore, dstAfter := auditAccounts(dest, record.Amount)
+
+	return model.AuditEntry{
+		Action:       model.AuditTransferCreate,
+		ResourceType: "transfer",
+		ResourceID:   strconv.FormatUint(uint64(record.ID), 10),
+		Before:       transferAuditState{Source: srcBefore, Destination: dstBefore},
+		After:        transferAuditState{Transfer: toTransferResponse(record), Source: srcAfter, Destination: dstAfter},
+	}
+}
+
+// auditAccounts returns an account before and after a change of delta, given its post-change row.
+func auditAccounts(acc entity.Account, delta decimal.Money) (auditAccount, auditAccount) {
+	before, after := auditAccount{AccountID: acc.AccountID}, auditAccount{AccountID: acc.AccountID}
+	if acc.ShardCount == 0 {
+		after.Balance, after.Version = acc.Balance.String(), acc.Version
+		before.Balance, before.Version = acc.Balance.Sub(delta).String(), acc.Version-1
+	}
+	return before, after
+}
+
+// protectAuditLog installs triggers that reject UPDATE and DELETE on the audit log. Creating
+// triggers can need privileges the service account lacks, so failing only logs a warning.
+func protectAuditLog(db *gorm.DB) {
+	for _, stmt := range []string{
+		"CREATE TRIGGER IF NOT EXISTS audit_records_no_update BEFORE UPDATE ON audit_records " +
+			"FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit records are append-only'",
+		"CREATE TRIGGER IF NOT EXISTS audit_records_no_delete BEFORE DELETE ON audit_records " +
+			"FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit records are append-only'",
+	} {
+		if err := db.Exec(stmt).Error; err != nil {
+			slog.WarnContext(db.Statement.Context, "could not protect audit log", "error", err)
+			return
+		}
+	}
+}
@@ -0,0 +1,299 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+	"txn-processor/config"
+	"txn-processor/internal/adapter/outbound/gorm/entity"
+	"txn-processor/internal/core/model"
+	"txn-processor/pkg/decimal"
+	"txn-processor/pkg/tracing"
+
+	"gorm.io/gorm"
+)
+
+var errCommitterClosed = errors.New("group commit is shut down")
+
+type transferJob struct {
+	ctx    context.Context
+	req    model.TransferRequest
+	amount decimal.Money
+	done   chan transferOutcome
+}
+
+type transferOutcome struct {
+	result *transferResult
+	err    error
+}
+
+// groupCommitter collects transfers for a short window and applies them in one transaction.
+type groupCommitter struct {
+	db       *gorm.DB
+	tracer   tracing.Tracer
+	fallback lockStrategy
+	window   time.Duration
+	maxBatch int
+	jobs     chan *transferJob
+
+	// mu guards closed: submit holds it shared while queueing, close exclusively to close jobs.
+	mu     sync.RWMutex
+	closed bool
+	done   chan struct{}
+}
+
+func newGroupCommitter(db *gorm.DB, tracer tracing.Tracer, fallback lockStrategy, conf config.GroupCommit) *groupCommitter {
+	g := &groupCommitter{
+		db:       db,
+		tracer:   tracer,
+		fallback: fallback,
+		window:   time.Duration(max(conf.WindowMs, 1)) * time.Millisecond,
+		maxBatch: max(conf.MaxBatch, 1),
+		jobs:     make(chan *transferJob, max(conf.MaxBatch, 1)),
+		done:     make(chan struct{}),
+	}
+	go g.run()
+	return g
+}
+
+// submit queues a transfer and blocks until its batch is committed.
+// Once queued the caller always waits, so a cancelled request never hides a committed transfer.
+func (g *groupCommitter) submit(ctx context.Context, req model.TransferRequest, amount decimal.Money) (*transferResult, error) {
+	job := &transferJob{ctx: ctx, req: req, amount: amount, done: make(chan transferOutcome, 1)}
+
+	g.mu.RLock()
+	if g.closed {
+		g.mu.RUnlock()
+		return nil, errCommitterClosed
+	}
+	select {
+	case g.jobs <- job:
+	case <-ctx.Done():
+		g.mu.RUnlock()
+		return nil, ctx.Err()
+	}
+	g.mu.RUnlock()
+
+	out := <-job.done
+	return out.result, out.err
+}
+
+// close stops taking transfers and waits until the queued ones are committed or ctx is done.
+func (g *groupCommitter) close(ctx context.Context) error {
+	g.mu.Lock()
+	if !g.closed {
+		g.closed = true
+		close(g.jobs)
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-g.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (g *groupCommitter) run() {
+	defer close(g.done)
+	for first := range g.jobs {
+		batch := []*transferJob{first}
+		timer := time.NewTimer(g.window)
+
+	collect:
+		for len(batch) < g.maxBatch {
+			select {
+			case job, ok := <-g.jobs:
+				if !ok {
+					break collect
+				}
+				batch = append(batch, job)
+			case <-timer.C:
+				break collect
+			}
+		}
+		timer.Stop()
+
+		g.flush(batch)
+	}
+}
+
+func (g *groupCommitter) flush(batch []*transferJob) {
+	ctx, span := g.tracer.Start(context.Background(), "dao.transfer.batch")
+	defer span.End()
+
+	live := batch[:0]
+	for _, job := range batch {
+		if err := job.ctx.Err(); err != nil {
+			job.done <- transferOutcome{err: err}
+			continue
+		}
+		live = append(live, job)
+	}
+	if len(live) == 0 {
+		return
+	}
+
+	span.SetAttributes("batch.size", len(live))
+
+	outcomes, err := g.apply(ctx, live)
+	if err != nil {
+		// The batch failed before its commit was sent, so nothing was applied: run each
+		// transfer on its own and let only the bad one fail.
+		span.RecordError(err)
+		for _, job := range live {
+			result, err := g.fallback.transfer(job.ctx, g.db, job.req, job.amount)
+			job.done <- transferOutcome{result: result, err: err}
+		}
+		return
+	}
+
+	for i, job := range live {
+		job.done <- outcomes[i]
+	}
+}
+
+// apply locks every account in the batch in account_id order, replays the transfers in memory
+// and writes the final balances once. Business failures are reported per job and skipped.
+func (g *groupCommitter) apply(ctx context.Context, jobs []*transferJob) ([]transferOutcome, error) {
+	ids := make([]int64, 0, len(jobs)*2)
+	for _, job := range jobs {
+		ids = append(ids, job.req.SourceAccountID, job.req.DestinationAccountID)
+	}
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+
+	tx := g.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	var accounts []entity.Account
+	if err := tx.Model(&entity.Account{}).
+		Clauses(LockClause).
+		Where("account_id IN ?", ids).
+		Order("account_id").
+		Find(&accounts).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	byID := make(map[int64]*entity.Account, len(accounts))
+	for i := range accounts {
+		byID[accounts[i].AccountID] = &accounts[i]
+	}
+
+	outcomes := make([]transferOutcome, len(jobs))
+	records := make([]entity.Transfer, 0, len(jobs))
+	changes := make([]entity.AccountChange, 0, len(jobs)*2)
+	snapshots := make([][2]entity.Account, 0, len(jobs))
+	applied := make([]int, 0, len(jobs))
+	dirty := make(map[int64]bool)
+
+	for i, job := range jobs {
+		source, ok := byID[job.req.SourceAccountID]
+		dest, ok2 := byID[job.req.DestinationAccountID]
+		if !ok || !ok2 {
+			outcomes[i].err = gorm.ErrRecordNotFound
+			continue
+		}
+
+		// Sharded after hasHotAccount looked; the caller retries it as a sharded transfer.
+		if source.ShardCount > 0 || dest.ShardCount > 0 {
+			outcomes[i].err = errHotAccount
+			continue
+		}
+
+		if source.Frozen || dest.Frozen {
+			outcomes[i].err = ErrAccountFrozen
+			continue
+		}
+
+		if source.Balance.LessThan(job.amount) {
+			outcomes[i].err = ErrInsufficientBalance
+			continue
+		}
+
+		source.Balance = source.Balance.Sub(job.amount)
+		source.Version++
+		dest.Balance = dest.Balance.Add(job.amount)
+		dest.Version++
+		dirty[source.AccountID] = true
+		dirty[dest.AccountID] = true
+
+		// Each transfer gets its own change entries with the intermediate balances.
+		records = append(records, newTransferRecord(job.req, job.amount))
+		changes = append(changes,
+			newChange(*source, model.ChangeDebit, job.amount.Neg(), nil),
+			newChange(*dest, model.ChangeCredit, job.amount, nil),
+		)
+		snapshots = append(snapshots, [2]entity.Account{*source, *dest})
+		applied = append(applied, i)
+	}
+
+	for _, acc := range accounts {
+		if !dirty[acc.AccountID] {
+			continue
+		}
+		if err := tx.Model(&entity.Account{}).
+			Where("id = ?", acc.ID).
+			Updates(map[string]interface{}{
+				"balance": acc.Balance,
+				"version": acc.Version,
+			}).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if len(records) > 0 {
+		if err := tx.Create(&records).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		for k := range records {
+			changes[2*k].TransferID = &records[k].ID
+			changes[2*k+1].TransferID = &records[k].ID
+		}
+		if err := tx.Create(&changes).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		// Each audit record carries the actor of the request that asked for the transfer.
+		for k, i := range applied {
+			jtx := tx.WithContext(context.WithoutCancel(jobs[i].ctx))
+			if err := writeAudit(jtx, transferAudit(records[k], snapshots[k][0], snapshots[k][1])); err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+		}
+		if err := writeTransferEvents(tx, records); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	// A failed commit may still have landed, so a retry could apply a transfer twice.
+	// Every transfer in the batch gets the commit error as it is.
+	if err := tx.Commit().Error; err != nil {
+		for _, i := range applied {
+			outcomes[i].err = err
+		}
+		return outcomes, nil
+	}
+
+	// Every caller caches the batch's final balances, so no one writes an intermediate state.
+	for k, i := range applied {
+		job := jobs[i]
+		outcomes[i].result = &transferResult{
+			record: records[k],
+			source: *byID[job.req.SourceAccountID],
+			dest:   *byID[job.req.DestinationAccountID],
+		}
+	}
+
+	return outcomes, nil
+}
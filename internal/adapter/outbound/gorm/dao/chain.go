@@ -0,0 +1,287 @@
+package dao
+
+import (
+	"context"
+	"crypto/ed25519"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"txn-processor/internal/adapter/outbound/gorm/entity"
+	"txn-processor/internal/core/model"
+	"txn-processor/internal/port"
+	"txn-processor/pkg/hashchain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	chainHeadID = 1
+	verifyBatch = 1000
+)
+
+type chainDAO struct {
+	*Connections
+}
+
+var _ port.ChainDao = (*chainDAO)(nil)
+
+func NewChainDAO(conn *Connections) port.ChainDao {
+	return &chainDAO{Connections: conn}
+}
+
+// LinkTransfers chains unlinked transfers in ID order under the head's row lock. A transfer
+// that commits after a later ID was linked simply joins the chain further on: chain order is
+// the order the chainer saw commits, and verification walks ChainSeq.
+func (d *chainDAO) LinkTransfers(ctx context.Context, limit int) (model.ChainHead, int, error) {
+	ctx, span := d.tracer.Start(ctx, "dao.chain.link")
+	defer span.End()
+
+	var head entity.ChainHead
+	linked := 0
+	err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(LockClause).
+			Where("id = ?", chainHeadID).
+			First(&head).Error; err != nil {
+			return err
+		}
+
+		// Soft-deleted transfers are chained too, so verification can flag them.
+		var transfers []entity.Transfer
+		if err := tx.Unscoped().
+			Where("chain_seq IS NULL").
+			Order("id").
+			Limit(limit).
+			Find(&transfers).Error; err != nil {
+			return err
+		}
+		if len(transfers) == 0 {
+			return nil
+		}
+
+		for _, t := range transfers {
+			seq := head.Seq + 1
+			hash := hashchain.Link(head.Hash, canonicalTransfer(t))
+			if err := tx.Unscoped().
+				Model(&entity.Transfer{}).
+				Where("id = ? AND chain_seq IS NULL", t.ID).
+				UpdateColumns(map[string]any{"chain_seq": seq, "prev_hash": head.Hash, "hash": hash}).Error; err != nil {
+				return err
+			}
+			head.Seq, head.Hash = seq, hash
+		}
+		linked = len(transfers)
+
+		return tx.Model(&entity.ChainHead{}).
+			Where("id = ?", chainHeadID).
+			Updates(map[string]any{"seq": head.Seq, "hash": head.Hash}).Error
+	})
+	if err != nil {
+		span.RecordError(err)
+		return model.ChainHead{}, 0, err
+	}
+
+	span.SetAttributes("chain.linked", linked)
+	return model.ChainHead{Seq: head.Seq, Hash: head.Hash}, linked, nil
+}
+
+func (d *chainDAO) LatestCheckpoint(ctx context.Context) (*model.ChainCheckpoint, error) {
+	ctx, span := d.tracer.Start(ctx, "dao.chain.checkpoint.latest")
+	defer span.End()
+
+	var e entity.ChainCheckpoint
+	err := d.db.WithContext(ctx).Order("seq DESC").First(&e).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	c := toCheckpoint(e)
+	return &c, nil
+}
+
+// CreateCheckpoint stores a signed checkpoint. Another replica may already have signed the
+// same head, in which case its checkpoint is kept.
+func (d *chainDAO) CreateCheckpoint(ctx context.Context, c model.ChainCheckpoint) error {
+	ctx, span := d.tracer.Start(ctx, "dao.chain.checkpoint.create")
+	defer span.End()
+
+	if err := d.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&entity.ChainCheckpoint{
+			Seq:       c.Seq,
+			Hash:      c.Hash,
+			KeyID:     c.KeyID,
+			Signature: c.Signature,
+			CreatedAt: c.CreatedAt,
+		}).Error; err != nil {
+		span.RecordError(err)
+		return err
+	}
+	return nil
+}
+
+// canonicalTransfer is the content a transfer's hash covers. Field order is fixed by the
+// struct, and amounts always carry four decimals so equal values hash equally.
+func canonicalTransfer(t entity.Transfer) []byte {
+	content := struct {
+		ID                   uint   `json:"id"`
+		RequestID            string `json:"request_id"`
+		SourceAccountID      int64  `json:"source_account_id"`
+		DestinationAccountID int64  `json:"destination_account_id"`
+		Amount               string `json:"amount"`
+		CreatedAt            int64  `json:"created_at"`
+	}{
+		ID:                   t.ID,
+		SourceAccountID:      t.SourceAccountID,
+		DestinationAccountID: t.DestinationAccountID,
+		Amount:               t.Amount.StringFixed(4),
+		CreatedAt:            t.CreatedAt.UTC().UnixMilli(),
+	}
+	if t.RequestID != nil {
+		content.RequestID = *t.RequestID
+	}
+
+	b, _ := json.Marshal(content)
+	return b
+}
+
+func toCheckpoint(e entity.ChainCheckpoint) model.ChainCheckpoint {
+	return model.ChainCheckpoint{
+		Seq:       e.Seq,
+		Hash:      e.Hash,
+		KeyID:     e.KeyID,
+		Signature: e.Signature,
+		CreatedAt: e.CreatedAt,
+	}
+}
+
+// ChainBreak is the first link that does not verify.
+type ChainBreak struct {
+	Seq        int64
+	TransferID uint
+	Reason     string
+}
+
+type ChainReport struct {
+	Links       int64
+	Checkpoints int
+	Break       *ChainBreak
+}
+
+// VerifyChain walks the transfer chain from the start, recomputing every hash and checking
+// each checkpoint's hash and, when pub is set, its signature. It stops at the first break.
+// The walk reads one snapshot, so links added meanwhile are not mistaken for a bad head.
+func VerifyChain(ctx context.Context, pub ed25519.PublicKey) (*ChainReport, error) {
+	conn, err := GetConnections()
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get DB connections", "error", err)
+		return nil, err
+	}
+
+	var report *ChainReport
+	err = conn.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		report, err = verifyChain(tx, pub)
+		return err
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func verifyChain(db *gorm.DB, pub ed25519.PublicKey) (*ChainReport, error) {
+	var rows []entity.ChainCheckpoint
+	if err := db.Order("seq").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	checkpoints := make(map[int64]entity.ChainCheckpoint, len(rows))
+	for _, c := range rows {
+		checkpoints[c.Seq] = c
+	}
+
+	report := &ChainReport{}
+	fail := func(seq int64, id uint, reason string) (*ChainReport, error) {
+		report.Break = &ChainBreak{Seq: seq, TransferID: id, Reason: reason}
+		return report, nil
+	}
+
+	prev := hashchain.Genesis
+	seq := int64(0)
+	for {
+		var transfers []entity.Transfer
+		if err := db.Unscoped().
+			Where("chain_seq > ?", seq).
+			Order("chain_seq").
+			Limit(verifyBatch).
+			Find(&transfers).Error; err != nil {
+			return nil, err
+		}
+
+		for _, t := range transfers {
+			seq++
+			switch {
+			case *t.ChainSeq != seq:
+				return fail(seq, 0, "link missing")
+			case t.DeletedAt.Valid:
+				return fail(seq, t.ID, "transfer deleted")
+			case t.PrevHash != prev:
+				return fail(seq, t.ID, "previous hash does not match")
+			}
+
+			hash := hashchain.Link(prev, canonicalTransfer(t))
+			if hash != t.Hash {
+				return fail(seq, t.ID, "content does not match hash")
+			}
+
+			if c, ok := checkpoints[seq]; ok {
+				if c.Hash != hash {
+					return fail(seq, t.ID, "checkpoint hash does not match")
+				}
+				if pub != nil && !hashchain.Verify(pub, checkpointOf(c), c.Signature) {
+					return fail(seq, t.ID, "checkpoint signature invalid")
+				}
+				report.Checkpoints++
+			}
+
+			prev = hash
+			report.Links++
+		}
+
+		if len(transfers) < verifyBatch {
+			break
+		}
+	}
+
+	// A checkpoint past the last link means the end of the chain was cut off.
+	for _, c := range rows {
+		if c.Seq > seq {
+			return fail(c.Seq, 0, "checkpoint beyond end of chain")
+		}
+	}
+
+	var head entity.ChainHead
+	if err := db.Where("id = ?", chainHeadID).First(&head).Error; err != nil {
+		return nil, err
+	}
+	if head.Seq != seq || head.Hash != prev {
+		return fail(head.Seq, 0, "chain head does not match last link")
+	}
+
+	return report, nil
+}
+
+func checkpointOf(c entity.ChainCheckpoint) hashchain.Checkpoint {
+	return hashchain.Checkpoint{Seq: c.Seq, Hash: c.Hash, KeyID: c.KeyID, CreatedAt: c.CreatedAt}
+}
+
+// seedChainHead creates the head row pointing at the genesis hash.
+func seedChainHead(db *gorm.DB) error {
+	return db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&entity.ChainHead{ID: chainHeadID, Hash: hashchain.Genesis}).Error
+}
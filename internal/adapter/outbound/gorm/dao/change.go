@@ -0,0 +1,153 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"txn-processor/internal/adapter/outbound/gorm/entity"
+	"txn-processor/internal/core/model"
+	"txn-processor/pkg/decimal"
+
+	"gorm.io/gorm"
+)
+
+// newChange builds a change feed entry from the post-change row. Hot accounts are left
+// unnumbered: their transfers never write the row, so the sequencer numbers them later.
+func newChange(acc entity.Account, kind string, amount decimal.Money, transferID *uint) entity.AccountChange {
+	c := entity.AccountChange{
+		AccountID:  acc.AccountID,
+		Kind:       kind,
+		Amount:     amount,
+		TransferID: transferID,
+	}
+	if acc.ShardCount == 0 {
+		version, balance := acc.Version, acc.Balance
+		c.Version = &version
+		c.Balance = &balance
+	}
+	return c
+}
+
+// writeTransferChanges records the debit and the credit of a committed transfer.
+func writeTransferChanges(tx *gorm.DB, record entity.Transfer, source, dest entity.Account) error {
+	changes := []entity.AccountChange{
+		newChange(source, model.ChangeDebit, record.Amount.Neg(), &record.ID),
+		newChange(dest, model.ChangeCredit, record.Amount, &record.ID),
+	}
+	return tx.Create(&changes).Error
+}
+
+// sequenceAccount numbers the account's pending changes in commit order and moves its version
+// past them. The row lock orders it against transfers and SetAccountShards.
+func sequenceAccount(tx *gorm.DB, accountID int64) (int, error) {
+	var acc entity.Account
+	if err := tx.Model(&entity.Account{}).
+		Clauses(LockClause).
+		Where("account_id = ?", accountID).
+		First(&acc).Error; err != nil {
+		return 0, err
+	}
+
+	var pending []entity.AccountChange
+	if err := tx.Model(&entity.AccountChange{}).
+		Clauses(LockClause).
+		Where("account_id = ? AND version IS NULL", accountID).
+		Order("id").
+		Find(&pending).Error; err != nil {
+		return 0, err
+	}
+	if len(pending) == 0 {
+		return 0, nil
+	}
+
+	for i, c := range pending {
+		if err := tx.Model(&entity.AccountChange{}).
+			Where("id = ?", c.ID).
+			Update("version", acc.Version+int64(i+1)).Error; err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Model(&entity.Account{}).
+		Where("id = ?", acc.ID).
+		Update("version", acc.Version+int64(len(pending))).Error; err != nil {
+		return 0, err
+	}
+
+	return len(pending), nil
+}
+
+// SequenceChanges numbers pending changes for up to limit accounts, one short transaction each.
+func (d *accountDAO) SequenceChanges(ctx context.Context, limit int) (int, error) {
+	ctx, span := d.tracer.Start(ctx, "dao.account.sequence")
+	defer span.End()
+
+	var ids []int64
+	if err := d.db.WithContext(ctx).
+		Model(&entity.AccountChange{}).
+		Where("version IS NULL").
+		Distinct("account_id").
+		Limit(limit).
+		Pluck("account_id", &ids).Error; err != nil {
+		span.RecordError(err)
+		return 0, err
+	}
+
+	total := 0
+	for _, id := range ids {
+		var n int
+		if err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			var err error
+			n, err = sequenceAccount(tx, id)
+			return err
+		}); err != nil {
+			span.RecordError(err)
+			return total, err
+		}
+		total += n
+
+		// The cached account carries the version it had before sequencing.
+		if n > 0 {
+			if err := d.cache.Del(ctx, fmt.Sprintf("account:%d", id)).Err(); err != nil {
+				span.RecordError(err)
+			}
+		}
+	}
+
+	span.SetAttributes("changes.sequenced", total)
+	return total, nil
+}
+
+// ListAccountChanges returns numbered changes with a version above since, oldest first.
+func (d *accountDAO) ListAccountChanges(ctx context.Context, id int64, since int64, limit int) ([]model.AccountChange, error) {
+	ctx, span := d.tracer.Start(ctx, "dao.account.changes")
+	defer span.End()
+
+	var rows []entity.AccountChange
+	if err := d.db.WithContext(ctx).
+		Model(&entity.AccountChange{}).
+		Where("account_id = ? AND version > ?", id, since).
+		Order("version").
+		Limit(limit).
+		Find(&rows).Error; err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	changes := make([]model.AccountChange, 0, len(rows))
+	for _, r := range rows {
+		c := model.AccountChange{
+			Version:   *r.Version,
+			Kind:      r.Kind,
+			Amount:    r.Amount.String(),
+			CreatedAt: r.CreatedAt,
+		}
+		if r.Balance != nil {
+			c.Balance = r.Balance.String()
+		}
+		if r.TransferID != nil {
+			c.TransferID = int64(*r.TransferID)
+		}
+		changes = append(changes, c)
+	}
+	return changes, nil
+}
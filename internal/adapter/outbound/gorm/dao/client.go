@@ -100,6 +100,16 @@ func GetConnections() (*Connections, error) {
 	return connInst, nil
 }
 
+// Redis exposes the shared Redis client to other outbound adapters.
+func (c *Connections) Redis() *redis.Client {
+	return c.cache
+}
+
+// DB exposes the shared database handle to maintenance commands and tests.
+func (c *Connections) DB() *gorm.DB {
+	return c.db
+}
+
 func (c *Connections) Close(ctx context.Context) error {
 	var errs []error
 
@@ -13,6 +13,12 @@ type Dao struct {
 	port.HealthDao
 	port.AccountDao
 	port.TransferDao
+	port.OutboxDao
+	port.WebhookDao
+	port.AuditDao
+	port.ChainDao
+	port.APIKeyDao
+	port.ImportDao
 }
 
 var _ port.Outbound = new(Dao)
@@ -24,13 +30,34 @@ func New(ctx context.Context, db config.DB, cache config.Cache, tracer tracing.T
 		return nil, err
 	}
 
+	transferDao, err := NewTransferDAO(conn, db)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to create transfer DAO", "error", err)
+		return nil, err
+	}
+
 	return &Dao{
 		HealthDao:   NewHealthDAO(conn),
 		AccountDao:  NewAccountDAO(conn),
-		TransferDao: NewTransferDAO(conn),
+		TransferDao: transferDao,
+		OutboxDao:   NewOutboxDAO(conn),
+		WebhookDao:  NewWebhookDAO(conn),
+		AuditDao:    NewAuditDAO(conn),
+		ChainDao:    NewChainDAO(conn),
+		APIKeyDao:   NewAPIKeyDAO(conn),
+		ImportDao:   NewImportDAO(conn),
 	}, nil
 }
 
+// Shutdown stops the DAOs' background work. It runs after the services stop and before the
+// connections close.
+func (d *Dao) Shutdown(ctx context.Context) error {
+	if stopper, ok := d.TransferDao.(interface{ Shutdown(context.Context) error }); ok {
+		return stopper.Shutdown(ctx)
+	}
+	return nil
+}
+
 func AutoMigrate(ctx context.Context) error {
 	conn, err := GetConnections()
 	if err != nil {
@@ -40,11 +67,38 @@ func AutoMigrate(ctx context.Context) error {
 
 	if err := conn.db.AutoMigrate(
 		&entity.Account{},
+		&entity.AccountShard{},
+		&entity.AccountGrant{},
+		&entity.AccountChange{},
+		&entity.AccountProjection{},
+		&entity.DailyBalance{},
 		&entity.Transfer{},
+		&entity.AsyncTransfer{},
+		&entity.OutboxEvent{},
+		&entity.WebhookSubscription{},
+		&entity.WebhookDelivery{},
+		&entity.AuditRecord{},
+		&entity.ChainHead{},
+		&entity.ChainCheckpoint{},
+		&entity.APIKey{},
+		&entity.ImportJob{},
+		&entity.ImportRow{},
 	); err != nil {
 		slog.ErrorContext(ctx, "failed to migrate entities", "error", err)
 		return err
 	}
 
+	protectAuditLog(conn.db.WithContext(ctx))
+
+	if err := seedOpeningChanges(conn.db.WithContext(ctx)); err != nil {
+		slog.ErrorContext(ctx, "failed to seed opening changes", "error", err)
+		return err
+	}
+
+	if err := seedChainHead(conn.db.WithContext(ctx)); err != nil {
+		slog.ErrorContext(ctx, "failed to seed chain head", "error", err)
+		return err
+	}
+
 	return nil
 }
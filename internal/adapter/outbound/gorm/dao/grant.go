@@ -0,0 +1,129 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+	"txn-processor/internal/adapter/outbound/gorm/entity"
+	"txn-processor/internal/core/model"
+
+	"gorm.io/gorm"
+)
+
+// AccountAccess reports how subject may act on the account. It reads the database rather than
+// the cache, so a revoked grant takes effect at once.
+func (d *accountDAO) AccountAccess(ctx context.Context, id int64, subject string) (string, error) {
+	ctx, span := d.tracer.Start(ctx, "dao.account.access")
+	defer span.End()
+
+	var acc entity.Account
+	if err := d.db.WithContext(ctx).
+		Select("owner").
+		Where("account_id = ?", id).
+		First(&acc).Error; err != nil {
+		span.RecordError(err)
+		return model.AccessNone, err
+	}
+	if subject != "" && acc.Owner == subject {
+		return model.AccessOwner, nil
+	}
+
+	var grants int64
+	if err := d.db.WithContext(ctx).
+		Model(&entity.AccountGrant{}).
+		Where("account_id = ? AND subject = ?", id, subject).
+		Count(&grants).Error; err != nil {
+		span.RecordError(err)
+		return model.AccessNone, err
+	}
+	if grants > 0 {
+		return model.AccessDelegate, nil
+	}
+	return model.AccessNone, nil
+}
+
+// AccessibleAccounts returns the accounts among ids that subject owns or has been granted.
+func (d *accountDAO) AccessibleAccounts(ctx context.Context, subject string, ids []int64) ([]int64, error) {
+	ctx, span := d.tracer.Start(ctx, "dao.account.accessible")
+	defer span.End()
+
+	var accessible []int64
+	if subject == "" {
+		return accessible, nil
+	}
+	if err := d.db.WithContext(ctx).
+		Model(&entity.Account{}).
+		Where("account_id IN ?", ids).
+		Where(d.db.Where("owner = ?", subject).
+			Or("account_id IN (?)", d.db.Model(&entity.AccountGrant{}).Select("account_id").Where("subject = ?", subject))).
+		Pluck("account_id", &accessible).Error; err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	return accessible, nil
+}
+
+// GrantAccount delegates the account to subject. Granting twice is a no-op.
+func (d *accountDAO) GrantAccount(ctx context.Context, id int64, subject string) error {
+	ctx, span := d.tracer.Start(ctx, "dao.account.grant")
+	defer span.End()
+
+	if err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(LockClause).
+			Where("account_id = ?", id).
+			First(&entity.Account{}).Error; err != nil {
+			return err
+		}
+
+		var existing entity.AccountGrant
+		err := tx.Where("account_id = ? AND subject = ?", id, subject).First(&existing).Error
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		if err := tx.Create(&entity.AccountGrant{AccountID: id, Subject: subject, CreatedAt: time.Now()}).Error; err != nil {
+			return err
+		}
+		return writeAudit(tx, model.AuditEntry{
+			Action:       model.AuditAccountGrant,
+			ResourceType: "account",
+			ResourceID:   strconv.FormatInt(id, 10),
+			After:        map[string]string{"subject": subject},
+		})
+	}); err != nil {
+		span.RecordError(err)
+		return err
+	}
+	return nil
+}
+
+// RevokeAccountGrant removes subject's delegation. It fails with gorm.ErrRecordNotFound if
+// there was none.
+func (d *accountDAO) RevokeAccountGrant(ctx context.Context, id int64, subject string) error {
+	ctx, span := d.tracer.Start(ctx, "dao.account.revoke")
+	defer span.End()
+
+	if err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("account_id = ? AND subject = ?", id, subject).Delete(&entity.AccountGrant{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return writeAudit(tx, model.AuditEntry{
+			Action:       model.AuditAccountRevoke,
+			ResourceType: "account",
+			ResourceID:   strconv.FormatInt(id, 10),
+			Before:       map[string]string{"subject": subject},
+		})
+	}); err != nil {
+		span.RecordError(err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,290 @@
+package dao
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"time"
+	"txn-processor/internal/adapter/outbound/gorm/entity"
+	"txn-processor/internal/core/model"
+	"txn-processor/internal/port"
+	"txn-processor/pkg/decimal"
+
+	"gorm.io/gorm"
+)
+
+// ErrImportMoved refuses a chunk that does not start where its job stands, which happens when
+// two uploads of one job run at once.
+var ErrImportMoved = errors.New("import job moved past the chunk")
+
+// importResultBatch bounds the rows of one multi-row INSERT of results.
+const importResultBatch = 1000
+
+type importDAO struct {
+	*Connections
+}
+
+var _ port.ImportDao = (*importDAO)(nil)
+
+func NewImportDAO(conn *Connections) port.ImportDao {
+	return &importDAO{Connections: conn}
+}
+
+// StartImport marks an existing job of kind running again; a job of another kind is returned
+// unchanged for the caller to refuse.
+func (d *importDAO) StartImport(ctx context.Context, id, kind string) (*model.ImportJob, error) {
+	ctx, span := d.tracer.Start(ctx, "dao.import.start")
+	defer span.End()
+
+	var e entity.ImportJob
+	if err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(LockClause).Where("id = ?", id).First(&e).Error
+		switch {
+		case err == nil:
+			if e.Kind != kind || e.Status == model.ImportRunning {
+				return nil
+			}
+			e.Status = model.ImportRunning
+			return tx.Model(&e).Update("status", e.Status).Error
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			e = entity.ImportJob{ID: id, Kind: kind, Status: model.ImportRunning}
+			if err := tx.Create(&e).Error; err != nil {
+				return err
+			}
+			return writeAudit(tx, model.AuditEntry{
+				Action:       model.AuditImportStart,
+				ResourceType: "import",
+				ResourceID:   id,
+				After:        toImportJob(e),
+			})
+		default:
+			return err
+		}
+	}); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	job := toImportJob(e)
+	return &job, nil
+}
+
+func (d *importDAO) GetImport(ctx context.Context, id string) (*model.ImportJob, error) {
+	ctx, span := d.tracer.Start(ctx, "dao.import.get")
+	defer span.End()
+
+	var e entity.ImportJob
+	if err := d.db.WithContext(ctx).Where("id = ?", id).First(&e).Error; err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	job := toImportJob(e)
+	return &job, nil
+}
+
+func (d *importDAO) ApplyImportChunk(ctx context.Context, id string, chunk model.ImportChunk) (*model.ImportJob, error) {
+	ctx, span := d.tracer.Start(ctx, "dao.import.chunk")
+	defer span.End()
+
+	span.SetAttributes("import.rows", chunk.Last-chunk.After)
+
+	var e entity.ImportJob
+	if err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(LockClause).Where("id = ?", id).First(&e).Error; err != nil {
+			return err
+		}
+		if e.Rows != chunk.After {
+			return ErrImportMoved
+		}
+
+		created, err := importAccounts(tx, chunk.Accounts)
+		if err != nil {
+			return err
+		}
+		results := append(slices.Clone(chunk.Results), created...)
+		slices.SortFunc(results, func(a, b model.ImportResult) int { return cmp.Compare(a.Row, b.Row) })
+
+		rows := make([]entity.ImportRow, 0, len(results))
+		for _, r := range results {
+			switch r.Status {
+			case model.ImportRowCreated:
+				e.Created++
+			case model.ImportRowExists:
+				e.Existing++
+			default:
+				e.Failed++
+			}
+			rows = append(rows, entity.ImportRow{
+				JobID:         id,
+				Row:           r.Row,
+				Status:        r.Status,
+				AccountID:     r.AccountID,
+				TransactionID: r.TransactionID,
+				Error:         r.Error,
+			})
+		}
+		if len(rows) > 0 {
+			if err := tx.CreateInBatches(&rows, importResultBatch).Error; err != nil {
+				return err
+			}
+		}
+
+		e.Rows = chunk.Last
+		e.UpdatedAt = time.Now()
+		return tx.Model(&entity.ImportJob{}).
+			Where("id = ?", id).
+			Updates(map[string]interface{}{
+				"applied_rows": e.Rows,
+				"created":      e.Created,
+				"existing":     e.Existing,
+				"failed":       e.Failed,
+				"updated_at":   e.UpdatedAt,
+			}).Error
+	}); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	job := toImportJob(e)
+	return &job, nil
+}
+
+// importAccounts creates the accounts whose ID is not taken yet, deleted accounts included, the
+// way CreateAccount does: each with its created change, audit record and outbox event. A
+// repeated ID, in the database or earlier in the chunk, is reported as existing.
+func importAccounts(tx *gorm.DB, accounts []model.ImportAccount) ([]model.ImportResult, error) {
+	if len(accounts) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]int64, 0, len(accounts))
+	for _, a := range accounts {
+		ids = append(ids, a.Request.AccountID)
+	}
+	var existing []int64
+	if err := tx.Unscoped().
+		Model(&entity.Account{}).
+		Where("account_id IN ?", ids).
+		Pluck("account_id", &existing).Error; err != nil {
+		return nil, err
+	}
+	taken := make(map[int64]bool, len(accounts))
+	for _, id := range existing {
+		taken[id] = true
+	}
+
+	results := make([]model.ImportResult, 0, len(accounts))
+	rows := make([]entity.Account, 0, len(accounts))
+	for _, a := range accounts {
+		res := model.ImportResult{Row: a.Row, Status: model.ImportRowExists, AccountID: a.Request.AccountID}
+		if !taken[a.Request.AccountID] {
+			balance, err := decimal.ParseMoney(a.Request.InitialBalance)
+			if err != nil {
+				return nil, err
+			}
+			taken[a.Request.AccountID] = true
+			res.Status = model.ImportRowCreated
+			rows = append(rows, entity.Account{
+				AccountID: a.Request.AccountID,
+				Balance:   balance,
+				Version:   1,
+				Owner:     a.Request.Owner,
+			})
+		}
+		results = append(results, res)
+	}
+	if len(rows) == 0 {
+		return results, nil
+	}
+
+	if err := tx.Create(&rows).Error; err != nil {
+		return nil, err
+	}
+	changes := make([]entity.AccountChange, 0, len(rows))
+	for _, acc := range rows {
+		changes = append(changes, newChange(acc, model.ChangeCreated, acc.Balance, nil))
+	}
+	if err := tx.Create(&changes).Error; err != nil {
+		return nil, err
+	}
+	for _, acc := range rows {
+		resp := model.AccountGetResponse{
+			AccountID: acc.AccountID,
+			Balance:   acc.Balance.String(),
+			Version:   acc.Version,
+			Owner:     acc.Owner,
+		}
+		if err := writeAudit(tx, model.AuditEntry{
+			Action:       model.AuditAccountCreate,
+			ResourceType: "account",
+			ResourceID:   strconv.FormatInt(acc.AccountID, 10),
+			After:        resp,
+		}); err != nil {
+			return nil, err
+		}
+		if err := writeEvent(tx, model.EventAccountCreated, acc.AccountID, resp); err != nil {
+			return nil, err
+		}
+	}
+
+	return results, nil
+}
+
+func (d *importDAO) FinishImport(ctx context.Context, id string) (*model.ImportJob, error) {
+	ctx, span := d.tracer.Start(ctx, "dao.import.finish")
+	defer span.End()
+
+	if err := d.db.WithContext(ctx).
+		Model(&entity.ImportJob{}).
+		Where("id = ?", id).
+		Update("status", model.ImportCompleted).Error; err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return d.GetImport(ctx, id)
+}
+
+func (d *importDAO) ListImportResults(ctx context.Context, id string, afterRow int64, limit int) ([]model.ImportResult, error) {
+	ctx, span := d.tracer.Start(ctx, "dao.import.results")
+	defer span.End()
+
+	var rows []entity.ImportRow
+	if err := d.db.WithContext(ctx).
+		Where("job_id = ? AND row_num > ?", id, afterRow).
+		Order("row_num").
+		Limit(limit).
+		Find(&rows).Error; err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	results := make([]model.ImportResult, 0, len(rows))
+	for _, r := range rows {
+		results = append(results, model.ImportResult{
+			Row:           r.Row,
+			Status:        r.Status,
+			AccountID:     r.AccountID,
+			TransactionID: r.TransactionID,
+			Error:         r.Error,
+		})
+	}
+	return results, nil
+}
+
+func toImportJob(e entity.ImportJob) model.ImportJob {
+	return model.ImportJob{
+		ID:        e.ID,
+		Kind:      e.Kind,
+		Status:    e.Status,
+		Rows:      e.Rows,
+		Created:   e.Created,
+		Existing:  e.Existing,
+		Failed:    e.Failed,
+		CreatedAt: e.CreatedAt,
+		UpdatedAt: e.UpdatedAt,
+	}
+}
@@ -0,0 +1,196 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"txn-processor/pkg/decimal"
+
+	"gorm.io/gorm"
+)
+
+// moneyColumns are the columns that moved from string/NUMERIC storage to DECIMAL.
+var moneyColumns = []struct {
+	Table  string
+	Column string
+}{
+	{Table: "accounts", Column: "balance"},
+	{Table: "transfers", Column: "amount"},
+}
+
+const legacySuffix = "_legacy"
+
+type columnInfo struct {
+	DataType  string
+	Precision *int64
+	Scale     *int64
+}
+
+type DecimalMismatch struct {
+	Table  string
+	Column string
+	ID     uint
+	Old    string
+	New    string
+}
+
+// MigrateDecimal converts legacy money columns to DECIMAL(20,4).
+// The old values are kept in a <column>_legacy column so VerifyDecimal can compare them.
+func MigrateDecimal(ctx context.Context) error {
+	conn, err := GetConnections()
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get DB connections", "error", err)
+		return err
+	}
+
+	db := conn.db.WithContext(ctx)
+
+	for _, mc := range moneyColumns {
+		info, err := lookupColumn(db, mc.Table, mc.Column)
+		if err != nil {
+			return err
+		}
+		if info == nil || isTargetDecimal(info) {
+			continue
+		}
+
+		slog.InfoContext(ctx, "migrating money column to decimal", "table", mc.Table, "column", mc.Column, "from", info.DataType)
+
+		if err := migrateColumn(db, mc.Table, mc.Column, info); err != nil {
+			slog.ErrorContext(ctx, "failed to migrate money column", "table", mc.Table, "column", mc.Column, "error", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// VerifyDecimal compares every migrated money column against its legacy copy.
+func VerifyDecimal(ctx context.Context) ([]DecimalMismatch, error) {
+	conn, err := GetConnections()
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get DB connections", "error", err)
+		return nil, err
+	}
+
+	db := conn.db.WithContext(ctx)
+
+	var all []DecimalMismatch
+	for _, mc := range moneyColumns {
+		legacy, err := lookupColumn(db, mc.Table, mc.Column+legacySuffix)
+		if err != nil {
+			return nil, err
+		}
+		if legacy == nil {
+			slog.InfoContext(ctx, "no legacy column to verify", "table", mc.Table, "column", mc.Column)
+			continue
+		}
+
+		mismatches, err := compareColumns(db, mc.Table, mc.Column+legacySuffix, mc.Column)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, mismatches...)
+	}
+
+	return all, nil
+}
+
+// migrateColumn copies the column into a DECIMAL shadow, checks the copy and swaps the two.
+// DDL is not transactional in MariaDB, so a failed copy drops the shadow column instead.
+func migrateColumn(db *gorm.DB, table, column string, info *columnInfo) error {
+	shadow := column + "_decimal"
+	legacy := column + legacySuffix
+
+	if err := db.Exec(fmt.Sprintf("ALTER TABLE `%s` ADD COLUMN `%s` %s NULL", table, shadow, decimal.SQLType)).Error; err != nil {
+		return err
+	}
+
+	drop := func() {
+		db.Exec(fmt.Sprintf("ALTER TABLE `%s` DROP COLUMN IF EXISTS `%s`", table, shadow))
+	}
+
+	if err := db.Exec(fmt.Sprintf("UPDATE `%s` SET `%s` = CAST(`%s` AS %s)", table, shadow, column, decimal.SQLType)).Error; err != nil {
+		drop()
+		return err
+	}
+
+	mismatches, err := compareColumns(db, table, column, shadow)
+	if err != nil {
+		drop()
+		return err
+	}
+	if len(mismatches) > 0 {
+		drop()
+		return fmt.Errorf("%s.%s: %d rows do not fit %s, first id=%d old=%s new=%s",
+			table, column, len(mismatches), decimal.SQLType, mismatches[0].ID, mismatches[0].Old, mismatches[0].New)
+	}
+
+	return db.Exec(fmt.Sprintf(
+		"ALTER TABLE `%s` CHANGE `%s` `%s` %s NULL, CHANGE `%s` `%s` %s NOT NULL",
+		table, column, legacy, columnType(info), shadow, column, decimal.SQLType,
+	)).Error
+}
+
+func compareColumns(db *gorm.DB, table, oldCol, newCol string) ([]DecimalMismatch, error) {
+	var rows []struct {
+		ID  uint
+		Old string
+		New *string
+	}
+
+	q := fmt.Sprintf(
+		"SELECT id, CAST(`%[2]s` AS CHAR) AS old, CAST(`%[3]s` AS CHAR) AS new FROM `%[1]s` "+
+			"WHERE `%[2]s` IS NOT NULL AND (`%[3]s` IS NULL OR CAST(`%[2]s` AS DECIMAL(65,30)) <> `%[3]s`)",
+		table, oldCol, newCol,
+	)
+	if err := db.Raw(q).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	mismatches := make([]DecimalMismatch, 0, len(rows))
+	for _, r := range rows {
+		m := DecimalMismatch{Table: table, Column: newCol, ID: r.ID, Old: r.Old}
+		if r.New != nil {
+			m.New = *r.New
+		}
+		mismatches = append(mismatches, m)
+	}
+	return mismatches, nil
+}
+
+func lookupColumn(db *gorm.DB, table, column string) (*columnInfo, error) {
+	var rows []columnInfo
+	if err := db.Raw(
+		"SELECT DATA_TYPE AS data_type, NUMERIC_PRECISION AS `precision`, NUMERIC_SCALE AS scale "+
+			"FROM INFORMATION_SCHEMA.COLUMNS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND COLUMN_NAME = ?",
+		table, column,
+	).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	return &rows[0], nil
+}
+
+func isTargetDecimal(c *columnInfo) bool {
+	return strings.EqualFold(c.DataType, "decimal") &&
+		c.Precision != nil && *c.Precision == decimal.Precision &&
+		c.Scale != nil && *c.Scale == decimal.Scale
+}
+
+func columnType(c *columnInfo) string {
+	switch strings.ToLower(c.DataType) {
+	case "decimal":
+		if c.Precision != nil && c.Scale != nil {
+			return fmt.Sprintf("DECIMAL(%d,%d)", *c.Precision, *c.Scale)
+		}
+		return "DECIMAL"
+	case "varchar", "char":
+		return "VARCHAR(255)"
+	default:
+		return strings.ToUpper(c.DataType)
+	}
+}
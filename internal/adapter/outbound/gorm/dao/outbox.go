@@ -0,0 +1,163 @@
+package dao
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+	"txn-processor/internal/adapter/outbound/gorm/entity"
+	"txn-processor/internal/core/model"
+	"txn-processor/internal/port"
+	"txn-processor/pkg/tracing"
+
+	"github.com/go-redis/redis/v8"
+	"gorm.io/gorm"
+)
+
+const relayLeaseKey = "outbox:relay:lease"
+
+// relayLease takes the lease or renews it for its holder in one step, so a lease that expires
+// between the check and the renewal cannot be extended for a relay that no longer holds it.
+//
+// KEYS[1] lease key; ARGV[1] owner; ARGV[2] TTL in milliseconds. Returns 1 if owner holds the lease.
+var relayLease = redis.NewScript(`
+if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+  return 1
+end
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+  redis.call("PEXPIRE", KEYS[1], ARGV[2])
+  return 1
+end
+return 0
+`)
+
+type outboxDAO struct {
+	*Connections
+}
+
+var _ port.OutboxDao = (*outboxDAO)(nil)
+
+func NewOutboxDAO(conn *Connections) port.OutboxDao {
+	return &outboxDAO{Connections: conn}
+}
+
+// FetchUnsent returns unpublished events in ID order. IDs are taken at insert, so an event can
+// commit, and be returned, before one with a lower ID; see OutboxRelay for what stays ordered.
+func (d *outboxDAO) FetchUnsent(ctx context.Context, limit int) ([]model.Event, error) {
+	ctx, span := d.tracer.Start(ctx, "dao.outbox.fetch")
+	defer span.End()
+
+	var rows []entity.OutboxEvent
+	if err := d.db.WithContext(ctx).
+		Model(&entity.OutboxEvent{}).
+		Where("sent_at IS NULL").
+		Order("id").
+		Limit(limit).
+		Find(&rows).Error; err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	events := make([]model.Event, 0, len(rows))
+	for _, r := range rows {
+		events = append(events, toEvent(r))
+	}
+	return events, nil
+}
+
+// FetchSince returns events created at or after since with an ID above afterID, in ID order.
+// Callers page by passing the last ID they received, so an event that commits after a page
+// holding a higher ID was read is not returned; the change feed is the gap-free history.
+func (d *outboxDAO) FetchSince(ctx context.Context, since time.Time, afterID int64, limit int) ([]model.Event, error) {
+	ctx, span := d.tracer.Start(ctx, "dao.outbox.since")
+	defer span.End()
+
+	var rows []entity.OutboxEvent
+	if err := d.db.WithContext(ctx).
+		Model(&entity.OutboxEvent{}).
+		Where("created_at >= ? AND id > ?", since, afterID).
+		Order("id").
+		Limit(limit).
+		Find(&rows).Error; err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	events := make([]model.Event, 0, len(rows))
+	for _, r := range rows {
+		events = append(events, toEvent(r))
+	}
+	return events, nil
+}
+
+func (d *outboxDAO) MarkSent(ctx context.Context, ids []int64) error {
+	ctx, span := d.tracer.Start(ctx, "dao.outbox.mark")
+	defer span.End()
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	if err := d.db.WithContext(ctx).
+		Model(&entity.OutboxEvent{}).
+		Where("id IN ?", ids).
+		Update("sent_at", time.Now()).Error; err != nil {
+		span.RecordError(err)
+		return err
+	}
+	return nil
+}
+
+// AcquireRelayLease elects a single relay across replicas so events leave in order.
+// The holder renews the lease on every call; others get false until it expires.
+func (d *outboxDAO) AcquireRelayLease(ctx context.Context, owner string, ttl time.Duration) (bool, error) {
+	ok, err := relayLease.Run(ctx, d.cache, []string{relayLeaseKey}, owner, ttl.Milliseconds()).Int()
+	return ok == 1, err
+}
+
+// writeEvent appends an event to the outbox inside the caller's transaction.
+func writeEvent(tx *gorm.DB, eventType string, accountID int64, payload any) error {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	h, err := json.Marshal(tracing.Inject(tx.Statement.Context))
+	if err != nil {
+		return err
+	}
+
+	return tx.Create(&entity.OutboxEvent{
+		EventType: eventType,
+		AccountID: accountID,
+		Payload:   string(b),
+		Headers:   string(h),
+	}).Error
+}
+
+// writeTransferEvents records TransferCompleted for each committed transfer row, once for its
+// source and once for its destination. Each event is keyed by its own account, so every
+// account's events carry its transfers in that account's order, whichever side it was on.
+func writeTransferEvents(tx *gorm.DB, records []entity.Transfer) error {
+	for _, r := range records {
+		for _, accountID := range []int64{r.SourceAccountID, r.DestinationAccountID} {
+			if err := writeEvent(tx, model.EventTransferCompleted, accountID, toTransferResponse(r)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func toEvent(r entity.OutboxEvent) model.Event {
+	e := model.Event{
+		ID:        int64(r.ID),
+		Type:      r.EventType,
+		AccountID: r.AccountID,
+		Payload:   json.RawMessage(r.Payload),
+		CreatedAt: r.CreatedAt,
+	}
+	if r.Headers != "" {
+		_ = json.Unmarshal([]byte(r.Headers), &e.Headers)
+	}
+	return e
+}
@@ -0,0 +1,368 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"time"
+	"txn-processor/internal/adapter/outbound/gorm/entity"
+	"txn-processor/internal/core/model"
+	"txn-processor/pkg/decimal"
+
+	"gorm.io/gorm"
+)
+
+const (
+	ProjectionAccounts      = "accounts"
+	ProjectionDailyBalances = "daily_balances"
+
+	replayBatch = 1000
+
+	// A projection is rebuilt into <table>_rebuild and swapped in, parking the old rows in
+	// <table>_old until they are dropped.
+	rebuildSuffix = "_rebuild"
+	oldSuffix     = "_old"
+)
+
+// projection is a read model derived from the account change log. A rebuild replays the whole
+// log into a fresh copy of its table, so a new read model needs no backfill script: add it here,
+// register its table with AutoMigrate and run rebuild-projections.
+type projection interface {
+	table() any
+	apply(c entity.AccountChange)
+	rows() any
+}
+
+var projections = map[string]func() projection{
+	ProjectionAccounts: func() projection {
+		return &accountProjection{byID: map[int64]*entity.AccountProjection{}}
+	},
+	ProjectionDailyBalances: func() projection {
+		return &dailyProjection{balances: map[int64]decimal.Money{}, byDay: map[dayKey]*entity.DailyBalance{}}
+	},
+}
+
+// ProjectionNames lists every projection in rebuild order.
+func ProjectionNames() []string {
+	names := make([]string, 0, len(projections))
+	for name := range projections {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+type ProjectionMismatch struct {
+	AccountID int64
+	Field     string
+	Live      string
+	Rebuilt   string
+}
+
+// RebuildProjections replays the change log into fresh tables for the named projections, or all
+// of them when names is empty, and swaps each in with one RENAME TABLE, so readers keep the old
+// rows until the new ones are complete. When the accounts projection is rebuilt it is also diffed
+// against the live accounts table. Replay and diff read one consistent snapshot, so transfers
+// running meanwhile do not show up as mismatches.
+func RebuildProjections(ctx context.Context, names []string) ([]ProjectionMismatch, error) {
+	conn, err := GetConnections()
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get DB connections", "error", err)
+		return nil, err
+	}
+
+	if len(names) == 0 {
+		names = ProjectionNames()
+	}
+
+	built := make(map[string]projection, len(names))
+	for _, name := range names {
+		newProjection, ok := projections[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown projection %q", name)
+		}
+		built[name] = newProjection()
+	}
+
+	db := conn.db.WithContext(ctx)
+
+	// DDL commits implicitly, so the copies are created before the snapshot is taken.
+	tables := make(map[string]string, len(built))
+	defer func() {
+		for _, table := range tables {
+			db.Exec(fmt.Sprintf("DROP TABLE IF EXISTS `%s`", table+rebuildSuffix))
+		}
+	}()
+	for name, p := range built {
+		table, err := tableName(db, p.table())
+		if err != nil {
+			return nil, err
+		}
+		if err := db.Exec(fmt.Sprintf("DROP TABLE IF EXISTS `%s`", table+rebuildSuffix)).Error; err != nil {
+			return nil, err
+		}
+		if err := db.Exec(fmt.Sprintf("CREATE TABLE `%s` LIKE `%s`", table+rebuildSuffix, table)).Error; err != nil {
+			return nil, err
+		}
+		tables[name] = table
+	}
+
+	// Under REPEATABLE READ every read below sees the snapshot taken by the first one.
+	var mismatches []ProjectionMismatch
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var batch []entity.AccountChange
+		replayed := 0
+		if err := tx.Model(&entity.AccountChange{}).
+			Order("id").
+			FindInBatches(&batch, replayBatch, func(_ *gorm.DB, _ int) error {
+				for _, c := range batch {
+					for _, p := range built {
+						p.apply(c)
+					}
+				}
+				replayed += len(batch)
+				return nil
+			}).Error; err != nil {
+			return err
+		}
+
+		// With at least one change replayed every projection has rows to insert.
+		for name, p := range built {
+			if replayed == 0 {
+				break
+			}
+			if err := tx.Table(tables[name]+rebuildSuffix).CreateInBatches(p.rows(), replayBatch).Error; err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+		}
+
+		slog.InfoContext(ctx, "change log replayed", "changes", replayed, "projections", names)
+
+		if p, ok := built[ProjectionAccounts]; ok {
+			var err error
+			mismatches, err = diffAccounts(tx, p.(*accountProjection))
+			return err
+		}
+		return nil
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead})
+	if err != nil {
+		return nil, err
+	}
+
+	for name, table := range tables {
+		if err := swapTable(db, table); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		slog.InfoContext(ctx, "projection table swapped in", "projection", name, "table", table)
+	}
+
+	return mismatches, nil
+}
+
+// swapTable replaces table with its rebuilt copy in one RENAME TABLE and drops the old rows.
+func swapTable(db *gorm.DB, table string) error {
+	old := table + oldSuffix
+	if err := db.Exec(fmt.Sprintf("DROP TABLE IF EXISTS `%s`", old)).Error; err != nil {
+		return err
+	}
+	if err := db.Exec(fmt.Sprintf("RENAME TABLE `%s` TO `%s`, `%s` TO `%s`", table, old, table+rebuildSuffix, table)).Error; err != nil {
+		return err
+	}
+	return db.Exec(fmt.Sprintf("DROP TABLE `%s`", old)).Error
+}
+
+// tableName returns the table GORM maps model to.
+func tableName(db *gorm.DB, model any) (string, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return "", err
+	}
+	return stmt.Schema.Table, nil
+}
+
+// diffAccounts compares the rebuilt accounts with the live table, counting shards in balances.
+func diffAccounts(tx *gorm.DB, rebuilt *accountProjection) ([]ProjectionMismatch, error) {
+	var live []entity.Account
+	if err := tx.Model(&entity.Account{}).Order("account_id").Find(&live).Error; err != nil {
+		return nil, err
+	}
+
+	var sums []struct {
+		AccountID int64
+		Total     decimal.Money
+	}
+	if err := tx.Model(&entity.AccountShard{}).
+		Select("account_id, SUM(balance) AS total").
+		Group("account_id").
+		Scan(&sums).Error; err != nil {
+		return nil, err
+	}
+	shards := make(map[int64]decimal.Money, len(sums))
+	for _, s := range sums {
+		shards[s.AccountID] = s.Total
+	}
+
+	var mismatches []ProjectionMismatch
+	seen := make(map[int64]bool, len(live))
+	for _, acc := range live {
+		seen[acc.AccountID] = true
+
+		p, ok := rebuilt.byID[acc.AccountID]
+		if !ok {
+			mismatches = append(mismatches, ProjectionMismatch{AccountID: acc.AccountID, Field: "account", Live: "present", Rebuilt: "missing"})
+			continue
+		}
+
+		balance := acc.Balance
+		if s, ok := shards[acc.AccountID]; ok {
+			balance = balance.Add(s)
+		}
+		if !balance.Equal(p.Balance) {
+			mismatches = append(mismatches, ProjectionMismatch{AccountID: acc.AccountID, Field: "balance", Live: balance.String(), Rebuilt: p.Balance.String()})
+		}
+		if acc.Version != p.Version {
+			mismatches = append(mismatches, ProjectionMismatch{AccountID: acc.AccountID, Field: "version", Live: strconv.FormatInt(acc.Version, 10), Rebuilt: strconv.FormatInt(p.Version, 10)})
+		}
+		if acc.ShardCount != p.ShardCount {
+			mismatches = append(mismatches, ProjectionMismatch{AccountID: acc.AccountID, Field: "shard_count", Live: strconv.Itoa(acc.ShardCount), Rebuilt: strconv.Itoa(p.ShardCount)})
+		}
+	}
+
+	for _, id := range rebuilt.order {
+		if !seen[id] {
+			mismatches = append(mismatches, ProjectionMismatch{AccountID: id, Field: "account", Live: "missing", Rebuilt: "present"})
+		}
+	}
+
+	return mismatches, nil
+}
+
+// accountProjection folds each account's changes into its balance, version and shard count.
+// Unnumbered hot-account changes count towards the balance but not the version, as on the
+// live row.
+type accountProjection struct {
+	byID  map[int64]*entity.AccountProjection
+	order []int64
+}
+
+func (p *accountProjection) table() any { return &entity.AccountProjection{} }
+
+func (p *accountProjection) apply(c entity.AccountChange) {
+	acc, ok := p.byID[c.AccountID]
+	if !ok {
+		acc = &entity.AccountProjection{AccountID: c.AccountID}
+		p.byID[c.AccountID] = acc
+		p.order = append(p.order, c.AccountID)
+	}
+
+	acc.Balance = applyChange(acc.Balance, c)
+	acc.Changes++
+	if c.Version != nil {
+		acc.Version = *c.Version
+	}
+	if c.Kind == model.ChangeReshard || c.Kind == model.ChangeOpening {
+		acc.ShardCount = c.Shards
+	}
+}
+
+func (p *accountProjection) rows() any {
+	rows := make([]entity.AccountProjection, 0, len(p.order))
+	for _, id := range p.order {
+		rows = append(rows, *p.byID[id])
+	}
+	return rows
+}
+
+type dayKey struct {
+	accountID int64
+	day       time.Time
+}
+
+// dailyProjection tracks the running balance of each account and closes it per UTC day.
+type dailyProjection struct {
+	balances map[int64]decimal.Money
+	byDay    map[dayKey]*entity.DailyBalance
+	order    []dayKey
+}
+
+func (p *dailyProjection) table() any { return &entity.DailyBalance{} }
+
+func (p *dailyProjection) apply(c entity.AccountChange) {
+	before := p.balances[c.AccountID]
+	after := applyChange(before, c)
+	p.balances[c.AccountID] = after
+
+	key := dayKey{accountID: c.AccountID, day: c.CreatedAt.UTC().Truncate(24 * time.Hour)}
+	d, ok := p.byDay[key]
+	if !ok {
+		d = &entity.DailyBalance{
+			AccountID: c.AccountID,
+			Day:       key.day,
+			Opening:   before,
+			Credits:   decimal.Zero,
+			Debits:    decimal.Zero,
+		}
+		p.byDay[key] = d
+		p.order = append(p.order, key)
+	}
+	d.Closing = after
+
+	switch c.Kind {
+	case model.ChangeCredit:
+		d.Credits = d.Credits.Add(c.Amount)
+	case model.ChangeDebit:
+		d.Debits = d.Debits.Add(c.Amount.Neg())
+	}
+}
+
+func (p *dailyProjection) rows() any {
+	rows := make([]entity.DailyBalance, 0, len(p.order))
+	for _, k := range p.order {
+		rows = append(rows, *p.byDay[k])
+	}
+	return rows
+}
+
+// applyChange returns the balance after c. Created and opening entries set the balance; the
+// others move it by their amount, which is zero for a reshard.
+func applyChange(balance decimal.Money, c entity.AccountChange) decimal.Money {
+	switch c.Kind {
+	case model.ChangeCreated, model.ChangeOpening:
+		return c.Amount
+	default:
+		return balance.Add(c.Amount)
+	}
+}
+
+// seedOpeningChanges gives accounts that predate the change log an opening entry with their
+// current total, so replaying the log reproduces them. Accounts never changed before get
+// version 1 so the entry is visible in the feed.
+func seedOpeningChanges(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(
+			"UPDATE accounts a SET a.version = 1 WHERE a.version = 0 AND a.deleted_at IS NULL " +
+				"AND NOT EXISTS (SELECT 1 FROM account_changes c WHERE c.account_id = a.account_id)",
+		).Error; err != nil {
+			return err
+		}
+
+		res := tx.Exec(
+			"INSERT INTO account_changes (account_id, version, kind, amount, balance, shards, created_at) "+
+				"SELECT a.account_id, a.version, ?, a.balance + COALESCE(s.total, 0), a.balance + COALESCE(s.total, 0), a.shard_count, ? "+
+				"FROM accounts a LEFT JOIN (SELECT account_id, SUM(balance) AS total FROM account_shards GROUP BY account_id) s "+
+				"ON s.account_id = a.account_id "+
+				"WHERE a.deleted_at IS NULL AND NOT EXISTS (SELECT 1 FROM account_changes c WHERE c.account_id = a.account_id)",
+			model.ChangeOpening, time.Now(),
+		)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected > 0 {
+			slog.InfoContext(tx.Statement.Context, "seeded opening changes", "accounts", res.RowsAffected)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,90 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"txn-processor/internal/core/model"
+	"txn-processor/pkg/decimal"
+
+	"gorm.io/gorm"
+)
+
+// Every account's change log starts with a created or opening entry holding its first balance
+// and then moves by each change's amount, so the amounts sum to the current balance. Hot
+// accounts count their shards and their not yet numbered changes.
+const (
+	reconcileLedgers = "SELECT account_id, SUM(amount) AS total FROM account_changes GROUP BY account_id"
+	reconcileShards  = "SELECT account_id, SUM(balance) AS total FROM account_shards GROUP BY account_id"
+)
+
+// Reconcile compares every account's balance with its change log, and the sum of all balances
+// with the money issued by created and opening entries. It reports at most limit mismatched
+// accounts. All queries read one consistent snapshot, so running transfers do not show up.
+func (d *accountDAO) Reconcile(ctx context.Context, limit int) (*model.ReconcileReport, error) {
+	ctx, span := d.tracer.Start(ctx, "dao.account.reconcile")
+	defer span.End()
+
+	var report model.ReconcileReport
+	err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var totals struct {
+			Accounts int64
+			Total    decimal.Money
+		}
+		if err := tx.Raw(
+			"SELECT COUNT(*) AS accounts, COALESCE(SUM(a.balance + COALESCE(s.total, 0)), 0) AS total " +
+				"FROM accounts a LEFT JOIN (" + reconcileShards + ") s ON s.account_id = a.account_id " +
+				"WHERE a.deleted_at IS NULL",
+		).Scan(&totals).Error; err != nil {
+			return err
+		}
+
+		var issued decimal.Money
+		if err := tx.Raw(
+			"SELECT COALESCE(SUM(amount), 0) FROM account_changes WHERE kind IN ?",
+			[]string{model.ChangeCreated, model.ChangeOpening},
+		).Scan(&issued).Error; err != nil {
+			return err
+		}
+
+		var rows []struct {
+			AccountID int64
+			Live      *decimal.Money
+			Ledger    decimal.Money
+		}
+		if err := tx.Raw(
+			"SELECT a.account_id, a.balance + COALESCE(s.total, 0) AS live, COALESCE(c.total, 0) AS ledger "+
+				"FROM accounts a "+
+				"LEFT JOIN ("+reconcileShards+") s ON s.account_id = a.account_id "+
+				"LEFT JOIN ("+reconcileLedgers+") c ON c.account_id = a.account_id "+
+				"WHERE a.deleted_at IS NULL AND a.balance + COALESCE(s.total, 0) <> COALESCE(c.total, 0) "+
+				"UNION ALL "+
+				"SELECT c.account_id, NULL AS live, c.total AS ledger FROM ("+reconcileLedgers+") c "+
+				"WHERE NOT EXISTS (SELECT 1 FROM accounts a WHERE a.account_id = c.account_id AND a.deleted_at IS NULL) "+
+				"ORDER BY account_id LIMIT ?",
+			limit,
+		).Scan(&rows).Error; err != nil {
+			return err
+		}
+
+		report = model.ReconcileReport{
+			Accounts:   totals.Accounts,
+			Total:      totals.Total.String(),
+			Issued:     issued.String(),
+			Mismatches: make([]model.ReconcileMismatch, 0, len(rows)),
+		}
+		for _, r := range rows {
+			m := model.ReconcileMismatch{AccountID: r.AccountID, Live: "missing", Ledger: r.Ledger.String()}
+			if r.Live != nil {
+				m.Live = r.Live.String()
+			}
+			report.Mismatches = append(report.Mismatches, m)
+		}
+		return nil
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return &report, nil
+}
@@ -0,0 +1,346 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"txn-processor/internal/adapter/outbound/gorm/entity"
+	"txn-processor/internal/core/model"
+	"txn-processor/pkg/decimal"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ShareLockClause lets many transfers touch a hot account while SetAccountShards waits for them.
+var ShareLockClause = clause.Locking{Strength: "SHARE"}
+
+// errShardShort sends a transfer whose picked shard is short back to start over with the hot
+// rows locked for update, so it can drain every shard and the row.
+var errShardShort = errors.New("picked shard is short")
+
+// hasHotAccount reports whether either side of the transfer is sharded.
+func (d *transferDAO) hasHotAccount(ctx context.Context, req model.TransferRequest) (bool, error) {
+	var count int64
+	err := d.db.WithContext(ctx).
+		Model(&entity.Account{}).
+		Where("account_id IN ? AND shard_count > 0", []int64{req.SourceAccountID, req.DestinationAccountID}).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// shardedTransfer debits and credits a random shard of each hot account instead of its row.
+// Plain accounts on either side are updated with guarded single-statement updates.
+//
+// Draining may write the source row, which a shared lock would have to be upgraded for; two
+// transfers doing that at once deadlock. So a short shard restarts the transfer with the hot
+// rows locked for update from the start.
+func (d *transferDAO) shardedTransfer(ctx context.Context, req model.TransferRequest, amount decimal.Money) (*transferResult, error) {
+	result, err := d.runSharded(ctx, req, amount, false)
+	if errors.Is(err, errShardShort) {
+		return d.runSharded(ctx, req, amount, true)
+	}
+	return result, err
+}
+
+// runSharded runs one attempt of shardedTransfer; drain allows draining the source's shards.
+func (d *transferDAO) runSharded(ctx context.Context, req model.TransferRequest, amount decimal.Money, drain bool) (*transferResult, error) {
+	lock := ShareLockClause
+	if drain {
+		lock = LockClause
+	}
+
+	tx := d.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	var accounts []entity.Account
+	if err := tx.Model(&entity.Account{}).
+		Where("account_id IN ?", []int64{req.SourceAccountID, req.DestinationAccountID}).
+		Order("account_id").
+		Find(&accounts).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if len(accounts) != 2 {
+		tx.Rollback()
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	// Only hot rows take the lock; plain rows are written directly and
+	// a shared lock there would deadlock on upgrade between concurrent transfers.
+	for i := range accounts {
+		if accounts[i].ShardCount == 0 {
+			continue
+		}
+		if err := tx.Model(&entity.Account{}).
+			Clauses(lock).
+			Where("id = ?", accounts[i].ID).
+			First(&accounts[i]).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	source, dest := accounts[0], accounts[1]
+	if source.AccountID != req.SourceAccountID {
+		source, dest = dest, source
+	}
+
+	if err := debitAccount(tx, &source, amount, drain); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := creditAccount(tx, &dest, amount); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	record, err := insertTransfer(tx, req, amount, source, dest)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return &transferResult{record: record, source: source, dest: dest}, nil
+}
+
+// debitAccount takes amount from a random shard of a hot account. If that shard is short, it
+// drains every shard when the account row is locked for update, and fails with errShardShort
+// otherwise.
+func debitAccount(tx *gorm.DB, acc *entity.Account, amount decimal.Money, drain bool) error {
+	if acc.ShardCount == 0 {
+		return debitRow(tx, acc, amount)
+	}
+
+	res := tx.Model(&entity.AccountShard{}).
+		Where("account_id = ? AND shard = ? AND balance >= ?", acc.AccountID, rand.IntN(acc.ShardCount), amount).
+		Update("balance", gorm.Expr("balance - ?", amount))
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 1 {
+		return nil
+	}
+	if !drain {
+		return errShardShort
+	}
+
+	// The picked shard is short, so lock every shard and drain them in order.
+	var shards []entity.AccountShard
+	if err := tx.Model(&entity.AccountShard{}).
+		Clauses(LockClause).
+		Where("account_id = ?", acc.AccountID).
+		Order("shard").
+		Find(&shards).Error; err != nil {
+		return err
+	}
+
+	remaining := amount
+	for _, s := range shards {
+		if !remaining.IsPositive() {
+			break
+		}
+		if !s.Balance.IsPositive() {
+			continue
+		}
+
+		take := remaining
+		if s.Balance.LessThan(remaining) {
+			take = s.Balance
+		}
+
+		if err := tx.Model(&entity.AccountShard{}).
+			Where("id = ?", s.ID).
+			Update("balance", gorm.Expr("balance - ?", take)).Error; err != nil {
+			return err
+		}
+		remaining = remaining.Sub(take)
+	}
+
+	if !remaining.IsPositive() {
+		return nil
+	}
+
+	// Credits that raced with SetAccountShards may still sit on the account row.
+	if !acc.Balance.IsPositive() {
+		return ErrInsufficientBalance
+	}
+	return debitRow(tx, acc, remaining)
+}
+
+// debitRow takes amount from the account row. Hot accounts keep their version for the
+// sequencer, which numbers their changes later.
+func debitRow(tx *gorm.DB, acc *entity.Account, amount decimal.Money) error {
+	updates := map[string]interface{}{
+		"balance": gorm.Expr("balance - ?", amount),
+	}
+	if acc.ShardCount == 0 {
+		updates["version"] = gorm.Expr("version + 1")
+	}
+
+	res := tx.Model(&entity.Account{}).
+		Where("id = ? AND balance >= ?", acc.ID, amount).
+		Updates(updates)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrInsufficientBalance
+	}
+	return reloadAccount(tx, acc)
+}
+
+func creditAccount(tx *gorm.DB, acc *entity.Account, amount decimal.Money) error {
+	if acc.ShardCount == 0 {
+		if err := tx.Model(&entity.Account{}).
+			Where("id = ?", acc.ID).
+			Updates(map[string]interface{}{
+				"balance": gorm.Expr("balance + ?", amount),
+				"version": gorm.Expr("version + 1"),
+			}).Error; err != nil {
+			return err
+		}
+		return reloadAccount(tx, acc)
+	}
+
+	return tx.Model(&entity.AccountShard{}).
+		Where("account_id = ? AND shard = ?", acc.AccountID, rand.IntN(acc.ShardCount)).
+		Update("balance", gorm.Expr("balance + ?", amount)).Error
+}
+
+// reloadAccount refreshes a row this transaction has just written, so the lock is already ours.
+func reloadAccount(tx *gorm.DB, acc *entity.Account) error {
+	return tx.Where("id = ?", acc.ID).First(acc).Error
+}
+
+// shardTotals returns the sum of all shards of each account in ids, in one grouped query.
+func shardTotals(db *gorm.DB, ids []int64) (map[int64]decimal.Money, error) {
+	var rows []struct {
+		AccountID int64
+		Total     decimal.Money
+	}
+	if err := db.Model(&entity.AccountShard{}).
+		Select("account_id, SUM(balance) AS total").
+		Where("account_id IN ?", ids).
+		Group("account_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	totals := make(map[int64]decimal.Money, len(rows))
+	for _, r := range rows {
+		totals[r.AccountID] = r.Total
+	}
+	return totals, nil
+}
+
+// SetAccountShards spreads an account's balance over n shards, or folds it back onto the row when n is 0.
+// It runs online: the row lock waits for in-flight transfers and holds new ones until commit.
+func (d *accountDAO) SetAccountShards(ctx context.Context, id int64, n int) error {
+	ctx, span := d.tracer.Start(ctx, "dao.account.shards")
+	defer span.End()
+
+	err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Number the hot account's pending changes first so the reshard lands after them.
+		if _, err := sequenceAccount(tx, id); err != nil {
+			return err
+		}
+
+		var acc entity.Account
+		if err := tx.Model(&entity.Account{}).
+			Clauses(LockClause).
+			Where("account_id = ?", id).
+			First(&acc).Error; err != nil {
+			return err
+		}
+
+		var shards []entity.AccountShard
+		if err := tx.Model(&entity.AccountShard{}).
+			Clauses(LockClause).
+			Where("account_id = ?", id).
+			Find(&shards).Error; err != nil {
+			return err
+		}
+
+		total := acc.Balance
+		for _, s := range shards {
+			total = total.Add(s.Balance)
+		}
+
+		if err := tx.Unscoped().
+			Where("account_id = ?", id).
+			Delete(&entity.AccountShard{}).Error; err != nil {
+			return err
+		}
+
+		base := total
+		if n > 0 {
+			base = decimal.Zero
+			parts := total.Split(n)
+			rows := make([]entity.AccountShard, n)
+			for i := range rows {
+				rows[i] = entity.AccountShard{AccountID: id, Shard: i, Balance: parts[i]}
+			}
+			if err := tx.Create(&rows).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&entity.Account{}).
+			Where("id = ?", acc.ID).
+			Updates(map[string]interface{}{
+				"balance":     base,
+				"shard_count": n,
+				"version":     acc.Version + 1,
+			}).Error; err != nil {
+			return err
+		}
+
+		version := acc.Version + 1
+		if err := tx.Create(&entity.AccountChange{
+			AccountID: id,
+			Version:   &version,
+			Kind:      model.ChangeReshard,
+			Amount:    decimal.Zero,
+			Balance:   &total,
+			Shards:    n,
+		}).Error; err != nil {
+			return err
+		}
+
+		if err := writeAudit(tx, model.AuditEntry{
+			Action:       model.AuditAccountShards,
+			ResourceType: "account",
+			ResourceID:   strconv.FormatInt(id, 10),
+			Before:       model.AccountShardResponse{AccountID: id, Shards: acc.ShardCount},
+			After:        model.AccountShardResponse{AccountID: id, Shards: n},
+		}); err != nil {
+			return err
+		}
+
+		return writeEvent(tx, model.EventAccountShardsChanged, id, model.AccountShardResponse{
+			AccountID: id,
+			Shards:    n,
+		})
+	})
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	key := fmt.Sprintf("account:%d", id)
+	if err := d.cache.Del(ctx, key).Err(); err != nil {
+		span.RecordError(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,334 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+	"txn-processor/internal/adapter/outbound/gorm/entity"
+	"txn-processor/internal/core/model"
+	"txn-processor/pkg/decimal"
+
+	"gorm.io/gorm"
+)
+
+const (
+	StrategyPessimistic = "pessimistic"
+	StrategyOptimistic  = "optimistic"
+	StrategyConditional = "conditional"
+)
+
+// lockStrategy applies the balance changes and the transfer row inside one DB transaction.
+type lockStrategy interface {
+	name() string
+	transfer(ctx context.Context, db *gorm.DB, req model.TransferRequest, amount decimal.Money) (*transferResult, error)
+}
+
+// errHotAccount reports that an account was sharded after hasHotAccount looked. The strategies
+// only write account rows, so the transfer is rolled back and run by shardedTransfer instead.
+var errHotAccount = errors.New("account is sharded")
+
+// transferResult carries the committed record and the post-transfer account states.
+type transferResult struct {
+	record entity.Transfer
+	source entity.Account
+	dest   entity.Account
+}
+
+func newLockStrategy(name string, retries int) (lockStrategy, error) {
+	switch name {
+	case "", StrategyPessimistic:
+		return pessimisticStrategy{}, nil
+	case StrategyOptimistic:
+		if retries <= 0 {
+			retries = 1
+		}
+		return optimisticStrategy{retries: retries}, nil
+	case StrategyConditional:
+		return conditionalStrategy{}, nil
+	default:
+		return nil, fmt.Errorf("unknown lock strategy %q", name)
+	}
+}
+
+// pessimisticStrategy takes SELECT ... FOR UPDATE locks on both accounts.
+type pessimisticStrategy struct{}
+
+func (pessimisticStrategy) name() string { return StrategyPessimistic }
+
+func (pessimisticStrategy) transfer(ctx context.Context, db *gorm.DB, req model.TransferRequest, amount decimal.Money) (*transferResult, error) {
+	tx := db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	// Both rows are locked in account_id order, so opposite transfers queue instead of deadlocking.
+	var accounts []entity.Account
+	if err := tx.Model(&entity.Account{}).
+		Clauses(LockClause).
+		Where("account_id IN ?", []int64{req.SourceAccountID, req.DestinationAccountID}).
+		Order("account_id").
+		Find(&accounts).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if len(accounts) != 2 {
+		tx.Rollback()
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	if accounts[0].ShardCount > 0 || accounts[1].ShardCount > 0 {
+		tx.Rollback()
+		return nil, errHotAccount
+	}
+
+	source, dest := accounts[0], accounts[1]
+	if source.AccountID != req.SourceAccountID {
+		source, dest = dest, source
+	}
+
+	if source.Balance.LessThan(amount) {
+		tx.Rollback()
+		return nil, ErrInsufficientBalance
+	}
+
+	if err := tx.Model(&entity.Account{}).
+		Where("id = ?", source.ID).
+		Updates(map[string]interface{}{
+			"balance": gorm.Expr("balance - ?", amount),
+			"version": gorm.Expr("version + 1"),
+		}).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Model(&entity.Account{}).
+		Where("id = ?", dest.ID).
+		Updates(map[string]interface{}{
+			"balance": gorm.Expr("balance + ?", amount),
+			"version": gorm.Expr("version + 1"),
+		}).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	source.Balance = source.Balance.Sub(amount)
+	source.Version++
+	dest.Balance = dest.Balance.Add(amount)
+	dest.Version++
+
+	record, err := insertTransfer(tx, req, amount, source, dest)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return &transferResult{record: record, source: source, dest: dest}, nil
+}
+
+// optimisticStrategy reads without locks and updates with a version check, retrying on conflict.
+type optimisticStrategy struct {
+	retries int
+}
+
+var errVersionConflict = errors.New("version conflict")
+
+func (optimisticStrategy) name() string { return StrategyOptimistic }
+
+func (s optimisticStrategy) transfer(ctx context.Context, db *gorm.DB, req model.TransferRequest, amount decimal.Money) (*transferResult, error) {
+	for attempt := 0; attempt < s.retries; attempt++ {
+		if attempt > 0 {
+			backoff := time.Duration(1<<min(attempt, 6)) * time.Millisecond
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(backoff + rand.N(backoff)):
+			}
+		}
+
+		result, err := s.try(ctx, db, req, amount)
+		if errors.Is(err, errVersionConflict) {
+			continue
+		}
+		return result, err
+	}
+
+	return nil, ErrConcurrentUpdate
+}
+
+func (optimisticStrategy) try(ctx context.Context, db *gorm.DB, req model.TransferRequest, amount decimal.Money) (*transferResult, error) {
+	tx := db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	var source entity.Account
+	var dest entity.Account
+
+	if err := tx.Model(&entity.Account{}).
+		Where("account_id = ?", req.SourceAccountID).
+		First(&source).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Model(&entity.Account{}).
+		Where("account_id = ?", req.DestinationAccountID).
+		First(&dest).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// A reshard after these reads bumps the version, so the updates below catch it as a conflict.
+	if source.ShardCount > 0 || dest.ShardCount > 0 {
+		tx.Rollback()
+		return nil, errHotAccount
+	}
+
+	if source.Balance.LessThan(amount) {
+		tx.Rollback()
+		return nil, ErrInsufficientBalance
+	}
+
+	source.Balance = source.Balance.Sub(amount)
+	dest.Balance = dest.Balance.Add(amount)
+
+	// Writing in account_id order keeps opposite transfers from deadlocking on the row locks.
+	accounts := []*entity.Account{&source, &dest}
+	if dest.AccountID < source.AccountID {
+		accounts[0], accounts[1] = accounts[1], accounts[0]
+	}
+	for _, acc := range accounts {
+		res := tx.Model(&entity.Account{}).
+			Where("id = ? AND version = ?", acc.ID, acc.Version).
+			Updates(map[string]interface{}{
+				"balance": acc.Balance,
+				"version": acc.Version + 1,
+			})
+		if res.Error != nil {
+			tx.Rollback()
+			return nil, res.Error
+		}
+		if res.RowsAffected == 0 {
+			tx.Rollback()
+			return nil, errVersionConflict
+		}
+		acc.Version++
+	}
+
+	record, err := insertTransfer(tx, req, amount, source, dest)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return &transferResult{record: record, source: source, dest: dest}, nil
+}
+
+// conditionalStrategy debits with a single guarded UPDATE and never reads before writing.
+type conditionalStrategy struct{}
+
+func (conditionalStrategy) name() string { return StrategyConditional }
+
+func (conditionalStrategy) transfer(ctx context.Context, db *gorm.DB, req model.TransferRequest, amount decimal.Money) (*transferResult, error) {
+	tx := db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	// Both updates skip sharded rows; missingOrHot tells why an update matched nothing.
+	debit := func() error {
+		res := tx.Model(&entity.Account{}).
+			Where("account_id = ? AND shard_count = 0 AND balance >= ?", req.SourceAccountID, amount).
+			Updates(map[string]interface{}{
+				"balance": gorm.Expr("balance - ?", amount),
+				"version": gorm.Expr("version + 1"),
+			})
+		if res.Error != nil || res.RowsAffected == 1 {
+			return res.Error
+		}
+		if err := missingOrHot(tx, req.SourceAccountID); err != nil {
+			return err
+		}
+		return ErrInsufficientBalance
+	}
+
+	credit := func() error {
+		res := tx.Model(&entity.Account{}).
+			Where("account_id = ? AND shard_count = 0", req.DestinationAccountID).
+			Updates(map[string]interface{}{
+				"balance": gorm.Expr("balance + ?", amount),
+				"version": gorm.Expr("version + 1"),
+			})
+		if res.Error != nil || res.RowsAffected == 1 {
+			return res.Error
+		}
+		if err := missingOrHot(tx, req.DestinationAccountID); err != nil {
+			return err
+		}
+		return gorm.ErrRecordNotFound
+	}
+
+	// Each update locks its row, so they run in account_id order to keep opposite transfers
+	// from deadlocking; a failed debit rolls back a credit made before it.
+	steps := []func() error{debit, credit}
+	if req.DestinationAccountID < req.SourceAccountID {
+		steps[0], steps[1] = credit, debit
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	// The rows are now locked by our own updates, so these reads see the committed result.
+	var source entity.Account
+	var dest entity.Account
+
+	if err := tx.Where("account_id = ?", req.SourceAccountID).First(&source).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Where("account_id = ?", req.DestinationAccountID).First(&dest).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	record, err := insertTransfer(tx, req, amount, source, dest)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return &transferResult{record: record, source: source, dest: dest}, nil
+}
+
+// missingOrHot returns gorm.ErrRecordNotFound if the account does not exist, errHotAccount if
+// it is sharded, and nil otherwise.
+func missingOrHot(tx *gorm.DB, accountID int64) error {
+	var acc entity.Account
+	if err := tx.Model(&entity.Account{}).
+		Select("shard_count").
+		Where("account_id = ?", accountID).
+		First(&acc).Error; err != nil {
+		return err
+	}
+	if acc.ShardCount > 0 {
+		return errHotAccount
+	}
+	return nil
+}
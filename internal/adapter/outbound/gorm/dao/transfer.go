@@ -6,11 +6,15 @@ import (
 	"errors"
 	"fmt"
 	"time"
+	"txn-processor/config"
 	"txn-processor/internal/adapter/outbound/gorm/entity"
 	"txn-processor/internal/core/model"
 	"txn-processor/internal/port"
 	"txn-processor/pkg/decimal"
+	"txn-processor/pkg/tracing"
 
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
@@ -20,121 +24,242 @@ const (
 
 var LockClause = clause.Locking{Strength: "UPDATE"}
 
+var (
+	ErrInsufficientBalance = errors.New("insufficient balance")
+	ErrAccountFrozen       = errors.New("account frozen")
+	ErrConcurrentUpdate    = errors.New("concurrent update, retries exhausted")
+)
+
 type transferDAO struct {
 	*Connections
+	strategy lockStrategy
+	batcher  *groupCommitter
 }
 
 var _ port.TransferDao = (*transferDAO)(nil)
 
-func NewTransferDAO(conn *Connections) port.TransferDao {
-	return &transferDAO{Connections: conn}
+func NewTransferDAO(conn *Connections, conf config.DB) (port.TransferDao, error) {
+	strategy, err := newLockStrategy(conf.LockStrategy, conf.OptimisticRetries)
+	if err != nil {
+		return nil, err
+	}
+	d := &transferDAO{Connections: conn, strategy: strategy}
+	if conf.GroupCommit.IsEnabled {
+		d.batcher = newGroupCommitter(conn.db, conn.tracer, strategy, conf.GroupCommit)
+	}
+	return d, nil
+}
+
+// Shutdown commits the transfers still queued for a group commit and stops taking new ones.
+func (d *transferDAO) Shutdown(ctx context.Context) error {
+	if d.batcher == nil {
+		return nil
+	}
+	return d.batcher.close(ctx)
 }
 
 func (d *transferDAO) RunTransferTx(ctx context.Context, req model.TransferRequest) (*model.TransferResponse, error) {
 	ctx, span := d.tracer.Start(ctx, "dao.transfer.tx")
 	defer span.End()
 
-	tx := d.db.WithContext(ctx).Begin()
-	if tx.Error != nil {
-		span.RecordError(tx.Error)
-		return nil, tx.Error
-	}
-
-	var source entity.Account
-	var dest entity.Account
+	span.SetAttributes("lock.strategy", d.strategy.name())
 
-	if err := tx.Model(&entity.Account{}).
-		Clauses(LockClause).
-		Where("account_id = ?", req.SourceAccountID).
-		First(&source).Error; err != nil {
+	amount, err := decimal.ParseMoney(req.Amount)
+	if err != nil {
 		span.RecordError(err)
-		tx.Rollback()
 		return nil, err
 	}
 
-	if err := tx.Model(&entity.Account{}).
-		Clauses(LockClause).
-		Where("account_id = ?", req.DestinationAccountID).
-		First(&dest).Error; err != nil {
+	hot, err := d.hasHotAccount(ctx, req)
+	if err != nil {
 		span.RecordError(err)
-		tx.Rollback()
 		return nil, err
 	}
 
-	if decimal.LessThan(source.Balance, req.Amount) {
-		tx.Rollback()
-		err := errors.New("insufficient balance")
+	var result *transferResult
+	switch {
+	case hot:
+		span.SetAttributes("lock.strategy", "sharded")
+		result, err = d.shardedTransfer(ctx, req, amount)
+	case d.batcher != nil:
+		span.SetAttributes("lock.strategy", "group-commit")
+		result, err = d.batcher.submit(ctx, req, amount)
+	default:
+		result, err = d.strategy.transfer(ctx, d.db, req, amount)
+	}
+	if errors.Is(err, errHotAccount) {
+		span.SetAttributes("lock.strategy", "sharded")
+		result, err = d.shardedTransfer(ctx, req, amount)
+	}
+	if err != nil && req.RequestID != "" && isDuplicate(err) {
+		// The request was already applied; hand back the original transfer.
+		return d.getTransferByRequestID(ctx, req.RequestID)
+	}
+	if err != nil {
 		span.RecordError(err)
 		return nil, err
 	}
 
-	source.Balance = decimal.Sub(source.Balance, req.Amount)
-	dest.Balance = decimal.Add(dest.Balance, req.Amount)
+	resp := toTransferResponse(result.record)
 
-	if err := tx.Model(&entity.Account{}).
-		Where("id = ?", source.ID).
-		Updates(map[string]interface{}{"balance": source.Balance}).Error; err != nil {
+	d.cacheTransfer(ctx, span, resp, result.source, result.dest)
+
+	return resp, nil
+}
+
+// cacheTransfer writes the committed transfer and both post-transfer balances to the cache.
+func (d *transferDAO) cacheTransfer(ctx context.Context, span tracing.Span, resp *model.TransferResponse, source, dest entity.Account) {
+	trKey := fmt.Sprintf("transfer:%d", resp.TransactionID)
+	b, _ := json.Marshal(resp)
+	if err := d.cache.Set(ctx, trKey, b, transferTTL).Err(); err != nil {
 		span.RecordError(err)
-		tx.Rollback()
-		return nil, err
+		_ = d.cache.Del(ctx, trKey).Err()
+	}
+
+	d.cacheAccount(ctx, span, source)
+	d.cacheAccount(ctx, span, dest)
+}
+
+// cacheAccount stores the post-transfer balance, or drops the key for sharded accounts
+// whose total is only known by summing their shards.
+func (d *transferDAO) cacheAccount(ctx context.Context, span tracing.Span, acc entity.Account) {
+	key := fmt.Sprintf("account:%d", acc.AccountID)
+
+	if acc.ShardCount > 0 {
+		if err := d.cache.Del(ctx, key).Err(); err != nil {
+			span.RecordError(err)
+		}
+		return
 	}
 
-	if err := tx.Model(&entity.Account{}).
-		Where("id = ?", dest.ID).
-		Updates(map[string]interface{}{"balance": dest.Balance}).Error; err != nil {
+	b, _ := json.Marshal(model.AccountGetResponse{AccountID: acc.AccountID, Balance: acc.Balance.String(), Version: acc.Version, Frozen: acc.Frozen})
+	if err := d.cache.Set(ctx, key, b, accountTTL).Err(); err != nil {
 		span.RecordError(err)
-		tx.Rollback()
-		return nil, err
+		_ = d.cache.Del(ctx, key).Err()
 	}
+}
 
-	record := entity.Transfer{
-		SourceAccountID:      req.SourceAccountID,
-		DestinationAccountID: req.DestinationAccountID,
-		Amount:               req.Amount,
+func (d *transferDAO) GetTransferByID(ctx context.Context, id int64) (*model.TransferResponse, error) {
+	ctx, span := d.tracer.Start(ctx, "dao.transfer.get")
+	defer span.End()
+
+	key := fmt.Sprintf("transfer:%d", id)
+
+	val, err := d.cache.Get(ctx, key).Result()
+	if err == nil {
+		var cached model.TransferResponse
+		if json.Unmarshal([]byte(val), &cached) == nil {
+			return &cached, nil
+		}
+		span.RecordError(fmt.Errorf("cache unmarshal error for key %s", key))
+	} else {
+		span.RecordError(err)
 	}
 
-	if err := tx.Model(&entity.Transfer{}).
-		Create(&record).Error; err != nil {
+	var record entity.Transfer
+	if err := d.db.WithContext(ctx).
+		Model(&entity.Transfer{}).
+		Where("id = ?", id).
+		First(&record).Error; err != nil {
 		span.RecordError(err)
-		tx.Rollback()
 		return nil, err
 	}
 
-	if err := tx.Commit().Error; err != nil {
+	resp := toTransferResponse(record)
+	b, _ := json.Marshal(resp)
+	if err := d.cache.Set(ctx, key, b, transferTTL).Err(); err != nil {
+		span.RecordError(err)
+		_ = d.cache.Del(ctx, key).Err()
+	}
+
+	return resp, nil
+}
+
+// GetTransfersByIDs reads the committed transfers among ids in one query, skipping the cache.
+func (d *transferDAO) GetTransfersByIDs(ctx context.Context, ids []int64) ([]model.TransferResponse, error) {
+	ctx, span := d.tracer.Start(ctx, "dao.transfer.get_many")
+	defer span.End()
+
+	var records []entity.Transfer
+	if err := d.db.WithContext(ctx).
+		Model(&entity.Transfer{}).
+		Where("id IN ?", ids).
+		Find(&records).Error; err != nil {
 		span.RecordError(err)
 		return nil, err
 	}
 
+	transfers := make([]model.TransferResponse, 0, len(records))
+	for _, record := range records {
+		transfers = append(transfers, *toTransferResponse(record))
+	}
+	return transfers, nil
+}
+
+func (d *transferDAO) getTransferByRequestID(ctx context.Context, requestID string) (*model.TransferResponse, error) {
+	var record entity.Transfer
+	if err := d.db.WithContext(ctx).
+		Model(&entity.Transfer{}).
+		Where("request_id = ?", requestID).
+		First(&record).Error; err != nil {
+		return nil, err
+	}
+	return toTransferResponse(record), nil
+}
+
+func toTransferResponse(record entity.Transfer) *model.TransferResponse {
 	resp := &model.TransferResponse{
 		TransactionID:        int64(record.ID),
 		SourceAccountID:      record.SourceAccountID,
 		DestinationAccountID: record.DestinationAccountID,
-		Amount:               record.Amount,
+		Amount:               record.Amount.String(),
 		CreatedAt:            record.CreatedAt,
 	}
+	if record.RequestID != nil {
+		resp.RequestID = *record.RequestID
+	}
+	return resp
+}
 
-	trKey := fmt.Sprintf("transfer:%d", record.ID)
-	b, _ := json.Marshal(resp)
-	if err := d.cache.Set(ctx, trKey, b, transferTTL).Err(); err != nil {
-		span.RecordError(err)
-		_ = d.cache.Del(ctx, trKey).Err()
+func newTransferRecord(req model.TransferRequest, amount decimal.Money) entity.Transfer {
+	record := entity.Transfer{
+		SourceAccountID:      req.SourceAccountID,
+		DestinationAccountID: req.DestinationAccountID,
+		Amount:               amount,
 	}
+	if req.RequestID != "" {
+		record.RequestID = &req.RequestID
+	}
+	return record
+}
 
-	srcKey := fmt.Sprintf("account:%d", source.AccountID)
-	src := model.AccountGetResponse{AccountID: source.AccountID, Balance: source.Balance}
-	b1, _ := json.Marshal(src)
-	if err := d.cache.Set(ctx, srcKey, b1, accountTTL).Err(); err != nil {
-		span.RecordError(err)
-		_ = d.cache.Del(ctx, srcKey).Err()
+func isDuplicate(err error) bool {
+	var myErr *mysql.MySQLError
+	return errors.As(err, &myErr) && myErr.Number == 1062
+}
+
+// insertTransfer writes the transfer row, its change feed entries, its audit record and its
+// outbox event, or refuses with ErrAccountFrozen so the caller rolls the transfer back.
+// source and dest must hold the post-transfer rows.
+func insertTransfer(tx *gorm.DB, req model.TransferRequest, amount decimal.Money, source, dest entity.Account) (entity.Transfer, error) {
+	if source.Frozen || dest.Frozen {
+		return entity.Transfer{}, ErrAccountFrozen
 	}
 
-	dstKey := fmt.Sprintf("account:%d", dest.AccountID)
-	dst := model.AccountGetResponse{AccountID: dest.AccountID, Balance: dest.Balance}
-	b2, _ := json.Marshal(dst)
-	if err := d.cache.Set(ctx, dstKey, b2, accountTTL).Err(); err != nil {
-		span.RecordError(err)
-		_ = d.cache.Del(ctx, dstKey).Err()
+	record := newTransferRecord(req, amount)
+
+	if err := tx.Model(&entity.Transfer{}).
+		Create(&record).Error; err != nil {
+		return record, err
 	}
 
-	return resp, nil
+	if err := writeTransferChanges(tx, record, source, dest); err != nil {
+		return record, err
+	}
+
+	if err := writeAudit(tx, transferAudit(record, source, dest)); err != nil {
+		return record, err
+	}
+
+	return record, writeTransferEvents(tx, []entity.Transfer{record})
 }
@@ -0,0 +1,416 @@
+package dao
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+	"txn-processor/internal/adapter/outbound/gorm/entity"
+	"txn-processor/internal/core/model"
+	"txn-processor/internal/port"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type webhookDAO struct {
+	*Connections
+}
+
+var _ port.WebhookDao = (*webhookDAO)(nil)
+
+func NewWebhookDAO(conn *Connections) port.WebhookDao {
+	return &webhookDAO{Connections: conn}
+}
+
+func (d *webhookDAO) CreateWebhook(ctx context.Context, hook model.Webhook) (*model.WebhookResponse, error) {
+	ctx, span := d.tracer.Start(ctx, "dao.webhook.create")
+	defer span.End()
+
+	e := entity.WebhookSubscription{
+		URL:        hook.URL,
+		Secret:     hook.Secret,
+		EventTypes: strings.Join(hook.EventTypes, ","),
+		AccountID:  hook.AccountID,
+		Enabled:    true,
+	}
+
+	if err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.WebhookSubscription{}).
+			Create(&e).Error; err != nil {
+			return err
+		}
+		// The response leaves the secret out of the audit record.
+		return writeAudit(tx, model.AuditEntry{
+			Action:       model.AuditWebhookCreate,
+			ResourceType: "webhook",
+			ResourceID:   strconv.FormatUint(uint64(e.ID), 10),
+			After:        toWebhookResponse(e),
+		})
+	}); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	res := toWebhookResponse(e)
+	res.Secret = e.Secret
+	return &res, nil
+}
+
+func (d *webhookDAO) GetWebhook(ctx context.Context, id int64) (*model.WebhookResponse, error) {
+	ctx, span := d.tracer.Start(ctx, "dao.webhook.get")
+	defer span.End()
+
+	var e entity.WebhookSubscription
+	if err := d.db.WithContext(ctx).
+		Model(&entity.WebhookSubscription{}).
+		Where("id = ?", id).
+		First(&e).Error; err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	res := toWebhookResponse(e)
+	return &res, nil
+}
+
+// DeleteWebhook removes the subscription together with its delivery log.
+func (d *webhookDAO) DeleteWebhook(ctx context.Context, id int64) error {
+	ctx, span := d.tracer.Start(ctx, "dao.webhook.delete")
+	defer span.End()
+
+	if err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var e entity.WebhookSubscription
+		if err := tx.Where("id = ?", id).First(&e).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("id = ?", id).Delete(&entity.WebhookSubscription{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("subscription_id = ?", id).Delete(&entity.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		return writeAudit(tx, model.AuditEntry{
+			Action:       model.AuditWebhookDelete,
+			ResourceType: "webhook",
+			ResourceID:   strconv.FormatInt(id, 10),
+			Before:       toWebhookResponse(e),
+		})
+	}); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+// EnableWebhook re-enables a subscription and clears its failure streak.
+// Deliveries still pending resume on the next dispatch.
+func (d *webhookDAO) EnableWebhook(ctx context.Context, id int64) error {
+	ctx, span := d.tracer.Start(ctx, "dao.webhook.enable")
+	defer span.End()
+
+	if err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var e entity.WebhookSubscription
+		if err := tx.Clauses(LockClause).Where("id = ?", id).First(&e).Error; err != nil {
+			return err
+		}
+		before := toWebhookResponse(e)
+
+		if err := tx.Model(&entity.WebhookSubscription{}).
+			Where("id = ?", id).
+			Updates(map[string]any{"enabled": true, "failures": 0, "disabled_at": nil}).Error; err != nil {
+			return err
+		}
+
+		e.Enabled, e.Failures, e.DisabledAt = true, 0, nil
+		return writeAudit(tx, model.AuditEntry{
+			Action:       model.AuditWebhookEnable,
+			ResourceType: "webhook",
+			ResourceID:   strconv.FormatInt(id, 10),
+			Before:       before,
+			After:        toWebhookResponse(e),
+		})
+	}); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+// ActiveWebhooks returns every enabled subscription; the fan-out filters them per event.
+func (d *webhookDAO) ActiveWebhooks(ctx context.Context) ([]model.Webhook, error) {
+	ctx, span := d.tracer.Start(ctx, "dao.webhook.active")
+	defer span.End()
+
+	var rows []entity.WebhookSubscription
+	if err := d.db.WithContext(ctx).
+		Model(&entity.WebhookSubscription{}).
+		Where("enabled = ?", true).
+		Find(&rows).Error; err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	hooks := make([]model.Webhook, 0, len(rows))
+	for _, r := range rows {
+		hooks = append(hooks, toWebhook(r))
+	}
+	return hooks, nil
+}
+
+// EnqueueDeliveries adds deliveries to the log. Existing (subscription, event) pairs are
+// left alone so a republished outbox batch is not delivered twice, unless reset is set,
+// in which case they are rescheduled from scratch as a replay.
+func (d *webhookDAO) EnqueueDeliveries(ctx context.Context, deliveries []model.WebhookDelivery, reset bool) error {
+	ctx, span := d.tracer.Start(ctx, "dao.webhook.enqueue")
+	defer span.End()
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	rows := make([]entity.WebhookDelivery, len(deliveries))
+	for i, dl := range deliveries {
+		rows[i] = entity.WebhookDelivery{
+			SubscriptionID: uint(dl.SubscriptionID),
+			EventID:        uint(dl.EventID),
+			EventType:      dl.EventType,
+			Status:         model.DeliveryPending,
+			NextAttemptAt:  now,
+		}
+	}
+
+	conflict := clause.OnConflict{DoNothing: true}
+	if reset {
+		conflict = clause.OnConflict{DoUpdates: clause.Assignments(map[string]any{
+			"status":          model.DeliveryPending,
+			"attempts":        0,
+			"next_attempt_at": now,
+			"response_code":   0,
+			"last_error":      "",
+			"delivered_at":    nil,
+		})}
+	}
+
+	if err := d.db.WithContext(ctx).
+		Clauses(conflict).
+		Create(&rows).Error; err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+// ClaimDueDeliveries returns up to limit due deliveries of enabled subscriptions and pushes
+// their next attempt lease into the future, so other replicas skip them while they are sent.
+func (d *webhookDAO) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]model.WebhookAttempt, error) {
+	ctx, span := d.tracer.Start(ctx, "dao.webhook.claim")
+	defer span.End()
+
+	now := time.Now()
+
+	var due []entity.WebhookDelivery
+	if err := d.db.WithContext(ctx).
+		Model(&entity.WebhookDelivery{}).
+		Joins("JOIN webhook_subscriptions ON webhook_subscriptions.id = webhook_deliveries.subscription_id").
+		Where("webhook_subscriptions.enabled = ?", true).
+		Where("webhook_deliveries.status = ? AND webhook_deliveries.next_attempt_at <= ?", model.DeliveryPending, now).
+		Order("webhook_deliveries.next_attempt_at").
+		Limit(limit).
+		Find(&due).Error; err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	// Claiming compares the old next_attempt_at, so only one replica wins each row.
+	claimed := make([]entity.WebhookDelivery, 0, len(due))
+	for _, r := range due {
+		res := d.db.WithContext(ctx).
+			Model(&entity.WebhookDelivery{}).
+			Where("id = ? AND status = ? AND next_attempt_at = ?", r.ID, model.DeliveryPending, r.NextAttemptAt).
+			Update("next_attempt_at", now.Add(lease))
+		if res.Error != nil {
+			span.RecordError(res.Error)
+			return nil, res.Error
+		}
+		if res.RowsAffected == 1 {
+			claimed = append(claimed, r)
+		}
+	}
+	if len(claimed) == 0 {
+		return nil, nil
+	}
+
+	subIDs := make([]uint, 0, len(claimed))
+	eventIDs := make([]uint, 0, len(claimed))
+	for _, r := range claimed {
+		subIDs = append(subIDs, r.SubscriptionID)
+		eventIDs = append(eventIDs, r.EventID)
+	}
+
+	var subs []entity.WebhookSubscription
+	if err := d.db.WithContext(ctx).Where("id IN ?", subIDs).Find(&subs).Error; err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	var events []entity.OutboxEvent
+	if err := d.db.WithContext(ctx).Where("id IN ?", eventIDs).Find(&events).Error; err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	subByID := make(map[uint]entity.WebhookSubscription, len(subs))
+	for _, s := range subs {
+		subByID[s.ID] = s
+	}
+	eventByID := make(map[uint]entity.OutboxEvent, len(events))
+	for _, e := range events {
+		eventByID[e.ID] = e
+	}
+
+	attempts := make([]model.WebhookAttempt, 0, len(claimed))
+	for _, r := range claimed {
+		sub, ok := subByID[r.SubscriptionID]
+		if !ok {
+			continue
+		}
+		ev, ok := eventByID[r.EventID]
+		if !ok {
+			continue
+		}
+		attempts = append(attempts, model.WebhookAttempt{
+			Delivery: toDelivery(r),
+			Webhook:  toWebhook(sub),
+			Event:    toEvent(ev),
+		})
+	}
+
+	span.SetAttributes("webhook.claimed", len(attempts))
+	return attempts, nil
+}
+
+// RecordAttempt stores the outcome of an attempt and updates the subscription's failure
+// streak. It reports whether the failure just disabled the subscription.
+func (d *webhookDAO) RecordAttempt(ctx context.Context, dl model.WebhookDelivery, disableAfter int) (bool, error) {
+	ctx, span := d.tracer.Start(ctx, "dao.webhook.record")
+	defer span.End()
+
+	disabled := false
+	err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.WebhookDelivery{}).
+			Where("id = ?", dl.ID).
+			Updates(map[string]any{
+				"status":          dl.Status,
+				"attempts":        dl.Attempts,
+				"next_attempt_at": dl.NextAttemptAt,
+				"response_code":   dl.ResponseCode,
+				"last_error":      truncate(dl.LastError, 512),
+				"delivered_at":    dl.DeliveredAt,
+			}).Error; err != nil {
+			return err
+		}
+
+		sub := tx.Model(&entity.WebhookSubscription{}).Where("id = ?", dl.SubscriptionID)
+		if dl.Status == model.DeliverySucceeded {
+			return sub.Update("failures", 0).Error
+		}
+		if err := sub.Update("failures", gorm.Expr("failures + 1")).Error; err != nil {
+			return err
+		}
+		if disableAfter <= 0 {
+			return nil
+		}
+
+		res := tx.Model(&entity.WebhookSubscription{}).
+			Where("id = ? AND enabled = ? AND failures >= ?", dl.SubscriptionID, true, disableAfter).
+			Updates(map[string]any{"enabled": false, "disabled_at": time.Now()})
+		disabled = res.RowsAffected == 1
+		return res.Error
+	})
+	if err != nil {
+		span.RecordError(err)
+		return false, err
+	}
+
+	return disabled, nil
+}
+
+func (d *webhookDAO) ListDeliveries(ctx context.Context, subscriptionID int64, limit int) ([]model.WebhookDelivery, error) {
+	ctx, span := d.tracer.Start(ctx, "dao.webhook.deliveries")
+	defer span.End()
+
+	var rows []entity.WebhookDelivery
+	if err := d.db.WithContext(ctx).
+		Model(&entity.WebhookDelivery{}).
+		Where("subscription_id = ?", subscriptionID).
+		Order("id DESC").
+		Limit(limit).
+		Find(&rows).Error; err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	out := make([]model.WebhookDelivery, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, toDelivery(r))
+	}
+	return out, nil
+}
+
+func toWebhook(e entity.WebhookSubscription) model.Webhook {
+	return model.Webhook{
+		ID:         int64(e.ID),
+		URL:        e.URL,
+		Secret:     e.Secret,
+		EventTypes: splitEventTypes(e.EventTypes),
+		AccountID:  e.AccountID,
+		Enabled:    e.Enabled,
+	}
+}
+
+func toWebhookResponse(e entity.WebhookSubscription) model.WebhookResponse {
+	return model.WebhookResponse{
+		ID:         int64(e.ID),
+		URL:        e.URL,
+		EventTypes: splitEventTypes(e.EventTypes),
+		AccountID:  e.AccountID,
+		Enabled:    e.Enabled,
+		Failures:   e.Failures,
+		DisabledAt: e.DisabledAt,
+		CreatedAt:  e.CreatedAt,
+	}
+}
+
+func toDelivery(e entity.WebhookDelivery) model.WebhookDelivery {
+	return model.WebhookDelivery{
+		ID:             int64(e.ID),
+		SubscriptionID: int64(e.SubscriptionID),
+		EventID:        int64(e.EventID),
+		EventType:      e.EventType,
+		Status:         e.Status,
+		Attempts:       e.Attempts,
+		NextAttemptAt:  e.NextAttemptAt,
+		ResponseCode:   e.ResponseCode,
+		LastError:      e.LastError,
+		DeliveredAt:    e.DeliveredAt,
+		CreatedAt:      e.CreatedAt,
+	}
+}
+
+func splitEventTypes(s string) []string {
+	if s == "" {
+		return []string{}
+	}
+	return strings.Split(s, ",")
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
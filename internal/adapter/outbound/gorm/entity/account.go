@@ -1,9 +1,33 @@
 package entity
 
-import "gorm.io/gorm"
+import (
+	"time"
+	"txn-processor/pkg/decimal"
+
+	"gorm.io/gorm"
+)
 
 type Account struct {
 	gorm.Model
-	AccountID int64  `gorm:"uniqueIndex;not null"`
-	Balance   string `gorm:"type:numeric;not null"`
+	AccountID int64         `gorm:"uniqueIndex;not null"`
+	Balance   decimal.Money `gorm:"type:decimal(20,4);not null"`
+	Version   int64         `gorm:"not null;default:0"`
+
+	// ShardCount > 0 marks a hot account whose balance is split across AccountShard rows.
+	ShardCount int `gorm:"not null;default:0"`
+
+	// Owner is the subject that created the account. Accounts created without authentication
+	// have none and are reachable by admins only.
+	Owner string `gorm:"type:varchar(128);not null;default:'';index"`
+
+	// Frozen blocks transfers from and to the account until an operator unfreezes it.
+	Frozen bool `gorm:"not null;default:false"`
+}
+
+// AccountGrant delegates access to an account to another subject.
+type AccountGrant struct {
+	ID        uint      `gorm:"primaryKey"`
+	AccountID int64     `gorm:"not null;uniqueIndex:idx_account_grant,priority:1"`
+	Subject   string    `gorm:"type:varchar(128);not null;uniqueIndex:idx_account_grant,priority:2;index"`
+	CreatedAt time.Time `gorm:"not null"`
 }
@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"time"
+	"txn-processor/pkg/decimal"
+)
+
+// AccountChange is one entry of an account's change feed and the durable event log the
+// accounts table can be rebuilt from. Rows are only ever inserted, apart from numbering.
+// Plain accounts number their changes in the same transaction; hot accounts leave Version
+// and Balance nil until the sequencer runs.
+type AccountChange struct {
+	ID         uint           `gorm:"primaryKey"`
+	AccountID  int64          `gorm:"not null;uniqueIndex:idx_account_change_version,priority:1"`
+	Version    *int64         `gorm:"uniqueIndex:idx_account_change_version,priority:2;index:idx_account_change_pending"`
+	Kind       string         `gorm:"type:varchar(16);not null"`
+	Amount     decimal.Money  `gorm:"type:decimal(20,4);not null"`
+	Balance    *decimal.Money `gorm:"type:decimal(20,4)"`
+	TransferID *uint          `gorm:"index"`
+	CreatedAt  time.Time
+
+	// Shards is the shard count a reshard or opening entry left the account with.
+	Shards int `gorm:"not null;default:0"`
+}
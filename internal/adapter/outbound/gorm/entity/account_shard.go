@@ -0,0 +1,16 @@
+package entity
+
+import (
+	"txn-processor/pkg/decimal"
+
+	"gorm.io/gorm"
+)
+
+// AccountShard holds one slice of a hot account's balance.
+// The account total is Account.Balance plus the sum of its shards.
+type AccountShard struct {
+	gorm.Model
+	AccountID int64         `gorm:"uniqueIndex:idx_account_shard;not null"`
+	Shard     int           `gorm:"uniqueIndex:idx_account_shard;not null"`
+	Balance   decimal.Money `gorm:"type:decimal(20,4);not null"`
+}
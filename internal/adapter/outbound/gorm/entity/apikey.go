@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+// APIKey stores the hash of an issued key. KeyID is the public part of the key and the lookup
+// index; the secret part never reaches the database.
+type APIKey struct {
+	ID      uint   `gorm:"primaryKey"`
+	KeyID   string `gorm:"type:varchar(32);not null;uniqueIndex"`
+	Hash    string `gorm:"type:char(64);not null"`
+	Name    string `gorm:"type:varchar(128);not null"`
+	Subject string `gorm:"type:varchar(128);not null;index"`
+	// Scopes is comma separated. Keys issued before scopes existed get the default set.
+	Scopes    string     `gorm:"type:varchar(255);not null;default:'accounts:read,accounts:write,transfers:create'"`
+	CreatedAt time.Time  `gorm:"not null"`
+	RevokedAt *time.Time `gorm:"index"`
+}
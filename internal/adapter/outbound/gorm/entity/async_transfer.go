@@ -0,0 +1,19 @@
+package entity
+
+import (
+	"time"
+	"txn-processor/pkg/decimal"
+)
+
+// AsyncTransfer tracks a transfer accepted with mode=async until a worker settles it.
+// Success is recorded by the Transfer row carrying the same RequestID.
+type AsyncTransfer struct {
+	ID                   string        `gorm:"type:varchar(64);primaryKey"`
+	SourceAccountID      int64         `gorm:"not null"`
+	DestinationAccountID int64         `gorm:"not null"`
+	Amount               decimal.Money `gorm:"type:decimal(20,4);not null"`
+	Status               string        `gorm:"type:varchar(16);not null;index"`
+	FailureReason        string        `gorm:"type:varchar(255)"`
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+}
@@ -0,0 +1,19 @@
+package entity
+
+import "time"
+
+// AuditRecord is one entry of the append-only audit log. Rows are never updated or deleted;
+// triggers reject both where the database allows creating them.
+type AuditRecord struct {
+	ID            uint      `gorm:"primaryKey"`
+	Action        string    `gorm:"type:varchar(64);not null;index"`
+	ResourceType  string    `gorm:"type:varchar(32);not null;index:idx_audit_resource,priority:1"`
+	ResourceID    string    `gorm:"type:varchar(64);not null;index:idx_audit_resource,priority:2"`
+	Actor         string    `gorm:"type:varchar(128);not null;index"`
+	CorrelationID string    `gorm:"type:varchar(64);index"`
+	SourceIP      string    `gorm:"type:varchar(64)"`
+	TraceID       string    `gorm:"type:varchar(32)"`
+	Before        string    `gorm:"type:text"`
+	After         string    `gorm:"type:text"`
+	CreatedAt     time.Time `gorm:"not null"`
+}
@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+// ChainHead is the single row holding the last link of the transfer hash chain. Chainers lock
+// it, so replicas extend the chain one at a time.
+type ChainHead struct {
+	ID   uint   `gorm:"primaryKey"`
+	Seq  int64  `gorm:"not null;default:0"`
+	Hash string `gorm:"type:char(64);not null"`
+}
+
+// ChainCheckpoint is a signed statement of the chain head at Seq.
+type ChainCheckpoint struct {
+	ID        uint      `gorm:"primaryKey"`
+	Seq       int64     `gorm:"not null;uniqueIndex"`
+	Hash      string    `gorm:"type:char(64);not null"`
+	KeyID     string    `gorm:"type:varchar(64);not null"`
+	Signature string    `gorm:"type:varchar(128);not null"`
+	CreatedAt time.Time `gorm:"not null"`
+}
@@ -0,0 +1,27 @@
+package entity
+
+import "time"
+
+// ImportJob tracks a bulk import. Rows is the last input row applied, so a resumed upload
+// skips the rows up to it.
+type ImportJob struct {
+	ID        string `gorm:"type:varchar(64);primaryKey"`
+	Kind      string `gorm:"type:varchar(16);not null"`
+	Status    string `gorm:"type:varchar(16);not null"`
+	Rows      int64  `gorm:"column:applied_rows;not null;default:0"`
+	Created   int64  `gorm:"not null;default:0"`
+	Existing  int64  `gorm:"not null;default:0"`
+	Failed    int64  `gorm:"not null;default:0"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// ImportRow is the outcome of one input row of a job.
+type ImportRow struct {
+	JobID         string `gorm:"type:varchar(64);primaryKey"`
+	Row           int64  `gorm:"column:row_num;primaryKey;autoIncrement:false"`
+	Status        string `gorm:"type:varchar(16);not null"`
+	AccountID     int64  `gorm:"not null;default:0"`
+	TransactionID int64  `gorm:"not null;default:0"`
+	Error         string `gorm:"type:varchar(255);not null;default:''"`
+}
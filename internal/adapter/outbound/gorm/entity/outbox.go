@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+// OutboxEvent is written in the same transaction as the change it describes
+// and published later by the relay. SentAt is nil until a publish succeeds.
+type OutboxEvent struct {
+	ID        uint   `gorm:"primaryKey"`
+	EventType string `gorm:"type:varchar(64);not null"`
+	AccountID int64  `gorm:"not null;index"`
+	Payload   string `gorm:"type:text;not null"`
+	Headers   string `gorm:"type:text"`
+	CreatedAt time.Time
+	SentAt    *time.Time `gorm:"index"`
+}
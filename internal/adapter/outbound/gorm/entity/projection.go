@@ -0,0 +1,27 @@
+package entity
+
+import (
+	"time"
+	"txn-processor/pkg/decimal"
+)
+
+// AccountProjection is the accounts table as rebuilt from the change log. Balance is the
+// total including shards.
+type AccountProjection struct {
+	AccountID  int64         `gorm:"primaryKey;autoIncrement:false"`
+	Balance    decimal.Money `gorm:"type:decimal(20,4);not null"`
+	Version    int64         `gorm:"not null"`
+	ShardCount int           `gorm:"not null"`
+	Changes    int64         `gorm:"not null"`
+}
+
+// DailyBalance is an account's opening and closing balance on a UTC day with that day's
+// transfer turnover. Days without changes have no row.
+type DailyBalance struct {
+	AccountID int64         `gorm:"primaryKey;autoIncrement:false"`
+	Day       time.Time     `gorm:"primaryKey;type:date"`
+	Opening   decimal.Money `gorm:"type:decimal(20,4);not null"`
+	Closing   decimal.Money `gorm:"type:decimal(20,4);not null"`
+	Credits   decimal.Money `gorm:"type:decimal(20,4);not null"`
+	Debits    decimal.Money `gorm:"type:decimal(20,4);not null"`
+}
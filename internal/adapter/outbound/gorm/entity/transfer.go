@@ -1,10 +1,21 @@
 package entity
 
-import "gorm.io/gorm"
+import (
+	"txn-processor/pkg/decimal"
+
+	"gorm.io/gorm"
+)
 
 type Transfer struct {
 	gorm.Model
-	SourceAccountID      int64  `gorm:"not null"`
-	DestinationAccountID int64  `gorm:"not null"`
-	Amount               string `gorm:"type:numeric;not null"`
+	RequestID            *string       `gorm:"type:varchar(64);uniqueIndex"`
+	SourceAccountID      int64         `gorm:"not null"`
+	DestinationAccountID int64         `gorm:"not null"`
+	Amount               decimal.Money `gorm:"type:decimal(20,4);not null"`
+
+	// ChainSeq, PrevHash and Hash place the transfer in the hash chain. They are set by the
+	// chainer shortly after commit; Hash covers the canonical content and PrevHash.
+	ChainSeq *int64 `gorm:"uniqueIndex"`
+	PrevHash string `gorm:"type:char(64);not null;default:''"`
+	Hash     string `gorm:"type:char(64);not null;default:''"`
 }
@@ -0,0 +1,34 @@
+package entity
+
+import "time"
+
+// WebhookSubscription is a partner endpoint. EventTypes is a comma-separated list, empty for all.
+// Failures counts consecutive failed attempts; reaching the limit clears Enabled.
+type WebhookSubscription struct {
+	ID         uint   `gorm:"primaryKey"`
+	URL        string `gorm:"type:varchar(2048);not null"`
+	Secret     string `gorm:"type:varchar(128);not null"`
+	EventTypes string `gorm:"type:varchar(512);not null;default:''"`
+	AccountID  *int64 `gorm:"index"`
+	Enabled    bool   `gorm:"not null;default:true"`
+	Failures   int    `gorm:"not null;default:0"`
+	DisabledAt *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// WebhookDelivery is the delivery log: one row per subscription and outbox event.
+type WebhookDelivery struct {
+	ID             uint      `gorm:"primaryKey"`
+	SubscriptionID uint      `gorm:"not null;uniqueIndex:idx_webhook_delivery"`
+	EventID        uint      `gorm:"not null;uniqueIndex:idx_webhook_delivery"`
+	EventType      string    `gorm:"type:varchar(64);not null"`
+	Status         string    `gorm:"type:varchar(16);not null;index:idx_webhook_due,priority:1"`
+	Attempts       int       `gorm:"not null;default:0"`
+	NextAttemptAt  time.Time `gorm:"not null;index:idx_webhook_due,priority:2"`
+	ResponseCode   int
+	LastError      string `gorm:"type:varchar(512)"`
+	DeliveredAt    *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
@@ -0,0 +1,24 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"txn-processor/internal/core/model"
+	"txn-processor/internal/port"
+)
+
+// Publisher writes events to the application log. It is the default when no broker is configured.
+type Publisher struct{}
+
+var _ port.EventPublisher = new(Publisher)
+
+func NewPublisher() *Publisher {
+	return &Publisher{}
+}
+
+func (p *Publisher) Publish(ctx context.Context, events []model.Event) error {
+	for _, e := range events {
+		slog.InfoContext(ctx, "Domain event", "id", e.ID, "type", e.Type, "account_id", e.AccountID, "payload", string(e.Payload))
+	}
+	return nil
+}
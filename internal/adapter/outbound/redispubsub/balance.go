@@ -0,0 +1,197 @@
+package redispubsub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"txn-processor/config"
+	"txn-processor/internal/core/model"
+	"txn-processor/internal/port"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const channelPrefix = "balance:"
+
+// Broker broadcasts balance updates through Redis pub/sub. Each replica holds a single
+// pattern subscription and fans messages out to its local subscribers.
+type Broker struct {
+	client  *redis.Client
+	history int64
+	buffer  int
+
+	mu     sync.Mutex
+	subs   map[int64]map[chan model.BalanceUpdate]struct{}
+	pubsub *redis.PubSub
+	wg     sync.WaitGroup
+}
+
+var _ port.BalanceBroker = new(Broker)
+
+func NewBroker(client *redis.Client, conf config.Realtime) *Broker {
+	return &Broker{
+		client:  client,
+		history: max(conf.History, 1),
+		buffer:  max(conf.BufferSize, 1),
+		subs:    make(map[int64]map[chan model.BalanceUpdate]struct{}),
+	}
+}
+
+func (b *Broker) Publish(ctx context.Context, update model.BalanceUpdate) (model.BalanceUpdate, error) {
+	seq, err := b.client.Incr(ctx, seqKey(update.AccountID)).Result()
+	if err != nil {
+		return update, err
+	}
+	update.Seq = seq
+
+	msg, err := json.Marshal(update)
+	if err != nil {
+		return update, err
+	}
+
+	hist := historyKey(update.AccountID)
+	_, err = b.client.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		p.ZAdd(ctx, hist, &redis.Z{Score: float64(seq), Member: msg})
+		p.ZRemRangeByRank(ctx, hist, 0, -b.history-1)
+		p.Publish(ctx, channelPrefix+strconv.FormatInt(update.AccountID, 10), msg)
+		return nil
+	})
+	return update, err
+}
+
+func (b *Broker) Subscribe(ctx context.Context, accountID int64) (<-chan model.BalanceUpdate, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.pubsub == nil {
+		ps := b.client.PSubscribe(context.Background(), channelPrefix+"*")
+		if _, err := ps.Receive(ctx); err != nil {
+			_ = ps.Close()
+			return nil, err
+		}
+		b.pubsub = ps
+		b.wg.Add(1)
+		go b.listen(ps)
+	}
+
+	ch := make(chan model.BalanceUpdate, b.buffer)
+	if b.subs[accountID] == nil {
+		b.subs[accountID] = make(map[chan model.BalanceUpdate]struct{})
+	}
+	b.subs[accountID][ch] = struct{}{}
+
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		b.drop(accountID, ch)
+		b.mu.Unlock()
+	}()
+
+	return ch, nil
+}
+
+func (b *Broker) History(ctx context.Context, accountID int64, afterSeq int64) ([]model.BalanceUpdate, error) {
+	raw, err := b.client.ZRangeByScore(ctx, historyKey(accountID), &redis.ZRangeBy{
+		Min: "(" + strconv.FormatInt(afterSeq, 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]model.BalanceUpdate, 0, len(raw))
+	for _, r := range raw {
+		var u model.BalanceUpdate
+		if err := json.Unmarshal([]byte(r), &u); err != nil {
+			return nil, err
+		}
+		out = append(out, u)
+	}
+	return out, nil
+}
+
+func (b *Broker) LastSeq(ctx context.Context, accountID int64) (int64, error) {
+	seq, err := b.client.Get(ctx, seqKey(accountID)).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	return seq, err
+}
+
+// Close stops the subscription and closes every subscriber channel.
+func (b *Broker) Close() error {
+	b.mu.Lock()
+	ps := b.pubsub
+	b.pubsub = nil
+	b.mu.Unlock()
+
+	var err error
+	if ps != nil {
+		err = ps.Close()
+		b.wg.Wait()
+	}
+
+	b.mu.Lock()
+	for id, chans := range b.subs {
+		for ch := range chans {
+			b.drop(id, ch)
+		}
+	}
+	b.mu.Unlock()
+
+	return err
+}
+
+func (b *Broker) listen(ps *redis.PubSub) {
+	defer b.wg.Done()
+
+	for msg := range ps.Channel() {
+		id, err := strconv.ParseInt(strings.TrimPrefix(msg.Channel, channelPrefix), 10, 64)
+		if err != nil {
+			continue
+		}
+
+		var u model.BalanceUpdate
+		if err := json.Unmarshal([]byte(msg.Payload), &u); err != nil {
+			slog.Warn("dropping malformed balance update", "channel", msg.Channel, "error", err)
+			continue
+		}
+
+		b.mu.Lock()
+		for ch := range b.subs[id] {
+			select {
+			case ch <- u:
+			default:
+				// A subscriber this far behind reconnects and resumes from history instead.
+				b.drop(id, ch)
+			}
+		}
+		b.mu.Unlock()
+	}
+}
+
+// drop unregisters and closes ch. Callers hold b.mu.
+func (b *Broker) drop(accountID int64, ch chan model.BalanceUpdate) {
+	chans := b.subs[accountID]
+	if _, ok := chans[ch]; !ok {
+		return
+	}
+	delete(chans, ch)
+	close(ch)
+	if len(chans) == 0 {
+		delete(b.subs, accountID)
+	}
+}
+
+func seqKey(accountID int64) string {
+	return fmt.Sprintf("balance:%d:seq", accountID)
+}
+
+func historyKey(accountID int64) string {
+	return fmt.Sprintf("balance:%d:history", accountID)
+}
@@ -0,0 +1,83 @@
+package redisratelimit
+
+import (
+	"context"
+	"time"
+	"txn-processor/internal/core/model"
+	"txn-processor/internal/port"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const keyPrefix = "ratelimit:"
+
+// gcra implements the generic cell rate algorithm, a token bucket that stores a single
+// timestamp per key: the theoretical arrival time (TAT) of the next request. Times are
+// microseconds from the Redis clock, so replicas with skewed clocks still agree.
+//
+// KEYS[1] key; ARGV[1] emission interval (period / limit); ARGV[2] period; ARGV[3] 1 to count
+// the request, 0 to only report whether it would be allowed.
+// Returns {allowed, remaining, reset, retry_after}, durations in microseconds.
+var gcra = redis.NewScript(`
+redis.replicate_commands()
+local t = redis.call("TIME")
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+local interval = tonumber(ARGV[1])
+local period = tonumber(ARGV[2])
+
+local tat = tonumber(redis.call("GET", KEYS[1]) or now)
+if tat < now then
+  tat = now
+end
+
+local new_tat = tat + interval
+local allow_at = new_tat - period
+if now < allow_at then
+  return {0, 0, tat - now, allow_at - now}
+end
+
+local ttl = new_tat - now
+if ARGV[3] == "0" then
+  return {1, math.floor((period - tat + now) / interval), tat - now, 0}
+end
+-- Format explicitly: Lua would print a 16-digit number in exponent form.
+redis.call("SET", KEYS[1], string.format("%d", new_tat), "PX", math.ceil(ttl / 1000))
+return {1, math.floor((period - ttl) / interval), ttl, 0}
+`)
+
+// Limiter keeps rate limits in Redis with one key per caller.
+type Limiter struct {
+	client *redis.Client
+}
+
+var _ port.RateLimiter = new(Limiter)
+
+func NewLimiter(client *redis.Client) *Limiter {
+	return &Limiter{client: client}
+}
+
+func (l *Limiter) Allow(ctx context.Context, key string, limit model.RateLimit) (model.RateLimitResult, error) {
+	return l.run(ctx, key, limit, 1)
+}
+
+func (l *Limiter) Peek(ctx context.Context, key string, limit model.RateLimit) (model.RateLimitResult, error) {
+	return l.run(ctx, key, limit, 0)
+}
+
+func (l *Limiter) run(ctx context.Context, key string, limit model.RateLimit, count int) (model.RateLimitResult, error) {
+	period := limit.Period.Microseconds()
+	interval := max(period/int64(max(limit.Limit, 1)), 1)
+
+	res, err := gcra.Run(ctx, l.client, []string{keyPrefix + key}, interval, period, count).Int64Slice()
+	if err != nil {
+		return model.RateLimitResult{}, err
+	}
+
+	return model.RateLimitResult{
+		Allowed:    res[0] == 1,
+		Limit:      limit.Limit,
+		Remaining:  int(res[1]),
+		Reset:      time.Duration(res[2]) * time.Microsecond,
+		RetryAfter: time.Duration(res[3]) * time.Microsecond,
+	}, nil
+}
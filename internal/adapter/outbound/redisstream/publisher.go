@@ -0,0 +1,50 @@
+package redisstream
+
+import (
+	"context"
+	"strconv"
+	"time"
+	"txn-processor/config"
+	"txn-processor/internal/core/model"
+	"txn-processor/internal/port"
+	"txn-processor/pkg/stream"
+	"txn-processor/pkg/tracing"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Publisher writes domain events to Redis Streams, partitioned by account ID.
+type Publisher struct {
+	producer   *stream.Producer
+	prefix     string
+	partitions int
+}
+
+var _ port.EventPublisher = new(Publisher)
+
+func NewPublisher(client redis.UniversalClient, conf config.Stream) *Publisher {
+	return &Publisher{
+		producer:   stream.NewProducer(client, conf.MaxLen),
+		prefix:     conf.EventsPrefix,
+		partitions: conf.Partitions,
+	}
+}
+
+// Publish continues the trace of the transaction that wrote each event, so the producer span
+// sits under the original request in Jaeger rather than under the relay.
+func (p *Publisher) Publish(ctx context.Context, events []model.Event) error {
+	for _, e := range events {
+		ectx := tracing.Extract(ctx, e.Headers)
+
+		if _, err := p.producer.Publish(ectx, stream.Partition(p.prefix, e.AccountID, p.partitions), map[string]string{
+			"event_id":   strconv.FormatInt(e.ID, 10),
+			"type":       e.Type,
+			"account_id": strconv.FormatInt(e.AccountID, 10),
+			"payload":    string(e.Payload),
+			"created_at": e.CreatedAt.UTC().Format(time.RFC3339Nano),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+	"txn-processor/internal/core/model"
+	"txn-processor/internal/port"
+	"txn-processor/pkg/tracing"
+	"txn-processor/pkg/webhook"
+)
+
+// Sender POSTs events as signed JSON over HTTP.
+type Sender struct {
+	client *http.Client
+	now    func() time.Time
+}
+
+var _ port.WebhookSender = new(Sender)
+
+func NewSender(timeout time.Duration) *Sender {
+	return &Sender{
+		client: &http.Client{Timeout: timeout},
+		now:    time.Now,
+	}
+}
+
+func (s *Sender) Send(ctx context.Context, hook model.Webhook, event model.Event) (int, error) {
+	body, err := json.Marshal(model.WebhookPayload{
+		ID:        event.ID,
+		Type:      event.Type,
+		AccountID: event.AccountID,
+		CreatedAt: event.CreatedAt,
+		Data:      event.Payload,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	for k, v := range tracing.Inject(ctx) {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "txn-processor-webhooks")
+	req.Header.Set(webhook.HeaderEventID, strconv.FormatInt(event.ID, 10))
+	req.Header.Set(webhook.HeaderEventType, event.Type)
+	req.Header.Set(webhook.HeaderSignature, webhook.Sign(hook.Secret, s.now(), body))
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("webhook endpoint returned %d", res.StatusCode)
+	}
+	return res.StatusCode, nil
+}
@@ -0,0 +1,89 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"txn-processor/internal/adapter/outbound/webhook"
+	"txn-processor/internal/core/model"
+	sig "txn-processor/pkg/webhook"
+
+	"github.com/stretchr/testify/require"
+)
+
+type received struct {
+	header http.Header
+	body   []byte
+}
+
+func receiver(t *testing.T, status int) (*httptest.Server, <-chan received) {
+	t.Helper()
+
+	ch := make(chan received, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		ch <- received{header: r.Header.Clone(), body: body}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, ch
+}
+
+func event() model.Event {
+	return model.Event{
+		ID:        42,
+		Type:      model.EventTransferCompleted,
+		AccountID: 1001,
+		Payload:   json.RawMessage(`{"transaction_id":7,"source_account_id":1001,"destination_account_id":2002,"amount":"1.5"}`),
+		CreatedAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+}
+
+func TestSendSignsPayload(t *testing.T) {
+	srv, ch := receiver(t, http.StatusNoContent)
+	hook := model.Webhook{ID: 1, URL: srv.URL, Secret: "whsec_test"}
+
+	code, err := webhook.NewSender(time.Second).Send(context.Background(), hook, event())
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNoContent, code)
+
+	got := <-ch
+	require.Equal(t, "42", got.header.Get(sig.HeaderEventID))
+	require.Equal(t, model.EventTransferCompleted, got.header.Get(sig.HeaderEventType))
+	require.NoError(t, sig.Verify("whsec_test", got.header.Get(sig.HeaderSignature), got.body, time.Minute))
+	require.ErrorIs(t, sig.Verify("other", got.header.Get(sig.HeaderSignature), got.body, time.Minute), sig.ErrInvalidSignature)
+
+	var payload model.WebhookPayload
+	require.NoError(t, json.Unmarshal(got.body, &payload))
+	require.Equal(t, int64(42), payload.ID)
+	require.Equal(t, int64(1001), payload.AccountID)
+	require.JSONEq(t, string(event().Payload), string(payload.Data))
+}
+
+func TestSendFailsOnNon2xx(t *testing.T) {
+	srv, ch := receiver(t, http.StatusServiceUnavailable)
+	hook := model.Webhook{ID: 1, URL: srv.URL, Secret: "whsec_test"}
+
+	code, err := webhook.NewSender(time.Second).Send(context.Background(), hook, event())
+	require.Error(t, err)
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	<-ch
+}
+
+func TestVerifyRejectsTamperingAndStaleSignatures(t *testing.T) {
+	body := []byte(`{"id":1}`)
+
+	header := sig.Sign("s3cret", time.Now(), body)
+	require.NoError(t, sig.Verify("s3cret", header, body, time.Minute))
+	require.ErrorIs(t, sig.Verify("s3cret", header, []byte(`{"id":2}`), time.Minute), sig.ErrInvalidSignature)
+	require.ErrorIs(t, sig.Verify("s3cret", "garbage", body, time.Minute), sig.ErrMalformedSignature)
+
+	stale := sig.Sign("s3cret", time.Now().Add(-time.Hour), body)
+	require.ErrorIs(t, sig.Verify("s3cret", stale, body, time.Minute), sig.ErrExpiredSignature)
+	require.NoError(t, sig.Verify("s3cret", stale, body, 0))
+}
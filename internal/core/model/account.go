@@ -1,8 +1,12 @@
 package model
 
+import "time"
+
 type AccountCreateRequest struct {
 	AccountID      int64  `json:"account_id"`
 	InitialBalance string `json:"initial_balance"`
+	// Owner defaults to the caller; only admins may create accounts for someone else.
+	Owner string `json:"owner,omitempty"`
 }
 
 type AccountCreateResponse struct {
@@ -12,4 +16,92 @@ type AccountCreateResponse struct {
 type AccountGetResponse struct {
 	AccountID int64  `json:"account_id"`
 	Balance   string `json:"balance"`
+	Version   int64  `json:"version"`
+	Owner     string `json:"owner,omitempty"`
+	// Frozen accounts can neither send nor receive transfers.
+	Frozen bool `json:"frozen,omitempty"`
+}
+
+// AccountListResponse is one page of accounts in account ID order. Pass NextAfterID as the
+// after_id of the next request.
+type AccountListResponse struct {
+	Accounts    []AccountGetResponse `json:"accounts"`
+	NextAfterID int64                `json:"next_after_id,omitempty"`
+}
+
+type AccountFreezeResponse struct {
+	AccountID int64 `json:"account_id"`
+	Frozen    bool  `json:"frozen"`
+}
+
+// ReconcileReport checks each account's balance against the sum of its change log, and the
+// total of all balances against the money ever issued by account creation.
+type ReconcileReport struct {
+	Accounts   int64               `json:"accounts"`
+	Total      string              `json:"total"`
+	Issued     string              `json:"issued"`
+	Mismatches []ReconcileMismatch `json:"mismatches"`
+}
+
+// ReconcileMismatch is an account whose live balance differs from its change log. Live is
+// "missing" for changes of an account that no longer exists.
+type ReconcileMismatch struct {
+	AccountID int64  `json:"account_id"`
+	Live      string `json:"live"`
+	Ledger    string `json:"ledger"`
+}
+
+// How a caller may act on an account: as its owner, as a delegate of the owner, or not at all.
+const (
+	AccessNone     = ""
+	AccessOwner    = "owner"
+	AccessDelegate = "delegate"
+)
+
+type AccountShardRequest struct {
+	Shards int `json:"shards"`
+}
+
+type AccountShardResponse struct {
+	AccountID int64 `json:"account_id"`
+	Shards    int   `json:"shards"`
+}
+
+// BalanceUpdate is pushed to balance stream clients after a change commits.
+// Seq increases by one per update of the account and doubles as the SSE event ID.
+type BalanceUpdate struct {
+	AccountID int64     `json:"account_id"`
+	Seq       int64     `json:"seq"`
+	Balance   string    `json:"balance"`
+	EventID   int64     `json:"event_id,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+const (
+	ChangeCreated = "created"
+	ChangeDebit   = "debit"
+	ChangeCredit  = "credit"
+	ChangeReshard = "reshard"
+
+	// ChangeOpening carries the balance of an account that predates the change log.
+	ChangeOpening = "opening"
+)
+
+// AccountChange is one entry of the per-account change feed. Versions have no gaps.
+// Balance is omitted for changes to hot accounts, whose intermediate totals are not tracked.
+type AccountChange struct {
+	Version    int64     `json:"version"`
+	Kind       string    `json:"kind"`
+	Amount     string    `json:"amount"`
+	Balance    string    `json:"balance,omitempty"`
+	TransferID int64     `json:"transfer_id,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type AccountChangesResponse struct {
+	AccountID int64           `json:"account_id"`
+	Changes   []AccountChange `json:"changes"`
+	// Next is the since value for the following page.
+	Next    int64 `json:"next"`
+	HasMore bool  `json:"has_more"`
 }
@@ -0,0 +1,41 @@
+package model
+
+import "time"
+
+// APIKey is a stored API key. Only the SHA-256 of the key is kept; ID is the public part of
+// the key used to look it up.
+type APIKey struct {
+	ID        string     `json:"id"`
+	Hash      string     `json:"-"`
+	Name      string     `json:"name"`
+	Subject   string     `json:"subject"`
+	Scopes    []string   `json:"scopes"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+type APIKeyIssueRequest struct {
+	Name    string   `json:"name"`
+	Subject string   `json:"subject"`
+	Scopes  []string `json:"scopes"`
+}
+
+// APIKeyIssueResponse is the only time the plain key is shown.
+type APIKeyIssueResponse struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+// Credentials are what a request presented to authenticate. At most one is set.
+type Credentials struct {
+	APIKey      string
+	BearerToken string
+}
+
+// SignedRequest is a request authenticated by its reqsign signature header.
+type SignedRequest struct {
+	Signature string
+	Method    string
+	URI       string
+	Body      []byte
+}
@@ -0,0 +1,65 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Audited actions. Each names the resource type and the operation.
+const (
+	AuditAccountCreate   = "account.create"
+	AuditAccountShards   = "account.shards"
+	AuditAccountGrant    = "account.grant"
+	AuditAccountRevoke   = "account.revoke"
+	AuditAccountFreeze   = "account.freeze"
+	AuditAccountUnfreeze = "account.unfreeze"
+	AuditTransferCreate  = "transfer.create"
+	AuditTransferSubmit  = "transfer.submit"
+	AuditImportStart     = "import.start"
+	AuditWebhookCreate   = "webhook.create"
+	AuditWebhookDelete   = "webhook.delete"
+	AuditWebhookEnable   = "webhook.enable"
+	AuditWebhookReplay   = "webhook.replay"
+	AuditAPIKeyIssue     = "api_key.issue"
+	AuditAPIKeyRevoke    = "api_key.revoke"
+)
+
+// AuditEntry is what a mutating operation records about itself. The actor, correlation ID,
+// source IP and trace ID are taken from the context it is written with.
+type AuditEntry struct {
+	Action       string
+	ResourceType string
+	ResourceID   string
+	Before       any
+	After        any
+}
+
+type AuditRecord struct {
+	ID            int64           `json:"id"`
+	Action        string          `json:"action"`
+	ResourceType  string          `json:"resource_type"`
+	ResourceID    string          `json:"resource_id"`
+	Actor         string          `json:"actor"`
+	CorrelationID string          `json:"correlation_id,omitempty"`
+	SourceIP      string          `json:"source_ip,omitempty"`
+	TraceID       string          `json:"trace_id,omitempty"`
+	Before        json.RawMessage `json:"before,omitempty"`
+	After         json.RawMessage `json:"after,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// AuditFilter selects audit records, newest first. Empty fields match everything; BeforeID
+// pages backwards from a previous response's NextBeforeID.
+type AuditFilter struct {
+	Action       string
+	ResourceType string
+	ResourceID   string
+	Actor        string
+	BeforeID     int64
+	Limit        int
+}
+
+type AuditListResponse struct {
+	Records      []AuditRecord `json:"records"`
+	NextBeforeID int64         `json:"next_before_id,omitempty"`
+}
@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// ChainHead is the last link of the transfer hash chain.
+type ChainHead struct {
+	Seq  int64
+	Hash string
+}
+
+// ChainCheckpoint is a signed statement that the chain had Hash at Seq.
+type ChainCheckpoint struct {
+	Seq       int64
+	Hash      string
+	KeyID     string
+	Signature string
+	CreatedAt time.Time
+}
@@ -0,0 +1,16 @@
+package model
+
+// ErrorResponse is the body of every HTTP error. Details are set when a request does not
+// match the OpenAPI document.
+type ErrorResponse struct {
+	Error   string        `json:"error"`
+	Details []ErrorDetail `json:"details,omitempty"`
+}
+
+// ErrorDetail names one offending value: where it was ("path", "query", "header" or "body"),
+// the parameter or JSON path, and why it was refused.
+type ErrorDetail struct {
+	In     string `json:"in"`
+	Field  string `json:"field,omitempty"`
+	Reason string `json:"reason"`
+}
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	EventAccountCreated       = "AccountCreated"
+	EventAccountShardsChanged = "AccountShardsChanged"
+	EventTransferCompleted    = "TransferCompleted"
+)
+
+// Event is a domain event recorded in the outbox in the same transaction as the change.
+type Event struct {
+	ID        int64           `json:"id"`
+	Type      string          `json:"type"`
+	AccountID int64           `json:"account_id"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"created_at"`
+
+	// Headers carries the W3C trace context of the transaction that wrote the event.
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// EventListResponse is one page of outbox events in commit order. Pass NextAfterID as the
+// after_id of the next request.
+type EventListResponse struct {
+	Events      []Event `json:"events"`
+	NextAfterID int64   `json:"next_after_id,omitempty"`
+}
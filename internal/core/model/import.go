@@ -0,0 +1,79 @@
+package model
+
+import "time"
+
+// Kinds of bulk import.
+const (
+	ImportAccounts  = "accounts"
+	ImportTransfers = "transfers"
+)
+
+const (
+	ImportRunning   = "running"
+	ImportCompleted = "completed"
+)
+
+// Outcomes of one imported row.
+const (
+	ImportRowCreated = "created"
+	ImportRowExists  = "exists"
+	ImportRowFailed  = "failed"
+)
+
+// ImportRequest starts the bulk import JobID, or resumes it by sending the same file again:
+// rows the job already applied are skipped. Format is the file's, see pkg/bulk.
+type ImportRequest struct {
+	JobID  string
+	Kind   string
+	Format string
+}
+
+// ImportJob is the progress of a bulk import. Rows counts the input rows applied so far;
+// Status is running until an upload reached the end of its file.
+type ImportJob struct {
+	ID        string    `json:"id"`
+	Kind      string    `json:"kind"`
+	Status    string    `json:"status"`
+	Rows      int64     `json:"rows"`
+	Created   int64     `json:"created"`
+	Existing  int64     `json:"existing"`
+	Failed    int64     `json:"failed"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ImportResult is the outcome of one input row, numbered from 1 after any header.
+type ImportResult struct {
+	Row           int64  `json:"row"`
+	Status        string `json:"status"`
+	AccountID     int64  `json:"account_id,omitempty"`
+	TransactionID int64  `json:"transaction_id,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+// ImportResultColumns name the columns of a results file, in the order of Values.
+var ImportResultColumns = []string{"row", "status", "account_id", "transaction_id", "error"}
+
+func (r ImportResult) Values() []any {
+	return []any{r.Row, r.Status, r.AccountID, r.TransactionID, r.Error}
+}
+
+type ImportResultList struct {
+	Results      []ImportResult `json:"results"`
+	NextAfterRow int64          `json:"next_after_row,omitempty"`
+}
+
+// ImportChunk is the run of input rows after row After up to row Last, applied in one
+// transaction. Accounts are created unless their ID exists; Results are the rows already
+// settled, failed ones and applied transfers.
+type ImportChunk struct {
+	After    int64
+	Last     int64
+	Accounts []ImportAccount
+	Results  []ImportResult
+}
+
+type ImportAccount struct {
+	Row     int64
+	Request AccountCreateRequest
+}
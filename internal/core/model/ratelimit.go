@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// RateLimitAccount names the per-account limit on transfers, next to the route group limits.
+const RateLimitAccount = "account"
+
+// RateLimitAuth names the per-IP limit on failed authentications.
+const RateLimitAuth = "auth"
+
+// RateLimit allows Limit requests per Period, with bursts of up to Limit.
+type RateLimit struct {
+	Limit  int
+	Period time.Duration
+}
+
+// RateLimitResult is the outcome of counting one request against a limit.
+type RateLimitResult struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is when the caller is back to the full Limit.
+	Reset time.Duration
+	// RetryAfter is how long a refused caller must wait; zero when allowed.
+	RetryAfter time.Duration
+}
@@ -2,7 +2,15 @@ package model
 
 import "time"
 
+const (
+	TransferPending   = "pending"
+	TransferCompleted = "completed"
+	TransferFailed    = "failed"
+)
+
 type TransferRequest struct {
+	// RequestID makes a transfer idempotent: a repeated ID returns the original transfer.
+	RequestID            string `json:"request_id,omitempty"`
 	SourceAccountID      int64  `json:"source_account_id"`
 	DestinationAccountID int64  `json:"destination_account_id"`
 	Amount               string `json:"amount"`
@@ -10,8 +18,20 @@ type TransferRequest struct {
 
 type TransferResponse struct {
 	TransactionID        int64     `json:"transaction_id"`
+	RequestID            string    `json:"request_id,omitempty"`
 	SourceAccountID      int64     `json:"source_account_id"`
 	DestinationAccountID int64     `json:"destination_account_id"`
 	Amount               string    `json:"amount"`
 	CreatedAt            time.Time `json:"created_at"`
+	// Receipt is a detached JWS over the fields above, verifiable against /.well-known/jwks.json.
+	Receipt string `json:"receipt,omitempty"`
+}
+
+type TransferStatusResponse struct {
+	TransferID           string            `json:"transfer_id"`
+	Status               string            `json:"status"`
+	SourceAccountID      int64             `json:"source_account_id,omitempty"`
+	DestinationAccountID int64             `json:"destination_account_id,omitempty"`
+	FailureReason        string            `json:"failure_reason,omitempty"`
+	Transfer             *TransferResponse `json:"transfer,omitempty"`
 }
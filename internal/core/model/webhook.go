@@ -0,0 +1,82 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+type WebhookCreateRequest struct {
+	URL string `json:"url"`
+	// EventTypes limits the subscription to these events; empty means all.
+	EventTypes []string `json:"event_types,omitempty"`
+	// AccountID limits the subscription to events touching this account; nil means all.
+	AccountID *int64 `json:"account_id,omitempty"`
+}
+
+type WebhookResponse struct {
+	ID         int64      `json:"id"`
+	URL        string     `json:"url"`
+	EventTypes []string   `json:"event_types"`
+	AccountID  *int64     `json:"account_id,omitempty"`
+	Enabled    bool       `json:"enabled"`
+	Failures   int        `json:"consecutive_failures"`
+	DisabledAt *time.Time `json:"disabled_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+
+	// Secret is only returned when the subscription is created.
+	Secret string `json:"secret,omitempty"`
+}
+
+// Webhook is a subscription as the dispatcher sees it, secret included.
+type Webhook struct {
+	ID         int64
+	URL        string
+	Secret     string
+	EventTypes []string
+	AccountID  *int64
+	Enabled    bool
+}
+
+type WebhookReplayRequest struct {
+	Since time.Time `json:"since"`
+}
+
+type WebhookReplayResponse struct {
+	Queued int `json:"queued"`
+}
+
+type WebhookDelivery struct {
+	ID             int64      `json:"id"`
+	SubscriptionID int64      `json:"subscription_id"`
+	EventID        int64      `json:"event_id"`
+	EventType      string     `json:"event_type"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at"`
+	ResponseCode   int        `json:"response_code,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// WebhookAttempt is a claimed delivery together with what is needed to send it.
+type WebhookAttempt struct {
+	Delivery WebhookDelivery
+	Webhook  Webhook
+	Event    Event
+}
+
+// WebhookPayload is the JSON body POSTed to subscribers.
+type WebhookPayload struct {
+	ID        int64           `json:"id"`
+	Type      string          `json:"type"`
+	AccountID int64           `json:"account_id"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
@@ -7,6 +7,8 @@ import (
 	"strings"
 	"txn-processor/internal/core/model"
 	"txn-processor/internal/port"
+	"txn-processor/pkg/auth"
+	"txn-processor/pkg/decimal"
 	"txn-processor/pkg/tracing"
 )
 
@@ -14,17 +16,21 @@ var (
 	ErrNotFound   = errors.New("not found")
 	ErrValidation = errors.New("validation failed")
 	ErrConflict   = errors.New("conflict")
+
+	ErrInsufficientFunds = errors.New("insufficient balance")
+	ErrAccountFrozen     = errors.New("account frozen")
 )
 
 type accountService struct {
 	dao    port.AccountDao
+	policy *policy
 	tracer tracing.Tracer
 }
 
 var _ port.AccountService = (*accountService)(nil)
 
-func NewAccountService(dao port.AccountDao, tracer tracing.Tracer) port.AccountService {
-	return &accountService{dao: dao, tracer: tracer}
+func NewAccountService(dao port.AccountDao, policy *policy, tracer tracing.Tracer) port.AccountService {
+	return &accountService{dao: dao, policy: policy, tracer: tracer}
 }
 
 func (s *accountService) CreateAccount(ctx context.Context, req model.AccountCreateRequest) (*model.AccountCreateResponse, error) {
@@ -37,6 +43,18 @@ func (s *accountService) CreateAccount(ctx context.Context, req model.AccountCre
 		return nil, err
 	}
 
+	if balance, err := decimal.ParseMoney(req.InitialBalance); err != nil || balance.IsNegative() {
+		span.RecordError(ErrValidation)
+		return nil, ErrValidation
+	}
+
+	owner, err := s.policy.owner(ctx, strings.TrimSpace(req.Owner))
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	req.Owner = owner
+
 	if err := s.dao.CreateAccount(ctx, req); err != nil {
 		span.RecordError(err)
 		if isUnique(err) {
@@ -58,6 +76,11 @@ func (s *accountService) GetAccount(ctx context.Context, id int64) (*model.Accou
 		return nil, err
 	}
 
+	if err := s.policy.requireAccount(ctx, auth.ScopeAccountsRead, id); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
 	acc, err := s.dao.GetAccountByID(ctx, id)
 	if err != nil {
 		span.RecordError(err)
@@ -67,9 +90,302 @@ func (s *accountService) GetAccount(ctx context.Context, id int64) (*model.Accou
 	return &model.AccountGetResponse{
 		AccountID: acc.AccountID,
 		Balance:   acc.Balance,
+		Version:   acc.Version,
+		Owner:     acc.Owner,
+		Frozen:    acc.Frozen,
+	}, nil
+}
+
+// MaxBatchLookup bounds how many accounts or transfers one batch lookup may ask for.
+const MaxBatchLookup = 100
+
+// GetAccounts returns the accounts among ids that exist and the caller may read, in no
+// particular order. Accounts the caller may not read are left out like missing ones.
+func (s *accountService) GetAccounts(ctx context.Context, ids []int64) ([]model.AccountGetResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "service.account.get_many")
+	defer span.End()
+
+	if err := validateBatch(ids); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	allowed, err := s.policy.accessible(ctx, auth.ScopeAccountsRead, ids)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	accounts, err := s.dao.GetAccountsByIDs(ctx, ids)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	if allowed == nil {
+		return accounts, nil
+	}
+
+	visible := accounts[:0]
+	for _, acc := range accounts {
+		if allowed[acc.AccountID] {
+			visible = append(visible, acc)
+		}
+	}
+	return visible, nil
+}
+
+func validateBatch(ids []int64) error {
+	if len(ids) == 0 || len(ids) > MaxBatchLookup {
+		return ErrValidation
+	}
+	for _, id := range ids {
+		if id <= 0 {
+			return ErrValidation
+		}
+	}
+	return nil
+}
+
+// MaxAccountShards bounds how far a single hot account can be split.
+const MaxAccountShards = 64
+
+func (s *accountService) SetAccountShards(ctx context.Context, id int64, req model.AccountShardRequest) (*model.AccountShardResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "service.account.shards")
+	defer span.End()
+
+	if id <= 0 || req.Shards < 0 || req.Shards > MaxAccountShards {
+		err := ErrValidation
+		span.RecordError(err)
+		return nil, err
+	}
+
+	if _, err := s.policy.require(ctx, auth.ScopeAdmin); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	if _, err := s.dao.GetAccountByID(ctx, id); err != nil {
+		span.RecordError(err)
+		return nil, ErrNotFound
+	}
+
+	if err := s.dao.SetAccountShards(ctx, id, req.Shards); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return &model.AccountShardResponse{AccountID: id, Shards: req.Shards}, nil
+}
+
+// GetAccountChanges serves DefaultChangesPage changes for a limit of 0 and refuses limits
+// above MaxChangesPage.
+const (
+	DefaultChangesPage = 100
+	MaxChangesPage     = 1000
+)
+
+// GetAccountChanges returns the account's changes after version since, oldest first.
+// Passing the response's Next as since fetches the following page.
+func (s *accountService) GetAccountChanges(ctx context.Context, id int64, since int64, limit int) (*model.AccountChangesResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "service.account.changes")
+	defer span.End()
+
+	if id <= 0 || since < 0 || limit < 0 || limit > MaxChangesPage {
+		err := ErrValidation
+		span.RecordError(err)
+		return nil, err
+	}
+	if limit == 0 {
+		limit = DefaultChangesPage
+	}
+
+	if err := s.policy.requireAccount(ctx, auth.ScopeAccountsRead, id); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	if _, err := s.dao.GetAccountByID(ctx, id); err != nil {
+		span.RecordError(err)
+		if isNotFound(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	changes, err := s.dao.ListAccountChanges(ctx, id, since, limit)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	next := since
+	if len(changes) > 0 {
+		next = changes[len(changes)-1].Version
+	}
+
+	return &model.AccountChangesResponse{
+		AccountID: id,
+		Changes:   changes,
+		Next:      next,
+		HasMore:   len(changes) == limit,
 	}, nil
 }
 
+// GrantAccount lets subject act on the account as a delegate. Only the owner or an admin may
+// grant access.
+func (s *accountService) GrantAccount(ctx context.Context, id int64, subject string) error {
+	ctx, span := s.tracer.Start(ctx, "service.account.grant")
+	defer span.End()
+
+	if err := validateGrant(id, subject); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	if err := s.policy.requireOwner(ctx, auth.ScopeAccountsWrite, id); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	if err := s.dao.GrantAccount(ctx, id, subject); err != nil {
+		span.RecordError(err)
+		if isNotFound(err) {
+			return ErrNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *accountService) RevokeAccountGrant(ctx context.Context, id int64, subject string) error {
+	ctx, span := s.tracer.Start(ctx, "service.account.revoke")
+	defer span.End()
+
+	if err := validateGrant(id, subject); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	if err := s.policy.requireOwner(ctx, auth.ScopeAccountsWrite, id); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	if err := s.dao.RevokeAccountGrant(ctx, id, subject); err != nil {
+		span.RecordError(err)
+		if isNotFound(err) {
+			return ErrNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+const (
+	defaultAccountPage = 100
+	maxAccountPage     = 1000
+
+	// maxReconcileMismatches bounds the report of a badly broken ledger.
+	maxReconcileMismatches = 1000
+)
+
+// ListAccounts pages through all accounts in ID order. Only admins may list them.
+func (s *accountService) ListAccounts(ctx context.Context, afterID int64, limit int) (*model.AccountListResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "service.account.list")
+	defer span.End()
+
+	if afterID < 0 || limit < 0 || limit > maxAccountPage {
+		err := ErrValidation
+		span.RecordError(err)
+		return nil, err
+	}
+	if limit == 0 {
+		limit = defaultAccountPage
+	}
+
+	if _, err := s.policy.require(ctx, auth.ScopeAdmin); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	accounts, err := s.dao.ListAccounts(ctx, afterID, limit)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	res := &model.AccountListResponse{Accounts: accounts}
+	if len(accounts) == limit {
+		res.NextAfterID = accounts[len(accounts)-1].AccountID
+	}
+	return res, nil
+}
+
+// SetAccountFrozen freezes or unfreezes an account. Transfers from or to a frozen account
+// fail with ErrAccountFrozen; reads and balance streams keep working.
+func (s *accountService) SetAccountFrozen(ctx context.Context, id int64, frozen bool) (*model.AccountFreezeResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "service.account.freeze")
+	defer span.End()
+
+	if id <= 0 {
+		err := ErrValidation
+		span.RecordError(err)
+		return nil, err
+	}
+
+	if _, err := s.policy.require(ctx, auth.ScopeAdmin); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	if err := s.dao.SetAccountFrozen(ctx, id, frozen); err != nil {
+		span.RecordError(err)
+		if isNotFound(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &model.AccountFreezeResponse{AccountID: id, Frozen: frozen}, nil
+}
+
+// Reconcile checks every balance against the change log. Only admins may run it.
+func (s *accountService) Reconcile(ctx context.Context) (*model.ReconcileReport, error) {
+	ctx, span := s.tracer.Start(ctx, "service.account.reconcile")
+	defer span.End()
+
+	if _, err := s.policy.require(ctx, auth.ScopeAdmin); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	report, err := s.dao.Reconcile(ctx, maxReconcileMismatches)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	return report, nil
+}
+
+func validateGrant(id int64, subject string) error {
+	if id <= 0 || strings.TrimSpace(subject) == "" || len(subject) > maxAPIKeyName {
+		return ErrValidation
+	}
+	return nil
+}
+
+func isNotFound(err error) bool {
+	return err != nil && strings.Contains(strings.ToLower(err.Error()), "record not found")
+}
+
+func isInsufficient(err error) bool {
+	return err != nil && strings.Contains(strings.ToLower(err.Error()), "insufficient balance")
+}
+
+func isFrozen(err error) bool {
+	return err != nil && strings.Contains(strings.ToLower(err.Error()), "account frozen")
+}
+
 func isUnique(err error) bool {
 	if err == nil {
 		return false
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"time"
+	"txn-processor/internal/core/model"
+	"txn-processor/internal/port"
+	"txn-processor/pkg/auth"
+	"txn-processor/pkg/tracing"
+)
+
+const (
+	defaultAuditPage = 50
+	maxAuditPage     = 500
+
+	defaultEventPage = 100
+	maxEventPage     = 1000
+)
+
+type auditService struct {
+	dao    port.AuditDao
+	events port.OutboxDao
+	policy *policy
+	tracer tracing.Tracer
+}
+
+var _ port.AuditService = (*auditService)(nil)
+
+func NewAuditService(dao port.AuditDao, events port.OutboxDao, policy *policy, tracer tracing.Tracer) port.AuditService {
+	return &auditService{dao: dao, events: events, policy: policy, tracer: tracer}
+}
+
+// ListAudit returns matching audit records, newest first.
+func (s *auditService) ListAudit(ctx context.Context, filter model.AuditFilter) (*model.AuditListResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "service.audit.list")
+	defer span.End()
+
+	if filter.BeforeID < 0 || filter.Limit < 0 || filter.Limit > maxAuditPage {
+		err := ErrValidation
+		span.RecordError(err)
+		return nil, err
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultAuditPage
+	}
+
+	if _, err := s.policy.require(ctx, auth.ScopeAdmin); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	records, err := s.dao.ListAudit(ctx, filter)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	res := &model.AuditListResponse{Records: records}
+	if len(records) == filter.Limit {
+		res.NextBeforeID = records[len(records)-1].ID
+	}
+	return res, nil
+}
+
+// ListEvents pages through the domain events of the outbox in commit order, whether or not
+// they were published yet.
+func (s *auditService) ListEvents(ctx context.Context, afterID int64, limit int) (*model.EventListResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "service.audit.events")
+	defer span.End()
+
+	if afterID < 0 || limit < 0 || limit > maxEventPage {
+		err := ErrValidation
+		span.RecordError(err)
+		return nil, err
+	}
+	if limit == 0 {
+		limit = defaultEventPage
+	}
+
+	if _, err := s.policy.require(ctx, auth.ScopeAdmin); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	events, err := s.events.FetchSince(ctx, time.Time{}, afterID, limit)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	res := &model.EventListResponse{Events: events}
+	if len(events) == limit {
+		res.NextAfterID = events[len(events)-1].ID
+	}
+	return res, nil
+}
@@ -0,0 +1,335 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"net"
+	"slices"
+	"strings"
+	"time"
+	"txn-processor/config"
+	"txn-processor/internal/core/model"
+	"txn-processor/internal/port"
+	"txn-processor/pkg/audit"
+	"txn-processor/pkg/auth"
+	"txn-processor/pkg/reqsign"
+	"txn-processor/pkg/tracing"
+)
+
+var ErrUnauthenticated = errors.New("unauthenticated")
+
+// APIKeyPrefix starts every API key, so a key sent as a bearer token is told apart from a JWT.
+const APIKeyPrefix = "txn_"
+
+const maxAPIKeyName = 128
+
+// minSigningSecret rejects shared secrets too short to resist guessing.
+const minSigningSecret = 16
+
+var (
+	errSignatureExpired  = errors.New("signature timestamp outside the allowed skew")
+	errSignatureReplayed = errors.New("signature nonce already used")
+)
+
+type authService struct {
+	dao      port.APIKeyDao
+	verifier *auth.TokenVerifier
+	policy   *policy
+	limits   *rateLimitService
+	enabled  bool
+	tracer   tracing.Tracer
+
+	// signingKeys maps partner IDs to their request signing secrets.
+	signingKeys map[string]string
+	maxSkew     time.Duration
+}
+
+var _ port.AuthService = (*authService)(nil)
+
+// NewAuthService loads the JWKS for bearer tokens. An unreadable JWKS is logged and leaves
+// only API keys accepted, rather than opening the API; a signing secret that is too short is
+// likewise logged and left out.
+func NewAuthService(dao port.APIKeyDao, policy *policy, limits *rateLimitService, tracer tracing.Tracer, conf config.Auth) port.AuthService {
+	s := &authService{
+		dao:         dao,
+		policy:      policy,
+		limits:      limits,
+		enabled:     conf.IsEnabled,
+		tracer:      tracer,
+		signingKeys: make(map[string]string, len(conf.SigningKeys)),
+		maxSkew:     time.Duration(max(conf.SigningMaxSkewSec, 1)) * time.Second,
+	}
+
+	for partner, secret := range conf.SigningKeys {
+		if len(secret) < minSigningSecret {
+			slog.Error("signing secret too short, partner's signed requests rejected", "partner", partner, "min", minSigningSecret)
+			continue
+		}
+		s.signingKeys[partner] = secret
+	}
+
+	if conf.IsEnabled && conf.JWKSFile != "" {
+		leeway := time.Duration(max(conf.LeewaySec, 0)) * time.Second
+		verifier, err := auth.LoadTokenVerifier(conf.JWKSFile, conf.Issuer, conf.Audience, leeway)
+		if err != nil {
+			slog.Error("failed to load JWKS, bearer tokens rejected", "file", conf.JWKSFile, "error", err)
+		} else {
+			s.verifier = verifier
+		}
+	}
+	if !conf.IsEnabled {
+		slog.Warn("API authentication disabled")
+	}
+
+	return s
+}
+
+// Authenticate returns the caller the credentials prove, or nil when authentication is
+// disabled and the request goes on anonymously.
+func (s *authService) Authenticate(ctx context.Context, creds model.Credentials) (*auth.Principal, error) {
+	if !s.enabled {
+		return nil, nil
+	}
+
+	ctx, span := s.tracer.Start(ctx, "service.auth.authenticate")
+	defer span.End()
+
+	p, err := s.limitFailures(ctx, func() (*auth.Principal, error) {
+		return s.authenticate(ctx, creds)
+	})
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	span.SetAttributes("auth.subject", p.Subject, "auth.method", p.Method)
+	return p, nil
+}
+
+// limitFailures counts failed authentications against the client IP's limit. Once the IP is
+// over it, its requests are refused before their credentials are checked, so guessing them
+// gets no further. Callers without an IP, such as queue commands, are not limited.
+func (s *authService) limitFailures(ctx context.Context, authenticate func() (*auth.Principal, error)) (*auth.Principal, error) {
+	ip := audit.FromContext(ctx).SourceIP
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	if ip == "" {
+		return authenticate()
+	}
+
+	if err := s.limits.check(ctx, model.RateLimitAuth, ip); err != nil {
+		return nil, err
+	}
+	p, err := authenticate()
+	if errors.Is(err, ErrUnauthenticated) {
+		_ = s.limits.charge(ctx, model.RateLimitAuth, ip)
+	}
+	return p, err
+}
+
+func (s *authService) authenticate(ctx context.Context, creds model.Credentials) (*auth.Principal, error) {
+	var (
+		p   *auth.Principal
+		err error
+	)
+	switch {
+	case creds.APIKey != "":
+		p, err = s.authenticateKey(ctx, creds.APIKey)
+	case strings.HasPrefix(creds.BearerToken, APIKeyPrefix):
+		p, err = s.authenticateKey(ctx, creds.BearerToken)
+	case creds.BearerToken != "" && s.verifier != nil:
+		p, err = s.authenticateToken(creds.BearerToken)
+	default:
+		err = ErrUnauthenticated
+	}
+	return p, err
+}
+
+// AuthenticateSigned checks the signature, its freshness and that its nonce is new. The MAC is
+// checked before the nonce is claimed, so only a partner can use up its nonces.
+func (s *authService) AuthenticateSigned(ctx context.Context, req model.SignedRequest) (*auth.Principal, error) {
+	if !s.enabled {
+		return nil, nil
+	}
+
+	ctx, span := s.tracer.Start(ctx, "service.auth.signed")
+	defer span.End()
+
+	sig, err := reqsign.Parse(req.Signature)
+	if err != nil {
+		span.RecordError(err)
+		return nil, errors.Join(ErrUnauthenticated, err)
+	}
+	secret, ok := s.signingKeys[sig.KeyID]
+	if !ok {
+		span.RecordError(ErrUnauthenticated)
+		return nil, ErrUnauthenticated
+	}
+	if d := time.Since(sig.Timestamp); d > s.maxSkew || d < -s.maxSkew {
+		span.RecordError(errSignatureExpired)
+		return nil, errors.Join(ErrUnauthenticated, errSignatureExpired)
+	}
+	if err := sig.Verify(secret, req.Method, req.URI, req.Body); err != nil {
+		span.RecordError(err)
+		return nil, errors.Join(ErrUnauthenticated, err)
+	}
+
+	// A nonce only needs remembering while its timestamp is accepted, from either side.
+	fresh, err := s.dao.ClaimNonce(ctx, sig.KeyID, sig.Nonce, 2*s.maxSkew)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	if !fresh {
+		span.RecordError(errSignatureReplayed)
+		return nil, errors.Join(ErrUnauthenticated, errSignatureReplayed)
+	}
+
+	span.SetAttributes("auth.subject", sig.KeyID, "auth.method", auth.MethodSignature)
+	return &auth.Principal{Subject: sig.KeyID, Method: auth.MethodSignature, KeyID: sig.KeyID, Scopes: auth.DefaultScopes}, nil
+}
+
+func (s *authService) authenticateKey(ctx context.Context, key string) (*auth.Principal, error) {
+	id, ok := apiKeyID(key)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	stored, err := s.dao.GetAPIKey(ctx, id)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrUnauthenticated
+		}
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashAPIKey(key)), []byte(stored.Hash)) != 1 || stored.RevokedAt != nil {
+		return nil, ErrUnauthenticated
+	}
+
+	return &auth.Principal{Subject: stored.Subject, Method: auth.MethodAPIKey, KeyID: stored.ID, Scopes: stored.Scopes}, nil
+}
+
+func (s *authService) authenticateToken(token string) (*auth.Principal, error) {
+	claims, kid, err := s.verifier.Verify(token, time.Now())
+	if err != nil {
+		return nil, errors.Join(ErrUnauthenticated, err)
+	}
+	return &auth.Principal{Subject: claims.Subject, Method: auth.MethodJWT, KeyID: kid, Scopes: strings.Fields(claims.Scope)}, nil
+}
+
+// IssueAPIKey creates a key for req.Subject, or for the key's name when no subject is given.
+// The plain key is returned once and only its hash is stored. Without scopes the key gets
+// auth.DefaultScopes.
+func (s *authService) IssueAPIKey(ctx context.Context, req model.APIKeyIssueRequest) (*model.APIKeyIssueResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "service.auth.issue")
+	defer span.End()
+
+	req.Name = strings.TrimSpace(req.Name)
+	req.Subject = strings.TrimSpace(req.Subject)
+	if req.Subject == "" {
+		req.Subject = req.Name
+	}
+	if req.Name == "" || len(req.Name) > maxAPIKeyName || len(req.Subject) > maxAPIKeyName {
+		err := ErrValidation
+		span.RecordError(err)
+		return nil, err
+	}
+	if len(req.Scopes) == 0 {
+		req.Scopes = auth.DefaultScopes
+	}
+	for _, scope := range req.Scopes {
+		if !slices.Contains(auth.Scopes, scope) {
+			err := ErrValidation
+			span.RecordError(err)
+			return nil, err
+		}
+	}
+
+	if _, err := s.policy.require(ctx, auth.ScopeAdmin); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	id, key, err := newAPIKey()
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	stored, err := s.dao.CreateAPIKey(ctx, model.APIKey{
+		ID:      id,
+		Hash:    hashAPIKey(key),
+		Name:    req.Name,
+		Subject: req.Subject,
+		Scopes:  slices.Compact(slices.Sorted(slices.Values(req.Scopes))),
+	})
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return &model.APIKeyIssueResponse{APIKey: *stored, Key: key}, nil
+}
+
+func (s *authService) RevokeAPIKey(ctx context.Context, id string) error {
+	ctx, span := s.tracer.Start(ctx, "service.auth.revoke")
+	defer span.End()
+
+	if id == "" || len(id) > 32 {
+		err := ErrValidation
+		span.RecordError(err)
+		return err
+	}
+
+	if _, err := s.policy.require(ctx, auth.ScopeAdmin); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	if err := s.dao.RevokeAPIKey(ctx, id); err != nil {
+		span.RecordError(err)
+		if isNotFound(err) {
+			return ErrNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// newAPIKey returns a key "txn_<id>_<secret>": a 12 hex digit public ID to look the key up
+// by and a 256-bit secret.
+func newAPIKey() (string, string, error) {
+	b := make([]byte, 6+32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	id := hex.EncodeToString(b[:6])
+	return id, APIKeyPrefix + id + "_" + base64.RawURLEncoding.EncodeToString(b[6:]), nil
+}
+
+// apiKeyID extracts the public ID; the secret may itself contain underscores.
+func apiKeyID(key string) (string, bool) {
+	rest, ok := strings.CutPrefix(key, APIKeyPrefix)
+	if !ok {
+		return "", false
+	}
+	id, secret, ok := strings.Cut(rest, "_")
+	if !ok || id == "" || len(id) > 32 || secret == "" {
+		return "", false
+	}
+	return id, true
+}
+
+// hashAPIKey is a plain SHA-256: keys carry 256 random bits, so a slow hash buys nothing.
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
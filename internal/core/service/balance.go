@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"time"
+	"txn-processor/config"
+	"txn-processor/internal/core/model"
+	"txn-processor/internal/port"
+	"txn-processor/pkg/auth"
+	"txn-processor/pkg/tracing"
+)
+
+// BalanceService pushes committed balance changes to stream clients. As an EventPublisher it
+// receives outbox batches from the relay, reads each touched account once per batch and
+// broadcasts the result through the broker.
+type BalanceService struct {
+	accounts port.AccountDao
+	broker   port.BalanceBroker
+	policy   *policy
+	tracer   tracing.Tracer
+	buffer   int
+}
+
+var (
+	_ port.BalanceService = (*BalanceService)(nil)
+	_ port.EventPublisher = (*BalanceService)(nil)
+)
+
+func NewBalanceService(accounts port.AccountDao, broker port.BalanceBroker, policy *policy, tracer tracing.Tracer, conf config.Realtime) *BalanceService {
+	return &BalanceService{
+		accounts: accounts,
+		broker:   broker,
+		policy:   policy,
+		tracer:   tracer,
+		buffer:   max(conf.BufferSize, 1),
+	}
+}
+
+func (s *BalanceService) Publish(ctx context.Context, events []model.Event) error {
+	ctx, span := s.tracer.Start(ctx, "service.balance.publish")
+	defer span.End()
+
+	// Coalesce the batch: clients want the latest balance, not every intermediate one.
+	var order []int64
+	latest := make(map[int64]int64)
+	for _, e := range events {
+		if e.Type != model.EventAccountCreated && e.Type != model.EventTransferCompleted {
+			continue
+		}
+		if _, ok := latest[e.AccountID]; !ok {
+			order = append(order, e.AccountID)
+		}
+		latest[e.AccountID] = e.ID
+	}
+
+	for _, id := range order {
+		acc, err := s.accounts.ReadAccountBalance(ctx, id)
+		if isNotFound(err) {
+			continue
+		}
+		if err != nil {
+			span.RecordError(err)
+			return err
+		}
+
+		if _, err := s.broker.Publish(ctx, model.BalanceUpdate{
+			AccountID: id,
+			Balance:   acc.Balance,
+			EventID:   latest[id],
+			UpdatedAt: time.Now(),
+		}); err != nil {
+			span.RecordError(err)
+			return err
+		}
+	}
+
+	span.SetAttributes("balance.updates", len(order))
+	return nil
+}
+
+// StreamBalance sends the account's updates until ctx is done. With lastSeq > 0 the stream
+// resumes after that update; without it, or when the history no longer reaches back that far,
+// it starts with a snapshot of the current balance.
+func (s *BalanceService) StreamBalance(ctx context.Context, id int64, lastSeq int64) (<-chan model.BalanceUpdate, error) {
+	ctx, span := s.tracer.Start(ctx, "service.balance.stream")
+	defer span.End()
+
+	if id <= 0 || lastSeq < 0 {
+		span.RecordError(ErrValidation)
+		return nil, ErrValidation
+	}
+
+	if err := s.policy.requireAccount(ctx, auth.ScopeAccountsRead, id); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	if _, err := s.accounts.GetAccountByID(ctx, id); err != nil {
+		span.RecordError(err)
+		if isNotFound(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	// Subscribe before reading history so nothing falls between the two.
+	live, err := s.broker.Subscribe(ctx, id)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	backlog, err := s.backlog(ctx, id, lastSeq)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	out := make(chan model.BalanceUpdate, s.buffer)
+	go s.forward(ctx, id, backlog, live, out)
+
+	return out, nil
+}
+
+func (s *BalanceService) backlog(ctx context.Context, id int64, lastSeq int64) ([]model.BalanceUpdate, error) {
+	if lastSeq > 0 {
+		hist, err := s.broker.History(ctx, id, lastSeq)
+		if err != nil {
+			return nil, err
+		}
+		if len(hist) == 0 || hist[0].Seq == lastSeq+1 {
+			return hist, nil
+		}
+	}
+
+	seq, err := s.broker.LastSeq(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	acc, err := s.accounts.ReadAccountBalance(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return []model.BalanceUpdate{{
+		AccountID: id,
+		Seq:       seq,
+		Balance:   acc.Balance,
+		UpdatedAt: time.Now(),
+	}}, nil
+}
+
+// forward emits the backlog, then live updates in sequence order. Gaps left by a pub/sub
+// reconnect are filled from history; duplicates are skipped.
+func (s *BalanceService) forward(ctx context.Context, id int64, backlog []model.BalanceUpdate, live <-chan model.BalanceUpdate, out chan<- model.BalanceUpdate) {
+	defer close(out)
+
+	last := int64(-1)
+	send := func(u model.BalanceUpdate) bool {
+		if u.Seq <= last {
+			return true
+		}
+		select {
+		case out <- u:
+			last = u.Seq
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	for _, u := range backlog {
+		if !send(u) {
+			return
+		}
+	}
+
+	for u := range live {
+		if last >= 0 && u.Seq > last+1 {
+			missed, err := s.broker.History(ctx, id, last)
+			if err != nil {
+				return
+			}
+			for _, m := range missed {
+				if !send(m) {
+					return
+				}
+			}
+		}
+		if !send(u) {
+			return
+		}
+	}
+}
+
+// Shutdown ends every open stream on this replica.
+func (s *BalanceService) Shutdown(ctx context.Context) error {
+	return s.broker.Close()
+}
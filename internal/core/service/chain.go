@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+	"txn-processor/config"
+	"txn-processor/internal/core/model"
+	"txn-processor/internal/port"
+	"txn-processor/pkg/hashchain"
+	"txn-processor/pkg/tracing"
+)
+
+// Chainer links committed transfers into the hash chain and signs checkpoints of its head.
+// Replicas may run it concurrently: the DAO extends the chain under the head's row lock, and a
+// checkpoint for a head another replica already signed is dropped.
+type Chainer struct {
+	dao       port.ChainDao
+	signer    *hashchain.Signer
+	tracer    tracing.Tracer
+	interval  time.Duration
+	batchSize int
+	every     int64
+	maxAge    time.Duration
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+	once sync.Once
+}
+
+// NewChainer returns a chainer that signs with signer, or writes no checkpoints if it is nil.
+func NewChainer(dao port.ChainDao, signer *hashchain.Signer, tracer tracing.Tracer, conf config.Chain) *Chainer {
+	return &Chainer{
+		dao:       dao,
+		signer:    signer,
+		tracer:    tracer,
+		interval:  time.Duration(max(conf.IntervalMs, 10)) * time.Millisecond,
+		batchSize: max(conf.BatchSize, 1),
+		every:     max(conf.CheckpointEvery, 1),
+		maxAge:    time.Duration(max(conf.CheckpointIntervalSec, 1)) * time.Second,
+		quit:      make(chan struct{}),
+	}
+}
+
+func (c *Chainer) Start(ctx context.Context) {
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-c.quit:
+				return
+			case <-ticker.C:
+				if err := c.Extend(ctx); err != nil {
+					slog.ErrorContext(ctx, "chainer failed", "error", err)
+				}
+			}
+		}
+	}()
+}
+
+// Extend links every transfer committed so far and then writes a checkpoint if one is due.
+func (c *Chainer) Extend(ctx context.Context) error {
+	ctx, span := c.tracer.Start(ctx, "service.chain.extend")
+	defer span.End()
+
+	var head model.ChainHead
+	for {
+		h, linked, err := c.dao.LinkTransfers(ctx, c.batchSize)
+		if err != nil {
+			span.RecordError(err)
+			return err
+		}
+		head = h
+		if linked < c.batchSize {
+			break
+		}
+	}
+
+	if c.signer == nil || head.Seq == 0 {
+		return nil
+	}
+
+	last, err := c.dao.LatestCheckpoint(ctx)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	if last != nil {
+		if last.Seq >= head.Seq {
+			return nil
+		}
+		if head.Seq-last.Seq < c.every && time.Since(last.CreatedAt) < c.maxAge {
+			return nil
+		}
+	}
+
+	// The signature covers milliseconds only, so the stored time must not carry more.
+	checkpoint := hashchain.Checkpoint{
+		Seq:       head.Seq,
+		Hash:      head.Hash,
+		KeyID:     c.signer.KeyID(),
+		CreatedAt: time.Now().UTC().Truncate(time.Millisecond),
+	}
+	if err := c.dao.CreateCheckpoint(ctx, model.ChainCheckpoint{
+		Seq:       checkpoint.Seq,
+		Hash:      checkpoint.Hash,
+		KeyID:     checkpoint.KeyID,
+		Signature: c.signer.Sign(checkpoint),
+		CreatedAt: checkpoint.CreatedAt,
+	}); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	span.SetAttributes("chain.checkpoint", head.Seq)
+	return nil
+}
+
+func (c *Chainer) Shutdown(ctx context.Context) error {
+	c.once.Do(func() { close(c.quit) })
+
+	done := make(chan struct{})
+	go func() {
+		c.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
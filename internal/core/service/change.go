@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+	"txn-processor/config"
+	"txn-processor/internal/port"
+	"txn-processor/pkg/tracing"
+)
+
+// ChangeSequencer numbers the change feed entries of hot accounts. Their transfers skip the
+// account row, so the entries are written unnumbered and get their versions here, in commit
+// order. Replicas may run it concurrently: each account is sequenced under its row lock.
+type ChangeSequencer struct {
+	dao       port.AccountDao
+	tracer    tracing.Tracer
+	interval  time.Duration
+	batchSize int
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+	once sync.Once
+}
+
+func NewChangeSequencer(dao port.AccountDao, tracer tracing.Tracer, conf config.Changes) *ChangeSequencer {
+	return &ChangeSequencer{
+		dao:       dao,
+		tracer:    tracer,
+		interval:  time.Duration(max(conf.SequenceIntervalMs, 10)) * time.Millisecond,
+		batchSize: max(conf.SequenceBatch, 1),
+		quit:      make(chan struct{}),
+	}
+}
+
+func (s *ChangeSequencer) Start(ctx context.Context) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.quit:
+				return
+			case <-ticker.C:
+				if _, err := s.dao.SequenceChanges(ctx, s.batchSize); err != nil {
+					slog.ErrorContext(ctx, "change sequencer failed", "error", err)
+				}
+			}
+		}
+	}()
+}
+
+func (s *ChangeSequencer) Shutdown(ctx context.Context) error {
+	s.once.Do(func() { close(s.quit) })
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
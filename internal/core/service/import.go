@@ -0,0 +1,319 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"txn-processor/config"
+	"txn-processor/internal/core/model"
+	"txn-processor/internal/port"
+	"txn-processor/pkg/auth"
+	"txn-processor/pkg/bulk"
+	"txn-processor/pkg/decimal"
+	"txn-processor/pkg/tracing"
+)
+
+const (
+	defaultImportPage = 1000
+	maxImportPage     = 1000
+	maxImportReason   = 255
+
+	// importPrefix starts the request ID of imported transfers that bring none, so a resumed
+	// import replays rather than repeats them.
+	importPrefix = "import:"
+)
+
+// importJobID leaves room for importPrefix, the ID and a row number within maxRequestIDLen.
+var importJobID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,40}$`)
+
+// importService applies bulk imports in chunks. Accounts of a chunk are created in one
+// transaction together with the chunk's results and the job's progress. Transfers go through
+// the transfer service one by one, so every lock strategy, hot-account and freeze rule applies, and
+// their results are recorded per chunk the same way.
+type importService struct {
+	dao       port.ImportDao
+	transfers *transferService
+	policy    *policy
+	tracer    tracing.Tracer
+	chunkSize int64
+}
+
+var _ port.ImportService = (*importService)(nil)
+
+func NewImportService(dao port.ImportDao, transfers *transferService, policy *policy, tracer tracing.Tracer, conf config.Import) port.ImportService {
+	return &importService{
+		dao:       dao,
+		transfers: transfers,
+		policy:    policy,
+		tracer:    tracer,
+		chunkSize: int64(max(conf.ChunkSize, 1)),
+	}
+}
+
+// Import applies the rows of body after the job's progress. Rows that fail validation or are
+// refused are recorded as failed and do not stop the import. A file that cannot be parsed any
+// further, a broken upload or a failing database does, after the rows before it were applied.
+func (s *importService) Import(ctx context.Context, req model.ImportRequest, body io.Reader) (*model.ImportJob, error) {
+	ctx, span := s.tracer.Start(ctx, "service.import.run")
+	defer span.End()
+
+	if _, err := s.policy.require(ctx, auth.ScopeAdmin); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	if !importJobID.MatchString(req.JobID) || (req.Kind != model.ImportAccounts && req.Kind != model.ImportTransfers) {
+		err := ErrValidation
+		span.RecordError(err)
+		return nil, err
+	}
+	rows, err := bulk.NewReader(body, req.Format)
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("%w: %v", ErrValidation, err)
+	}
+
+	job, err := s.dao.StartImport(ctx, req.JobID, req.Kind)
+	if err != nil {
+		span.RecordError(err)
+		if isUnique(err) {
+			return nil, ErrConflict
+		}
+		return nil, err
+	}
+	if job.Kind != req.Kind {
+		err := ErrConflict
+		span.RecordError(err)
+		return nil, err
+	}
+
+	chunk := model.ImportChunk{After: job.Rows, Last: job.Rows}
+	for {
+		row, err := rows.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err == nil && row.Num > job.Rows {
+			err = s.add(ctx, req, &chunk, row)
+		}
+		if err != nil {
+			// Keep the rows before the failure, so a resume starts right after them.
+			if chunk.Last > chunk.After {
+				if _, err := s.apply(ctx, req.JobID, chunk); err != nil {
+					span.RecordError(err)
+					return nil, err
+				}
+			}
+			span.RecordError(err)
+			if errors.Is(err, bulk.ErrMalformed) {
+				return nil, fmt.Errorf("%w: %v", ErrValidation, err)
+			}
+			return nil, err
+		}
+
+		if chunk.Last-chunk.After >= s.chunkSize {
+			if job, err = s.apply(ctx, req.JobID, chunk); err != nil {
+				span.RecordError(err)
+				return nil, err
+			}
+			chunk = model.ImportChunk{After: job.Rows, Last: job.Rows}
+		}
+	}
+
+	if chunk.Last > chunk.After {
+		if _, err := s.apply(ctx, req.JobID, chunk); err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+	}
+	job, err = s.dao.FinishImport(ctx, req.JobID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	return job, nil
+}
+
+// add validates a row into the chunk. Transfers are applied right away; an error means the
+// import cannot go on.
+func (s *importService) add(ctx context.Context, req model.ImportRequest, chunk *model.ImportChunk, row bulk.Row) error {
+	chunk.Last = row.Num
+	if row.Err != nil {
+		chunk.Results = append(chunk.Results, failedRow(row.Num, row.Err))
+		return nil
+	}
+
+	if req.Kind == model.ImportAccounts {
+		acc, err := s.accountRow(ctx, row)
+		if err != nil {
+			chunk.Results = append(chunk.Results, failedRow(row.Num, err))
+			return nil
+		}
+		chunk.Accounts = append(chunk.Accounts, model.ImportAccount{Row: row.Num, Request: acc})
+		return nil
+	}
+
+	tr, err := transferRow(req.JobID, row)
+	if err != nil {
+		chunk.Results = append(chunk.Results, failedRow(row.Num, err))
+		return nil
+	}
+	res, err := s.transfers.process(ctx, tr, false)
+	switch {
+	case err == nil && !sameTransfer(tr, res):
+		// The request ID replayed a transfer that is not this row's.
+		chunk.Results = append(chunk.Results, failedRow(row.Num, errors.New("request_id belongs to another transfer")))
+	case err == nil:
+		chunk.Results = append(chunk.Results, model.ImportResult{Row: row.Num, Status: model.ImportRowCreated, TransactionID: res.TransactionID})
+	case errors.Is(err, ErrValidation), errors.Is(err, ErrNotFound), errors.Is(err, ErrInsufficientFunds),
+		errors.Is(err, ErrAccountFrozen), errors.Is(err, ErrForbidden):
+		chunk.Results = append(chunk.Results, failedRow(row.Num, err))
+	default:
+		// The row was not applied; leave it out so a resume tries it again.
+		chunk.Last = row.Num - 1
+		return err
+	}
+	return nil
+}
+
+// apply commits the chunk even if the upload that carried it was cancelled.
+func (s *importService) apply(ctx context.Context, id string, chunk model.ImportChunk) (*model.ImportJob, error) {
+	job, err := s.dao.ApplyImportChunk(context.WithoutCancel(ctx), id, chunk)
+	if err != nil {
+		if isMoved(err) {
+			return nil, ErrConflict
+		}
+		return nil, err
+	}
+	return job, nil
+}
+
+func (s *importService) accountRow(ctx context.Context, row bulk.Row) (model.AccountCreateRequest, error) {
+	id, err := idField(row, "account_id")
+	if err != nil {
+		return model.AccountCreateRequest{}, err
+	}
+	balance := row.Fields["initial_balance"]
+	if m, err := decimal.ParseMoney(balance); err != nil || m.IsNegative() {
+		return model.AccountCreateRequest{}, fmt.Errorf("invalid initial_balance %q", balance)
+	}
+	owner, err := s.policy.owner(ctx, row.Fields["owner"])
+	if err != nil {
+		return model.AccountCreateRequest{}, err
+	}
+	return model.AccountCreateRequest{AccountID: id, InitialBalance: balance, Owner: owner}, nil
+}
+
+func transferRow(jobID string, row bulk.Row) (model.TransferRequest, error) {
+	source, err := idField(row, "source_account_id")
+	if err != nil {
+		return model.TransferRequest{}, err
+	}
+	dest, err := idField(row, "destination_account_id")
+	if err != nil {
+		return model.TransferRequest{}, err
+	}
+	amount := row.Fields["amount"]
+	if m, err := decimal.ParseMoney(amount); err != nil || !m.IsPositive() {
+		return model.TransferRequest{}, fmt.Errorf("invalid amount %q", amount)
+	}
+
+	req := model.TransferRequest{SourceAccountID: source, DestinationAccountID: dest, Amount: amount, RequestID: row.Fields["request_id"]}
+	if reservedRequestID(req.RequestID) {
+		return model.TransferRequest{}, fmt.Errorf("reserved request_id %q", req.RequestID)
+	}
+	if req.RequestID == "" {
+		req.RequestID = importPrefix + jobID + ":" + strconv.FormatInt(row.Num, 10)
+	}
+	return req, nil
+}
+
+// sameTransfer reports whether res, which may be a replay, moved what req asks for.
+func sameTransfer(req model.TransferRequest, res *model.TransferResponse) bool {
+	if res.SourceAccountID != req.SourceAccountID || res.DestinationAccountID != req.DestinationAccountID {
+		return false
+	}
+	want, err := decimal.ParseMoney(req.Amount)
+	if err != nil {
+		return false
+	}
+	got, err := decimal.ParseMoney(res.Amount)
+	return err == nil && got.Equal(want)
+}
+
+func idField(row bulk.Row, name string) (int64, error) {
+	v, err := strconv.ParseInt(row.Fields[name], 10, 64)
+	if err != nil || v <= 0 {
+		return 0, fmt.Errorf("invalid %s %q", name, row.Fields[name])
+	}
+	return v, nil
+}
+
+// failedRow keeps the reason within the results table's column, which quoted input may exceed.
+func failedRow(num int64, err error) model.ImportResult {
+	reason := err.Error()
+	if len(reason) > maxImportReason {
+		reason = strings.ToValidUTF8(reason[:maxImportReason], "")
+	}
+	return model.ImportResult{Row: num, Status: model.ImportRowFailed, Error: reason}
+}
+
+func (s *importService) GetImport(ctx context.Context, id string) (*model.ImportJob, error) {
+	ctx, span := s.tracer.Start(ctx, "service.import.get")
+	defer span.End()
+
+	if _, err := s.policy.require(ctx, auth.ScopeAdmin); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	job, err := s.dao.GetImport(ctx, id)
+	if err != nil {
+		span.RecordError(err)
+		if isNotFound(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return job, nil
+}
+
+// ListImportResults pages through a job's results in row order.
+func (s *importService) ListImportResults(ctx context.Context, id string, afterRow int64, limit int) (*model.ImportResultList, error) {
+	ctx, span := s.tracer.Start(ctx, "service.import.results")
+	defer span.End()
+
+	if limit == 0 {
+		limit = defaultImportPage
+	}
+	if afterRow < 0 || limit < 0 || limit > maxImportPage {
+		err := ErrValidation
+		span.RecordError(err)
+		return nil, err
+	}
+
+	if _, err := s.GetImport(ctx, id); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	results, err := s.dao.ListImportResults(ctx, id, afterRow, limit)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	res := &model.ImportResultList{Results: results}
+	if len(results) == limit {
+		res.NextAfterRow = results[len(results)-1].Row
+	}
+	return res, nil
+}
+
+func isMoved(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "import job moved")
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+	"txn-processor/config"
+	"txn-processor/internal/core/model"
+	"txn-processor/internal/port"
+	"txn-processor/pkg/tracing"
+
+	"github.com/google/uuid"
+)
+
+// OutboxRelay publishes outbox events in ID order and marks them sent.
+// Delivery is at-least-once: a crash between Publish and MarkSent republishes the batch.
+//
+// Order holds per account: an event is written while its account row is locked, so the events
+// of one account commit in ID order. A transfer writes one event for each of its accounts, and
+// each of them is ordered with the events of its own account. Concurrent transactions on different accounts, or on a
+// sharded account, which only takes a shared lock, may commit out of ID order.
+type OutboxRelay struct {
+	dao       port.OutboxDao
+	publisher port.EventPublisher
+	tracer    tracing.Tracer
+	owner     string
+	interval  time.Duration
+	batchSize int
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+	once sync.Once
+}
+
+func NewOutboxRelay(dao port.OutboxDao, publisher port.EventPublisher, tracer tracing.Tracer, conf config.Outbox) *OutboxRelay {
+	return &OutboxRelay{
+		dao:       dao,
+		publisher: publisher,
+		tracer:    tracer,
+		owner:     uuid.NewString(),
+		interval:  time.Duration(max(conf.PollIntervalMs, 10)) * time.Millisecond,
+		batchSize: max(conf.BatchSize, 1),
+		quit:      make(chan struct{}),
+	}
+}
+
+func (r *OutboxRelay) Start(ctx context.Context) {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-r.quit:
+				return
+			case <-ticker.C:
+				if err := r.Drain(ctx); err != nil {
+					slog.ErrorContext(ctx, "outbox relay failed", "error", err)
+				}
+			}
+		}
+	}()
+}
+
+func (r *OutboxRelay) Shutdown(ctx context.Context) error {
+	r.once.Do(func() { close(r.quit) })
+
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Drain publishes unsent events until the outbox is empty or a publish fails.
+func (r *OutboxRelay) Drain(ctx context.Context) error {
+	for {
+		// Renewing per batch keeps the lease alive through a long backlog.
+		leader, err := r.dao.AcquireRelayLease(ctx, r.owner, 5*r.interval+time.Second)
+		if err != nil || !leader {
+			return err
+		}
+
+		more, err := r.drainBatch(ctx)
+		if err != nil || !more {
+			return err
+		}
+
+		select {
+		case <-r.quit:
+			return nil
+		default:
+		}
+	}
+}
+
+// drainBatch publishes one batch and reports whether a full batch was sent.
+func (r *OutboxRelay) drainBatch(ctx context.Context) (bool, error) {
+	ctx, span := r.tracer.Start(ctx, "service.outbox.relay")
+	defer span.End()
+
+	events, err := r.dao.FetchUnsent(ctx, r.batchSize)
+	if err != nil {
+		span.RecordError(err)
+		return false, err
+	}
+	if len(events) == 0 {
+		return false, nil
+	}
+
+	span.SetAttributes("outbox.batch", len(events))
+
+	if err := r.publisher.Publish(ctx, events); err != nil {
+		span.RecordError(err)
+		return false, err
+	}
+
+	ids := make([]int64, len(events))
+	for i, e := range events {
+		ids[i] = e.ID
+	}
+
+	if err := r.dao.MarkSent(ctx, ids); err != nil {
+		span.RecordError(err)
+		return false, err
+	}
+
+	return len(events) == r.batchSize, nil
+}
+
+type fanOut []port.EventPublisher
+
+// FanOut publishes every batch to each publisher in turn. A failure fails the whole batch,
+// so publishers must tolerate the republish that follows.
+func FanOut(publishers ...port.EventPublisher) port.EventPublisher {
+	return fanOut(publishers)
+}
+
+func (f fanOut) Publish(ctx context.Context, events []model.Event) error {
+	for _, p := range f {
+		if err := p.Publish(ctx, events); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"txn-processor/config"
+	"txn-processor/internal/core/model"
+	"txn-processor/internal/port"
+	"txn-processor/pkg/auth"
+)
+
+var ErrForbidden = errors.New("forbidden")
+
+// policy decides what the caller in a context may do. Services check it themselves, so every
+// inbound adapter gets the same rules. With authentication disabled there is no caller and
+// everything is allowed; with it enabled a context without a principal is refused, so an
+// adapter that forgets to authenticate fails closed.
+type policy struct {
+	enabled  bool
+	accounts port.AccountDao
+}
+
+func newPolicy(accounts port.AccountDao, conf config.Auth) *policy {
+	return &policy{enabled: conf.IsEnabled, accounts: accounts}
+}
+
+// require checks that the caller holds scope and returns it, or nil without authentication.
+func (p *policy) require(ctx context.Context, scope string) (*auth.Principal, error) {
+	if !p.enabled {
+		return nil, nil
+	}
+	caller, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+	if !caller.HasScope(scope) {
+		return nil, ErrForbidden
+	}
+	return &caller, nil
+}
+
+// requireAccount checks scope and that the caller owns, or is a delegate of, at least one of
+// the accounts. Admins may act on any account. An unknown account is refused like a foreign
+// one, so callers cannot probe which IDs exist.
+func (p *policy) requireAccount(ctx context.Context, scope string, ids ...int64) error {
+	caller, err := p.require(ctx, scope)
+	if err != nil || caller == nil || caller.IsAdmin() {
+		return err
+	}
+
+	for _, id := range ids {
+		access, err := p.accounts.AccountAccess(ctx, id, caller.Subject)
+		if isNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if access != model.AccessNone {
+			return nil
+		}
+	}
+	return ErrForbidden
+}
+
+// accessible is requireAccount for a batch: it returns which of ids the caller may act on with
+// scope, checked in one query, or nil when every account is allowed.
+func (p *policy) accessible(ctx context.Context, scope string, ids []int64) (map[int64]bool, error) {
+	caller, err := p.require(ctx, scope)
+	if err != nil || caller == nil || caller.IsAdmin() {
+		return nil, err
+	}
+
+	allowed := make(map[int64]bool, len(ids))
+	if len(ids) == 0 {
+		return allowed, nil
+	}
+	found, err := p.accounts.AccessibleAccounts(ctx, caller.Subject, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range found {
+		allowed[id] = true
+	}
+	return allowed, nil
+}
+
+// requireOwner checks scope and that the caller owns the account; delegates cannot pass
+// their access on.
+func (p *policy) requireOwner(ctx context.Context, scope string, id int64) error {
+	caller, err := p.require(ctx, scope)
+	if err != nil || caller == nil || caller.IsAdmin() {
+		return err
+	}
+
+	access, err := p.accounts.AccountAccess(ctx, id, caller.Subject)
+	if isNotFound(err) || (err == nil && access != model.AccessOwner) {
+		return ErrForbidden
+	}
+	return err
+}
+
+// owner returns the subject a new account belongs to: the caller, or for admins whoever they
+// name.
+func (p *policy) owner(ctx context.Context, requested string) (string, error) {
+	caller, err := p.require(ctx, auth.ScopeAccountsWrite)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case caller == nil:
+		return requested, nil
+	case requested == "" || requested == caller.Subject:
+		return caller.Subject, nil
+	case caller.IsAdmin():
+		return requested, nil
+	default:
+		return "", ErrForbidden
+	}
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+	"txn-processor/config"
+	"txn-processor/internal/core/model"
+	"txn-processor/internal/port"
+	"txn-processor/pkg/tracing"
+)
+
+const defaultRateLimitRoute = "default"
+
+// ErrRateLimited refuses a request over its rate limit.
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+// RateLimitError is ErrRateLimited with the state of the limit, so adapters can tell the
+// caller when to retry.
+type RateLimitError struct {
+	Result model.RateLimitResult
+}
+
+func (e *RateLimitError) Error() string { return ErrRateLimited.Error() }
+func (e *RateLimitError) Unwrap() error { return ErrRateLimited }
+
+type rateLimitService struct {
+	limiter port.RateLimiter
+	rules   map[string]model.RateLimit
+	tracer  tracing.Tracer
+}
+
+var _ port.RateLimitService = (*rateLimitService)(nil)
+
+// newRateLimitService parses the configured limits. A malformed limit is logged and left out
+// rather than failing startup.
+func newRateLimitService(limiter port.RateLimiter, tracer tracing.Tracer, conf config.RateLimit) *rateLimitService {
+	s := &rateLimitService{limiter: limiter, rules: make(map[string]model.RateLimit), tracer: tracer}
+	if !conf.IsEnabled {
+		slog.Warn("rate limiting disabled")
+		return s
+	}
+
+	add := func(route, spec string) {
+		if spec == "" || spec == "none" {
+			return
+		}
+		rule, err := parseRateLimit(spec)
+		if err != nil {
+			slog.Error("ignoring rate limit", "route", route, "limit", spec, "error", err)
+			return
+		}
+		s.rules[route] = rule
+	}
+	add(defaultRateLimitRoute, conf.Default)
+	add(model.RateLimitAccount, conf.Account)
+	add(model.RateLimitAuth, conf.Auth)
+	for route, spec := range conf.Routes {
+		add(route, spec)
+	}
+
+	return s
+}
+
+// Allow fails open: when Redis is unreachable the request goes through and the error is only
+// logged, so an outage of the limiter does not stop transfers.
+func (s *rateLimitService) Allow(ctx context.Context, route, key string) (*model.RateLimitResult, error) {
+	return s.run(ctx, route, key, s.limiter.Allow)
+}
+
+// charge counts a request against the limit of route and returns a *RateLimitError once it is
+// refused.
+func (s *rateLimitService) charge(ctx context.Context, route, key string) error {
+	res, _ := s.run(ctx, route, key, s.limiter.Allow)
+	if res != nil && !res.Allowed {
+		return &RateLimitError{Result: *res}
+	}
+	return nil
+}
+
+// check is charge without counting the request.
+func (s *rateLimitService) check(ctx context.Context, route, key string) error {
+	res, _ := s.run(ctx, route, key, s.limiter.Peek)
+	if res != nil && !res.Allowed {
+		return &RateLimitError{Result: *res}
+	}
+	return nil
+}
+
+func (s *rateLimitService) run(ctx context.Context, route, key string, count func(context.Context, string, model.RateLimit) (model.RateLimitResult, error)) (*model.RateLimitResult, error) {
+	rule, ok := s.rules[route]
+	// The account and auth limits stand on their own; only route groups fall back to the default.
+	if !ok && route != model.RateLimitAccount && route != model.RateLimitAuth {
+		rule, ok = s.rules[defaultRateLimitRoute]
+	}
+	if !ok {
+		return nil, nil
+	}
+
+	ctx, span := s.tracer.Start(ctx, "service.ratelimit.allow")
+	defer span.End()
+
+	res, err := count(ctx, route+":"+key, rule)
+	if err != nil {
+		span.RecordError(err)
+		slog.WarnContext(ctx, "rate limiter unavailable, allowing request", "route", route, "error", err)
+		return nil, nil
+	}
+
+	span.SetAttributes("ratelimit.route", route, "ratelimit.allowed", res.Allowed)
+	return &res, nil
+}
+
+// parseRateLimit reads "<requests>/<period>", where period is a Go duration such as "1s" or
+// "1m".
+func parseRateLimit(spec string) (model.RateLimit, error) {
+	n, p, ok := strings.Cut(spec, "/")
+	if !ok {
+		return model.RateLimit{}, fmt.Errorf("want <requests>/<period>")
+	}
+	limit, err := strconv.Atoi(strings.TrimSpace(n))
+	if err != nil || limit <= 0 {
+		return model.RateLimit{}, fmt.Errorf("invalid request count %q", n)
+	}
+	period, err := time.ParseDuration(strings.TrimSpace(p))
+	if err != nil || period <= 0 {
+		return model.RateLimit{}, fmt.Errorf("invalid period %q", p)
+	}
+	return model.RateLimit{Limit: limit, Period: period}, nil
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"txn-processor/config"
+	"txn-processor/internal/core/model"
+	"txn-processor/internal/port"
+	"txn-processor/pkg/receipt"
+)
+
+// receiptService signs transfer receipts with the active key and publishes every key that
+// may have signed one still in circulation.
+type receiptService struct {
+	signer *receipt.Signer
+	keys   receipt.KeySet
+}
+
+var _ port.ReceiptService = (*receiptService)(nil)
+
+// newReceiptService loads the keyring. Unusable keys are logged and left out, and without an
+// active signer transfers are returned without a receipt.
+func newReceiptService(conf config.Receipt) *receiptService {
+	s := &receiptService{keys: receipt.KeySet{Keys: []receipt.Key{}}}
+	if !conf.IsEnabled {
+		return s
+	}
+
+	for kid, seed := range conf.SigningKeys {
+		signer, err := receipt.NewSigner(kid, seed)
+		if err != nil {
+			slog.Error("receipt signing key unusable", "kid", kid, "error", err)
+			continue
+		}
+		s.keys.Keys = append(s.keys.Keys, signer.Key())
+		if kid == conf.ActiveKeyID {
+			s.signer = signer
+		}
+	}
+	for kid, pub := range conf.PublicKeys {
+		if _, ok := conf.SigningKeys[kid]; ok {
+			continue
+		}
+		key, err := receipt.ParsePublicKey(kid, pub)
+		if err != nil {
+			slog.Error("receipt public key unusable", "kid", kid, "error", err)
+			continue
+		}
+		s.keys.Keys = append(s.keys.Keys, key)
+	}
+	slices.SortFunc(s.keys.Keys, func(a, b receipt.Key) int { return strings.Compare(a.Kid, b.Kid) })
+
+	if s.signer == nil {
+		slog.Warn("no active receipt signing key, transfers are returned without receipts", "active_kid", conf.ActiveKeyID)
+	}
+	return s
+}
+
+func (s *receiptService) ReceiptKeys(ctx context.Context) receipt.KeySet {
+	return s.keys
+}
+
+// sign sets the receipt of a completed transfer.
+func (s *receiptService) sign(tr *model.TransferResponse) {
+	if s.signer == nil || tr == nil {
+		return
+	}
+	tr.Receipt = s.signer.Sign(receipt.Transfer{
+		TransactionID:        tr.TransactionID,
+		RequestID:            tr.RequestID,
+		SourceAccountID:      tr.SourceAccountID,
+		DestinationAccountID: tr.DestinationAccountID,
+		Amount:               tr.Amount,
+		CreatedAt:            tr.CreatedAt,
+	})
+}
@@ -1,7 +1,12 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"log/slog"
+	"txn-processor/config"
 	"txn-processor/internal/port"
+	"txn-processor/pkg/hashchain"
 	"txn-processor/pkg/tracing"
 )
 
@@ -9,14 +14,104 @@ type Service struct {
 	port.HealthService
 	port.AccountService
 	port.TransferService
+	port.WebhookService
+	port.BalanceService
+	port.AuditService
+	port.ReceiptService
+	port.AuthService
+	port.RateLimitService
+	port.ImportService
+
+	relay     *OutboxRelay
+	sequencer *ChangeSequencer
+	chainer   *Chainer
 }
 
 var _ port.Inbound = new(Service)
 
-func New(dao port.Outbound, tracer tracing.Tracer) *Service {
-	return &Service{
-		HealthService:   NewHealthService(dao, tracer),
-		AccountService:  NewAccountService(dao, tracer),
-		TransferService: NewTransferService(dao, tracer),
+func New(dao port.Outbound, publisher port.EventPublisher, sender port.WebhookSender, broker port.BalanceBroker, limiter port.RateLimiter, tracer tracing.Tracer, conf *config.App) *Service {
+	policy := newPolicy(dao, conf.Auth)
+	webhooks := NewWebhookService(dao, dao, dao, sender, policy, tracer, conf.Webhook)
+	balances := NewBalanceService(dao, broker, policy, tracer, conf.Realtime)
+	receipts := newReceiptService(conf.Receipt)
+	limits := newRateLimitService(limiter, tracer, conf.RateLimit)
+	transfers := newTransferService(dao, receipts, policy, limits, tracer, conf.Async)
+
+	s := &Service{
+		HealthService:    NewHealthService(dao, tracer),
+		AccountService:   NewAccountService(dao, policy, tracer),
+		TransferService:  transfers,
+		WebhookService:   webhooks,
+		BalanceService:   balances,
+		AuditService:     NewAuditService(dao, dao, policy, tracer),
+		ReceiptService:   receipts,
+		AuthService:      NewAuthService(dao, policy, limits, tracer, conf.Auth),
+		RateLimitService: limits,
+		ImportService:    NewImportService(dao, transfers, policy, tracer, conf.Import),
+		sequencer:        NewChangeSequencer(dao, tracer, conf.Changes),
+	}
+
+	if conf.Outbox.IsEnabled {
+		if conf.Webhook.IsEnabled {
+			publisher = FanOut(publisher, webhooks)
+		}
+		if conf.Realtime.IsEnabled {
+			publisher = FanOut(publisher, balances)
+		}
+		s.relay = NewOutboxRelay(dao, publisher, tracer, conf.Outbox)
+	}
+
+	if conf.Chain.IsEnabled {
+		s.chainer = NewChainer(dao, chainSigner(conf.Chain), tracer, conf.Chain)
+	}
+
+	return s
+}
+
+// Start launches background work that needs a migrated database.
+func (s *Service) Start(ctx context.Context) {
+	for _, svc := range s.background() {
+		if starter, ok := svc.(interface{ Start(context.Context) }); ok {
+			starter.Start(ctx)
+		}
+	}
+}
+
+// Shutdown stops background workers owned by the services.
+func (s *Service) Shutdown(ctx context.Context) error {
+	var errs []error
+	for _, svc := range s.background() {
+		if stopper, ok := svc.(interface{ Shutdown(context.Context) error }); ok {
+			if err := stopper.Shutdown(ctx); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *Service) background() []any {
+	svcs := []any{s.HealthService, s.AccountService, s.TransferService, s.WebhookService, s.BalanceService, s.sequencer}
+	if s.relay != nil {
+		svcs = append(svcs, s.relay)
+	}
+	if s.chainer != nil {
+		svcs = append(svcs, s.chainer)
+	}
+	return svcs
+}
+
+// chainSigner returns the checkpoint signer, or nil if no usable key is configured. The chain
+// is still extended without one; it just has no signed checkpoints.
+func chainSigner(conf config.Chain) *hashchain.Signer {
+	if conf.SigningKey == "" {
+		slog.Warn("no chain signing key configured, checkpoints disabled")
+		return nil
+	}
+	signer, err := hashchain.NewSigner(conf.KeyID, conf.SigningKey)
+	if err != nil {
+		slog.Error("chain signing key unusable, checkpoints disabled", "error", err)
+		return nil
 	}
+	return signer
 }
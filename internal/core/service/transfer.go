@@ -2,52 +2,490 @@ package service
 
 import (
 	"context"
+	"errors"
+	"log/slog"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
+	"txn-processor/config"
 	"txn-processor/internal/core/model"
 	"txn-processor/internal/port"
+	"txn-processor/pkg/audit"
+	"txn-processor/pkg/auth"
+	"txn-processor/pkg/decimal"
 	"txn-processor/pkg/tracing"
+
+	"github.com/google/uuid"
+)
+
+var ErrBusy = errors.New("too many pending transfers")
+
+const maxRequestIDLen = 64
+
+// An async transfer that fails for a reason other than a refusal is tried asyncAttempts times,
+// waiting a little longer after each attempt.
+const (
+	asyncAttempts   = 3
+	asyncRetryDelay = 200 * time.Millisecond
 )
 
 type transferService struct {
-	dao    port.TransferDao
-	tracer tracing.Tracer
+	dao      port.TransferDao
+	receipts *receiptService
+	policy   *policy
+	limits   *rateLimitService
+	tracer   tracing.Tracer
+
+	// queue feeds the workers Start launches; slots bounds how many async transfers are in flight.
+	workers int
+	queue   chan model.TransferRequest
+	slots   chan struct{}
+	quit    chan struct{}
+	wg      sync.WaitGroup
+	once    sync.Once
 }
 
 var _ port.TransferService = (*transferService)(nil)
 
-func NewTransferService(dao port.TransferDao, tracer tracing.Tracer) port.TransferService {
-	return &transferService{dao: dao, tracer: tracer}
+func newTransferService(dao port.TransferDao, receipts *receiptService, policy *policy, limits *rateLimitService, tracer tracing.Tracer, conf config.Async) *transferService {
+	s := &transferService{
+		dao:      dao,
+		receipts: receipts,
+		policy:   policy,
+		limits:   limits,
+		tracer:   tracer,
+		workers:  max(conf.Workers, 1),
+		queue:    make(chan model.TransferRequest, max(conf.QueueSize, 1)),
+		slots:    make(chan struct{}, max(conf.QueueSize, 1)),
+		quit:     make(chan struct{}),
+	}
+	return s
 }
 
 func (s *transferService) ProcessTransfer(ctx context.Context, req model.TransferRequest) (*model.TransferResponse, error) {
+	if reservedRequestID(req.RequestID) {
+		return nil, ErrValidation
+	}
+	return s.process(ctx, req, true)
+}
+
+// process runs a transfer under any request ID, including the ones the service reserves for
+// reversals and imports. A limited transfer counts against its source account's rate limit
+// once the caller is known to be allowed to send from it, so no one uses up another's budget.
+func (s *transferService) process(ctx context.Context, req model.TransferRequest, limited bool) (*model.TransferResponse, error) {
 	ctx, span := s.tracer.Start(ctx, "service.transfer.process")
 	defer span.End()
 
-	if req.SourceAccountID <= 0 ||
-		req.DestinationAccountID <= 0 ||
-		strings.TrimSpace(req.Amount) == "" {
-		err := ErrValidation
+	if err := validateRequest(req); err != nil {
 		span.RecordError(err)
 		return nil, err
 	}
 
-	if req.SourceAccountID == req.DestinationAccountID {
-		err := ErrValidation
+	if err := s.policy.requireAccount(ctx, auth.ScopeTransfersCreate, req.SourceAccountID); err != nil {
 		span.RecordError(err)
 		return nil, err
 	}
 
+	if limited {
+		if err := s.limits.charge(ctx, model.RateLimitAccount, strconv.FormatInt(req.SourceAccountID, 10)); err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+	}
+
 	result, err := s.dao.RunTransferTx(ctx, req)
 	if err != nil {
 		span.RecordError(err)
+		switch {
+		case isNotFound(err):
+			return nil, ErrNotFound
+		case isInsufficient(err):
+			return nil, ErrInsufficientFunds
+		case isFrozen(err):
+			return nil, ErrAccountFrozen
+		}
 		return nil, err
 	}
 
-	return &model.TransferResponse{
+	// A replayed request ID returns the transfer it first created, which may be someone else's.
+	if result.SourceAccountID != req.SourceAccountID || result.DestinationAccountID != req.DestinationAccountID {
+		if err := s.policy.requireAccount(ctx, auth.ScopeAccountsRead, result.SourceAccountID, result.DestinationAccountID); err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+	}
+
+	resp := &model.TransferResponse{
 		TransactionID:        result.TransactionID,
+		RequestID:            result.RequestID,
 		SourceAccountID:      result.SourceAccountID,
 		DestinationAccountID: result.DestinationAccountID,
 		Amount:               result.Amount,
 		CreatedAt:            result.CreatedAt,
+	}
+	s.receipts.sign(resp)
+	return resp, nil
+}
+
+// SubmitTransfer records the transfer as pending and hands it to the worker pool.
+// It fails fast with ErrBusy once the pool's queue is full.
+func (s *transferService) SubmitTransfer(ctx context.Context, req model.TransferRequest) (*model.TransferStatusResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "service.transfer.submit")
+	defer span.End()
+
+	if err := validateTransfer(req); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	if err := s.policy.requireAccount(ctx, auth.ScopeTransfersCreate, req.SourceAccountID); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	if err := s.limits.charge(ctx, model.RateLimitAccount, strconv.FormatInt(req.SourceAccountID, 10)); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	if req.RequestID == "" {
+		req.RequestID = uuid.NewString()
+	}
+
+	select {
+	case s.slots <- struct{}{}:
+	default:
+		span.RecordError(ErrBusy)
+		return nil, ErrBusy
+	}
+
+	if err := s.dao.CreatePendingTransfer(ctx, req); err != nil {
+		<-s.slots
+		span.RecordError(err)
+		if isUnique(err) {
+			return s.replayedStatus(ctx, req.RequestID)
+		}
+		return nil, err
+	}
+
+	s.queue <- req
+
+	return &model.TransferStatusResponse{
+		TransferID:           req.RequestID,
+		Status:               model.TransferPending,
+		SourceAccountID:      req.SourceAccountID,
+		DestinationAccountID: req.DestinationAccountID,
 	}, nil
 }
+
+func (s *transferService) replayedStatus(ctx context.Context, requestID string) (*model.TransferStatusResponse, error) {
+	status, err := s.dao.GetTransferStatus(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.policy.requireAccount(ctx, auth.ScopeAccountsRead, status.SourceAccountID, status.DestinationAccountID); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// GetTransfer accepts either a numeric transaction ID or an async transfer ID.
+func (s *transferService) GetTransfer(ctx context.Context, id string) (*model.TransferStatusResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "service.transfer.get")
+	defer span.End()
+
+	if id == "" || len(id) > maxRequestIDLen {
+		err := ErrValidation
+		span.RecordError(err)
+		return nil, err
+	}
+
+	if txID, err := strconv.ParseInt(id, 10, 64); err == nil {
+		tr, err := s.dao.GetTransferByID(ctx, txID)
+		if err != nil {
+			span.RecordError(err)
+			return nil, ErrNotFound
+		}
+		if err := s.policy.requireAccount(ctx, auth.ScopeAccountsRead, tr.SourceAccountID, tr.DestinationAccountID); err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+		s.receipts.sign(tr)
+		return &model.TransferStatusResponse{
+			TransferID:           id,
+			Status:               model.TransferCompleted,
+			SourceAccountID:      tr.SourceAccountID,
+			DestinationAccountID: tr.DestinationAccountID,
+			Transfer:             tr,
+		}, nil
+	}
+
+	status, err := s.dao.GetTransferStatus(ctx, id)
+	if err != nil {
+		span.RecordError(err)
+		return nil, ErrNotFound
+	}
+	if err := s.policy.requireAccount(ctx, auth.ScopeAccountsRead, status.SourceAccountID, status.DestinationAccountID); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	s.receipts.sign(status.Transfer)
+	return status, nil
+}
+
+// GetTransfers returns the committed transfers among ids that the caller may read, in no
+// particular order. A transfer is readable by whoever may read its source or destination.
+func (s *transferService) GetTransfers(ctx context.Context, ids []int64) ([]model.TransferResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "service.transfer.get_many")
+	defer span.End()
+
+	if err := validateBatch(ids); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	transfers, err := s.dao.GetTransfersByIDs(ctx, ids)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	accounts := make([]int64, 0, 2*len(transfers))
+	for _, tr := range transfers {
+		accounts = append(accounts, tr.SourceAccountID, tr.DestinationAccountID)
+	}
+	allowed, err := s.policy.accessible(ctx, auth.ScopeAccountsRead, accounts)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	visible := transfers[:0]
+	for _, tr := range transfers {
+		if allowed == nil || allowed[tr.SourceAccountID] || allowed[tr.DestinationAccountID] {
+			s.receipts.sign(&tr)
+			visible = append(visible, tr)
+		}
+	}
+	return visible, nil
+}
+
+// reversalPrefix makes the request ID of a transfer's reversal, so reversing it again replays
+// the first reversal instead of moving the money twice.
+const reversalPrefix = "reversal:"
+
+// ReverseTransfer sends a committed transfer's amount from its destination back to its source
+// as a new transfer. Only admins may reverse transfers.
+func (s *transferService) ReverseTransfer(ctx context.Context, id int64) (*model.TransferResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "service.transfer.reverse")
+	defer span.End()
+
+	if id <= 0 {
+		err := ErrValidation
+		span.RecordError(err)
+		return nil, err
+	}
+
+	if _, err := s.policy.require(ctx, auth.ScopeAdmin); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	tr, err := s.dao.GetTransferByID(ctx, id)
+	if err != nil {
+		span.RecordError(err)
+		return nil, ErrNotFound
+	}
+
+	req := model.TransferRequest{
+		SourceAccountID:      tr.DestinationAccountID,
+		DestinationAccountID: tr.SourceAccountID,
+		Amount:               tr.Amount,
+		RequestID:            reversalPrefix + strconv.FormatInt(id, 10),
+	}
+	res, err := s.process(ctx, req, false)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	// The request ID can only replay an earlier reversal of this transfer, but a transfer that
+	// is not the exact reverse must never be reported as one.
+	if !sameTransfer(req, res) {
+		span.RecordError(ErrConflict)
+		return nil, ErrConflict
+	}
+	return res, nil
+}
+
+// Start launches the async workers and re-queues transfers left pending by a previous run.
+// It must run after migrations. Until then submitted transfers wait in the queue.
+func (s *transferService) Start(ctx context.Context) {
+	for range s.workers {
+		s.wg.Add(1)
+		go s.work()
+	}
+	s.wg.Add(1)
+	go s.recoverPending(ctx, cap(s.slots))
+}
+
+// Shutdown stops the async workers after their current transfer.
+// Queued transfers stay pending in the DB and are picked up again on the next start.
+func (s *transferService) Shutdown(ctx context.Context) error {
+	s.once.Do(func() { close(s.quit) })
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *transferService) work() {
+	defer s.wg.Done()
+
+	for {
+		select {
+		case <-s.quit:
+			return
+		case req := <-s.queue:
+			s.processAsync(req)
+			<-s.slots
+		}
+	}
+}
+
+func (s *transferService) processAsync(req model.TransferRequest) {
+	// The worker acts on its own; the request ID links it to the submission's audit record.
+	actor := audit.System("async")
+	actor.CorrelationID = req.RequestID
+
+	ctx, span := s.tracer.Start(audit.WithActor(context.Background(), actor), "service.transfer.async")
+	defer span.End()
+
+	span.SetAttributes("transfer.request_id", req.RequestID)
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		if _, err = s.dao.RunTransferTx(ctx, req); err == nil || asyncRefusal(err) != "" || attempt == asyncAttempts {
+			break
+		}
+		span.RecordError(err)
+		select {
+		case <-s.quit:
+			return
+		case <-time.After(time.Duration(attempt) * asyncRetryDelay):
+		}
+	}
+
+	// Anything but a refusal the caller would have got from a sync transfer may pass on its own,
+	// so the transfer stays pending and the next start picks it up again.
+	reason := asyncRefusal(err)
+	if err != nil && reason == "" {
+		span.RecordError(err)
+		slog.ErrorContext(ctx, "async transfer left pending", "request_id", req.RequestID, "error", err)
+		return
+	}
+
+	if err := s.dao.SettlePendingTransfer(ctx, req.RequestID, reason); err != nil {
+		span.RecordError(err)
+		slog.ErrorContext(ctx, "failed to settle async transfer", "request_id", req.RequestID, "error", err)
+	}
+}
+
+// asyncRefusal is the failure reason recorded for err, or "" if err is not a final refusal.
+func asyncRefusal(err error) string {
+	switch {
+	case isNotFound(err):
+		return ErrNotFound.Error()
+	case isInsufficient(err):
+		return ErrInsufficientFunds.Error()
+	case isFrozen(err):
+		return ErrAccountFrozen.Error()
+	}
+	return ""
+}
+
+// recoverPending re-queues transfers left pending by a previous run.
+// Replaying one that already committed is harmless because RequestID is unique.
+func (s *transferService) recoverPending(ctx context.Context, limit int) {
+	defer s.wg.Done()
+
+	count := 0
+	for afterID := ""; ; {
+		pending, err := s.dao.ListPendingTransfers(ctx, afterID, limit)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to load pending transfers", "error", err)
+			return
+		}
+		if len(pending) == 0 {
+			break
+		}
+
+		for _, req := range pending {
+			select {
+			case <-s.quit:
+				return
+			case s.slots <- struct{}{}:
+				s.queue <- req
+			}
+		}
+		count += len(pending)
+		afterID = pending[len(pending)-1].RequestID
+	}
+
+	if count > 0 {
+		slog.InfoContext(ctx, "recovered pending transfers", "count", count)
+	}
+}
+
+// validateTransfer checks a transfer a client asked for. Request IDs with a reserved prefix are
+// refused, so no client can claim the ID of a later reversal or import row in advance.
+func validateTransfer(req model.TransferRequest) error {
+	if reservedRequestID(req.RequestID) {
+		return ErrValidation
+	}
+	return validateRequest(req)
+}
+
+// reservedRequestID reports whether id has a prefix the service generates request IDs with.
+func reservedRequestID(id string) bool {
+	return strings.HasPrefix(id, reversalPrefix) || strings.HasPrefix(id, importPrefix)
+}
+
+func validateRequest(req model.TransferRequest) error {
+	if req.SourceAccountID <= 0 ||
+		req.DestinationAccountID <= 0 ||
+		strings.TrimSpace(req.Amount) == "" {
+		return ErrValidation
+	}
+
+	if req.SourceAccountID == req.DestinationAccountID {
+		return ErrValidation
+	}
+
+	// Numeric IDs are reserved for transaction IDs in GET /v1/transfers/:id.
+	if len(req.RequestID) > maxRequestIDLen {
+		return ErrValidation
+	}
+	if _, err := strconv.ParseInt(req.RequestID, 10, 64); err == nil {
+		return ErrValidation
+	}
+
+	// A negative amount would pull money from the destination, which the caller need not own.
+	amount, err := decimal.ParseMoney(req.Amount)
+	if err != nil || !amount.IsPositive() {
+		return ErrValidation
+	}
+
+	return nil
+}
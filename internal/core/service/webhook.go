@@ -0,0 +1,462 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/url"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+	"txn-processor/config"
+	"txn-processor/internal/core/model"
+	"txn-processor/internal/port"
+	"txn-processor/pkg/auth"
+	"txn-processor/pkg/tracing"
+)
+
+const (
+	maxWebhookDeliveries = 500
+	webhookReplayPage    = 500
+)
+
+var webhookEventTypes = []string{
+	model.EventAccountCreated,
+	model.EventAccountShardsChanged,
+	model.EventTransferCompleted,
+}
+
+// WebhookService manages subscriptions and delivers outbox events to them.
+// It is also an EventPublisher: the outbox relay hands it every batch to fan out into the
+// delivery log, and a dispatcher sends due deliveries with exponential backoff.
+type WebhookService struct {
+	hooks  port.WebhookDao
+	outbox port.OutboxDao
+	audit  port.AuditDao
+	sender port.WebhookSender
+	policy *policy
+	tracer tracing.Tracer
+	conf   config.Webhook
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+	once sync.Once
+}
+
+var (
+	_ port.WebhookService = (*WebhookService)(nil)
+	_ port.EventPublisher = (*WebhookService)(nil)
+)
+
+func NewWebhookService(hooks port.WebhookDao, outbox port.OutboxDao, audit port.AuditDao, sender port.WebhookSender, policy *policy, tracer tracing.Tracer, conf config.Webhook) *WebhookService {
+	conf.PollIntervalMs = max(conf.PollIntervalMs, 10)
+	conf.BatchSize = max(conf.BatchSize, 1)
+	conf.TimeoutMs = max(conf.TimeoutMs, 100)
+	conf.MaxAttempts = max(conf.MaxAttempts, 1)
+	conf.RetryBaseMs = max(conf.RetryBaseMs, 1)
+	conf.RetryMaxSec = max(conf.RetryMaxSec, 1)
+
+	return &WebhookService{
+		hooks:  hooks,
+		outbox: outbox,
+		audit:  audit,
+		sender: sender,
+		policy: policy,
+		tracer: tracer,
+		conf:   conf,
+		quit:   make(chan struct{}),
+	}
+}
+
+func (s *WebhookService) CreateWebhook(ctx context.Context, req model.WebhookCreateRequest) (*model.WebhookResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "service.webhook.create")
+	defer span.End()
+
+	if err := validateWebhook(req); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	if _, err := s.policy.require(ctx, auth.ScopeAdmin); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	res, err := s.hooks.CreateWebhook(ctx, model.Webhook{
+		URL:        req.URL,
+		Secret:     secret,
+		EventTypes: req.EventTypes,
+		AccountID:  req.AccountID,
+	})
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *WebhookService) GetWebhook(ctx context.Context, id int64) (*model.WebhookResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "service.webhook.get")
+	defer span.End()
+
+	if id <= 0 {
+		span.RecordError(ErrValidation)
+		return nil, ErrValidation
+	}
+
+	if _, err := s.policy.require(ctx, auth.ScopeAdmin); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	res, err := s.hooks.GetWebhook(ctx, id)
+	if err != nil {
+		span.RecordError(err)
+		if isNotFound(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *WebhookService) DeleteWebhook(ctx context.Context, id int64) error {
+	ctx, span := s.tracer.Start(ctx, "service.webhook.delete")
+	defer span.End()
+
+	if id <= 0 {
+		span.RecordError(ErrValidation)
+		return ErrValidation
+	}
+
+	if _, err := s.policy.require(ctx, auth.ScopeAdmin); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	if err := s.hooks.DeleteWebhook(ctx, id); err != nil {
+		span.RecordError(err)
+		if isNotFound(err) {
+			return ErrNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// EnableWebhook turns a subscription back on after it was disabled for failing.
+func (s *WebhookService) EnableWebhook(ctx context.Context, id int64) (*model.WebhookResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "service.webhook.enable")
+	defer span.End()
+
+	if id <= 0 {
+		span.RecordError(ErrValidation)
+		return nil, ErrValidation
+	}
+
+	if _, err := s.policy.require(ctx, auth.ScopeAdmin); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	if err := s.hooks.EnableWebhook(ctx, id); err != nil {
+		span.RecordError(err)
+		if isNotFound(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	return s.GetWebhook(ctx, id)
+}
+
+func (s *WebhookService) ListDeliveries(ctx context.Context, id int64, limit int) ([]model.WebhookDelivery, error) {
+	ctx, span := s.tracer.Start(ctx, "service.webhook.deliveries")
+	defer span.End()
+
+	if _, err := s.GetWebhook(ctx, id); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	if limit <= 0 || limit > maxWebhookDeliveries {
+		limit = 50
+	}
+
+	res, err := s.hooks.ListDeliveries(ctx, id, limit)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// ReplayWebhook reschedules every matching event recorded since req.Since, including ones
+// that were already delivered or gave up.
+func (s *WebhookService) ReplayWebhook(ctx context.Context, id int64, req model.WebhookReplayRequest) (*model.WebhookReplayResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "service.webhook.replay")
+	defer span.End()
+
+	if req.Since.IsZero() || req.Since.After(time.Now()) {
+		span.RecordError(ErrValidation)
+		return nil, ErrValidation
+	}
+
+	hook, err := s.GetWebhook(ctx, id)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	queued := 0
+	afterID := int64(0)
+	for {
+		events, err := s.outbox.FetchSince(ctx, req.Since, afterID, webhookReplayPage)
+		if err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+
+		var deliveries []model.WebhookDelivery
+		for _, e := range events {
+			if webhookMatches(hook.EventTypes, hook.AccountID, e) {
+				deliveries = append(deliveries, newDelivery(hook.ID, e))
+			}
+		}
+		if err := s.hooks.EnqueueDeliveries(ctx, deliveries, true); err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+		queued += len(deliveries)
+
+		if len(events) < webhookReplayPage {
+			break
+		}
+		afterID = events[len(events)-1].ID
+	}
+
+	span.SetAttributes("webhook.replayed", queued)
+
+	res := &model.WebhookReplayResponse{Queued: queued}
+	if err := s.audit.AppendAudit(ctx, model.AuditEntry{
+		Action:       model.AuditWebhookReplay,
+		ResourceType: "webhook",
+		ResourceID:   strconv.FormatInt(id, 10),
+		After:        map[string]any{"since": req.Since, "queued": queued},
+	}); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Publish adds a delivery for every enabled subscription matching each event.
+// Republished batches are harmless because a (subscription, event) pair is only logged once.
+func (s *WebhookService) Publish(ctx context.Context, events []model.Event) error {
+	ctx, span := s.tracer.Start(ctx, "service.webhook.fanout")
+	defer span.End()
+
+	hooks, err := s.hooks.ActiveWebhooks(ctx)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	var deliveries []model.WebhookDelivery
+	for _, e := range events {
+		for _, h := range hooks {
+			if webhookMatches(h.EventTypes, h.AccountID, e) {
+				deliveries = append(deliveries, newDelivery(h.ID, e))
+			}
+		}
+	}
+
+	if err := s.hooks.EnqueueDeliveries(ctx, deliveries, false); err != nil {
+		span.RecordError(err)
+		return err
+	}
+	return nil
+}
+
+// Start runs the dispatcher when webhooks are enabled.
+func (s *WebhookService) Start(ctx context.Context) {
+	if !s.conf.IsEnabled {
+		return
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(time.Duration(s.conf.PollIntervalMs) * time.Millisecond)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.quit:
+				return
+			case <-ticker.C:
+				for {
+					n, err := s.Dispatch(ctx)
+					if err != nil {
+						slog.ErrorContext(ctx, "webhook dispatch failed", "error", err)
+					}
+					if err != nil || n < s.conf.BatchSize {
+						break
+					}
+				}
+			}
+		}
+	}()
+}
+
+func (s *WebhookService) Shutdown(ctx context.Context) error {
+	s.once.Do(func() { close(s.quit) })
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Dispatch sends one batch of due deliveries in parallel and returns how many it claimed.
+func (s *WebhookService) Dispatch(ctx context.Context) (int, error) {
+	timeout := time.Duration(s.conf.TimeoutMs) * time.Millisecond
+
+	// The lease outlives the HTTP timeout so a slow endpoint is not claimed twice.
+	attempts, err := s.hooks.ClaimDueDeliveries(ctx, s.conf.BatchSize, 2*timeout+time.Second)
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	for _, a := range attempts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.deliver(ctx, a)
+		}()
+	}
+	wg.Wait()
+
+	return len(attempts), nil
+}
+
+func (s *WebhookService) deliver(ctx context.Context, a model.WebhookAttempt) {
+	// The delivery continues the trace of the transaction that produced the event.
+	ctx, span := s.tracer.Start(tracing.Extract(ctx, a.Event.Headers), "service.webhook.deliver")
+	defer span.End()
+
+	span.SetAttributes("webhook.id", a.Webhook.ID, "webhook.event_id", a.Event.ID)
+
+	d := a.Delivery
+	d.Attempts++
+
+	code, err := s.sender.Send(ctx, a.Webhook, a.Event)
+	now := time.Now()
+	d.ResponseCode = code
+	d.NextAttemptAt = now
+
+	switch {
+	case err == nil:
+		d.Status = model.DeliverySucceeded
+		d.LastError = ""
+		d.DeliveredAt = &now
+	case d.Attempts >= s.conf.MaxAttempts:
+		span.RecordError(err)
+		d.Status = model.DeliveryFailed
+		d.LastError = err.Error()
+	default:
+		span.RecordError(err)
+		d.Status = model.DeliveryPending
+		d.LastError = err.Error()
+		d.NextAttemptAt = now.Add(s.backoff(d.Attempts))
+	}
+
+	disabled, err := s.hooks.RecordAttempt(ctx, d, s.conf.DisableAfter)
+	if err != nil {
+		span.RecordError(err)
+		slog.ErrorContext(ctx, "failed to record webhook attempt", "delivery_id", d.ID, "error", err)
+		return
+	}
+	if disabled {
+		slog.WarnContext(ctx, "webhook disabled after repeated failures", "webhook_id", a.Webhook.ID, "url", a.Webhook.URL)
+	}
+}
+
+// backoff returns RetryBaseMs * 2^(attempt-1), capped at RetryMaxSec.
+func (s *WebhookService) backoff(attempt int) time.Duration {
+	limit := time.Duration(s.conf.RetryMaxSec) * time.Second
+	d := time.Duration(s.conf.RetryBaseMs) * time.Millisecond
+	for i := 1; i < attempt && d < limit; i++ {
+		d *= 2
+	}
+	return min(d, limit)
+}
+
+func newDelivery(hookID int64, e model.Event) model.WebhookDelivery {
+	return model.WebhookDelivery{
+		SubscriptionID: hookID,
+		EventID:        e.ID,
+		EventType:      e.Type,
+	}
+}
+
+// webhookMatches applies a subscription's filters. A transfer matches both of its accounts.
+func webhookMatches(types []string, accountID *int64, e model.Event) bool {
+	if len(types) > 0 && !slices.Contains(types, e.Type) {
+		return false
+	}
+	return accountID == nil || e.AccountID == *accountID
+}
+
+func validateWebhook(req model.WebhookCreateRequest) error {
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || len(req.URL) > 2048 {
+		return ErrValidation
+	}
+
+	for _, t := range req.EventTypes {
+		if !slices.Contains(webhookEventTypes, t) {
+			return ErrValidation
+		}
+	}
+
+	if req.AccountID != nil && *req.AccountID <= 0 {
+		return ErrValidation
+	}
+
+	return nil
+}
+
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
@@ -2,13 +2,23 @@ package port
 
 import (
 	"context"
+	"io"
 	"txn-processor/internal/core/model"
+	"txn-processor/pkg/auth"
+	"txn-processor/pkg/receipt"
 )
 
 type Inbound interface {
 	HealthService
 	AccountService
 	TransferService
+	WebhookService
+	BalanceService
+	AuditService
+	ReceiptService
+	AuthService
+	RateLimitService
+	ImportService
 }
 
 type HealthService interface {
@@ -18,8 +28,72 @@ type HealthService interface {
 type AccountService interface {
 	CreateAccount(ctx context.Context, req model.AccountCreateRequest) (*model.AccountCreateResponse, error)
 	GetAccount(ctx context.Context, id int64) (*model.AccountGetResponse, error)
+	// GetAccounts returns the readable accounts among ids; the rest are left out.
+	GetAccounts(ctx context.Context, ids []int64) ([]model.AccountGetResponse, error)
+	SetAccountShards(ctx context.Context, id int64, req model.AccountShardRequest) (*model.AccountShardResponse, error)
+	GetAccountChanges(ctx context.Context, id int64, since int64, limit int) (*model.AccountChangesResponse, error)
+	GrantAccount(ctx context.Context, id int64, subject string) error
+	RevokeAccountGrant(ctx context.Context, id int64, subject string) error
+	ListAccounts(ctx context.Context, afterID int64, limit int) (*model.AccountListResponse, error)
+	SetAccountFrozen(ctx context.Context, id int64, frozen bool) (*model.AccountFreezeResponse, error)
+	Reconcile(ctx context.Context) (*model.ReconcileReport, error)
 }
 
 type TransferService interface {
 	ProcessTransfer(ctx context.Context, req model.TransferRequest) (*model.TransferResponse, error)
+	SubmitTransfer(ctx context.Context, req model.TransferRequest) (*model.TransferStatusResponse, error)
+	GetTransfer(ctx context.Context, id string) (*model.TransferStatusResponse, error)
+	// GetTransfers returns the readable committed transfers among ids; the rest are left out.
+	GetTransfers(ctx context.Context, ids []int64) ([]model.TransferResponse, error)
+	// ReverseTransfer moves a committed transfer's amount back, at most once per transfer.
+	ReverseTransfer(ctx context.Context, id int64) (*model.TransferResponse, error)
+}
+
+type WebhookService interface {
+	CreateWebhook(ctx context.Context, req model.WebhookCreateRequest) (*model.WebhookResponse, error)
+	GetWebhook(ctx context.Context, id int64) (*model.WebhookResponse, error)
+	DeleteWebhook(ctx context.Context, id int64) error
+	EnableWebhook(ctx context.Context, id int64) (*model.WebhookResponse, error)
+	ListDeliveries(ctx context.Context, id int64, limit int) ([]model.WebhookDelivery, error)
+	ReplayWebhook(ctx context.Context, id int64, req model.WebhookReplayRequest) (*model.WebhookReplayResponse, error)
+}
+
+type BalanceService interface {
+	StreamBalance(ctx context.Context, id int64, lastSeq int64) (<-chan model.BalanceUpdate, error)
+}
+
+type AuditService interface {
+	ListAudit(ctx context.Context, filter model.AuditFilter) (*model.AuditListResponse, error)
+	// ListEvents returns outbox events with an ID above afterID, oldest first.
+	ListEvents(ctx context.Context, afterID int64, limit int) (*model.EventListResponse, error)
+}
+
+// ReceiptService publishes the keys that verify transfer receipts.
+type ReceiptService interface {
+	ReceiptKeys(ctx context.Context) receipt.KeySet
+}
+
+type AuthService interface {
+	// Authenticate returns the caller proven by creds. It returns nil and no error when
+	// authentication is disabled.
+	Authenticate(ctx context.Context, creds model.Credentials) (*auth.Principal, error)
+	// AuthenticateSigned checks a partner's request signature, like Authenticate.
+	AuthenticateSigned(ctx context.Context, req model.SignedRequest) (*auth.Principal, error)
+	IssueAPIKey(ctx context.Context, req model.APIKeyIssueRequest) (*model.APIKeyIssueResponse, error)
+	RevokeAPIKey(ctx context.Context, id string) error
+}
+
+// ImportService loads accounts or transfers from CSV or NDJSON files in chunks. A job is
+// resumed by uploading the same file again under its ID.
+type ImportService interface {
+	Import(ctx context.Context, req model.ImportRequest, body io.Reader) (*model.ImportJob, error)
+	GetImport(ctx context.Context, id string) (*model.ImportJob, error)
+	// ListImportResults returns the outcomes of rows after afterRow, in row order.
+	ListImportResults(ctx context.Context, id string, afterRow int64, limit int) (*model.ImportResultList, error)
+}
+
+type RateLimitService interface {
+	// Allow counts a request by key against the limit of route, a route group. The service
+	// applies the account and auth limits itself. It returns nil when the route has no limit.
+	Allow(ctx context.Context, route, key string) (*model.RateLimitResult, error)
 }
@@ -2,6 +2,7 @@ package port
 
 import (
 	"context"
+	"time"
 	"txn-processor/internal/core/model"
 )
 
@@ -9,6 +10,12 @@ type Outbound interface {
 	HealthDao
 	AccountDao
 	TransferDao
+	OutboxDao
+	WebhookDao
+	AuditDao
+	ChainDao
+	APIKeyDao
+	ImportDao
 }
 
 type HealthDao interface {
@@ -18,8 +25,124 @@ type HealthDao interface {
 type AccountDao interface {
 	CreateAccount(ctx context.Context, req model.AccountCreateRequest) error
 	GetAccountByID(ctx context.Context, id int64) (*model.AccountGetResponse, error)
+	ReadAccountBalance(ctx context.Context, id int64) (*model.AccountGetResponse, error)
+	// GetAccountsByIDs returns the accounts that exist among ids, in no particular order.
+	GetAccountsByIDs(ctx context.Context, ids []int64) ([]model.AccountGetResponse, error)
+	SetAccountShards(ctx context.Context, id int64, shards int) error
+	SequenceChanges(ctx context.Context, limit int) (int, error)
+	ListAccountChanges(ctx context.Context, id int64, since int64, limit int) ([]model.AccountChange, error)
+	// AccountAccess returns one of the model.Access values for subject on the account.
+	AccountAccess(ctx context.Context, id int64, subject string) (string, error)
+	// AccessibleAccounts returns the accounts among ids that subject owns or is a delegate of.
+	AccessibleAccounts(ctx context.Context, subject string, ids []int64) ([]int64, error)
+	GrantAccount(ctx context.Context, id int64, subject string) error
+	RevokeAccountGrant(ctx context.Context, id int64, subject string) error
+	// ListAccounts returns up to limit accounts with an ID above afterID, in ID order.
+	ListAccounts(ctx context.Context, afterID int64, limit int) ([]model.AccountGetResponse, error)
+	SetAccountFrozen(ctx context.Context, id int64, frozen bool) error
+	// Reconcile checks balances against the change log, reporting at most limit mismatches.
+	Reconcile(ctx context.Context, limit int) (*model.ReconcileReport, error)
 }
 
 type TransferDao interface {
 	RunTransferTx(ctx context.Context, req model.TransferRequest) (*model.TransferResponse, error)
+	GetTransferByID(ctx context.Context, id int64) (*model.TransferResponse, error)
+	// GetTransfersByIDs returns the committed transfers among ids, in no particular order.
+	GetTransfersByIDs(ctx context.Context, ids []int64) ([]model.TransferResponse, error)
+	CreatePendingTransfer(ctx context.Context, req model.TransferRequest) error
+	GetTransferStatus(ctx context.Context, requestID string) (*model.TransferStatusResponse, error)
+	SettlePendingTransfer(ctx context.Context, requestID string, reason string) error
+	// ListPendingTransfers returns up to limit pending async transfers with an ID above afterID, in ID order.
+	ListPendingTransfers(ctx context.Context, afterID string, limit int) ([]model.TransferRequest, error)
+}
+
+type OutboxDao interface {
+	FetchUnsent(ctx context.Context, limit int) ([]model.Event, error)
+	FetchSince(ctx context.Context, since time.Time, afterID int64, limit int) ([]model.Event, error)
+	MarkSent(ctx context.Context, ids []int64) error
+	AcquireRelayLease(ctx context.Context, owner string, ttl time.Duration) (bool, error)
+}
+
+// EventPublisher delivers outbox events to a broker. Publish receives events in commit order
+// and must return an error unless every event was accepted.
+type EventPublisher interface {
+	Publish(ctx context.Context, events []model.Event) error
+}
+
+type WebhookDao interface {
+	CreateWebhook(ctx context.Context, hook model.Webhook) (*model.WebhookResponse, error)
+	GetWebhook(ctx context.Context, id int64) (*model.WebhookResponse, error)
+	DeleteWebhook(ctx context.Context, id int64) error
+	EnableWebhook(ctx context.Context, id int64) error
+	ActiveWebhooks(ctx context.Context) ([]model.Webhook, error)
+	EnqueueDeliveries(ctx context.Context, deliveries []model.WebhookDelivery, reset bool) error
+	ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]model.WebhookAttempt, error)
+	RecordAttempt(ctx context.Context, delivery model.WebhookDelivery, disableAfter int) (bool, error)
+	ListDeliveries(ctx context.Context, subscriptionID int64, limit int) ([]model.WebhookDelivery, error)
+}
+
+// AuditDao appends to and reads the audit log. It has no way to change or remove a record.
+type AuditDao interface {
+	AppendAudit(ctx context.Context, entry model.AuditEntry) error
+	ListAudit(ctx context.Context, filter model.AuditFilter) ([]model.AuditRecord, error)
+}
+
+// ChainDao extends the transfer hash chain and stores signed checkpoints of its head.
+type ChainDao interface {
+	// LinkTransfers appends up to limit unchained transfers to the chain and returns the new
+	// head and how many were linked.
+	LinkTransfers(ctx context.Context, limit int) (model.ChainHead, int, error)
+	// LatestCheckpoint returns the checkpoint with the highest Seq, or nil if there is none.
+	LatestCheckpoint(ctx context.Context) (*model.ChainCheckpoint, error)
+	CreateCheckpoint(ctx context.Context, checkpoint model.ChainCheckpoint) error
+}
+
+// APIKeyDao stores API keys by the hash of their secret.
+type APIKeyDao interface {
+	CreateAPIKey(ctx context.Context, key model.APIKey) (*model.APIKey, error)
+	GetAPIKey(ctx context.Context, id string) (*model.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id string) error
+	ClaimNonce(ctx context.Context, keyID, nonce string, ttl time.Duration) (bool, error)
+}
+
+// ImportDao stores bulk import jobs and the results of their rows.
+type ImportDao interface {
+	// StartImport returns the job, creating it for kind if it does not exist yet.
+	StartImport(ctx context.Context, id, kind string) (*model.ImportJob, error)
+	GetImport(ctx context.Context, id string) (*model.ImportJob, error)
+	// ApplyImportChunk creates the chunk's accounts, records every row's result and moves the
+	// job past the chunk in one transaction. It fails if the job is not at chunk.After.
+	ApplyImportChunk(ctx context.Context, id string, chunk model.ImportChunk) (*model.ImportJob, error)
+	FinishImport(ctx context.Context, id string) (*model.ImportJob, error)
+	// ListImportResults returns up to limit results with a row above afterRow, in row order.
+	ListImportResults(ctx context.Context, id string, afterRow int64, limit int) ([]model.ImportResult, error)
+}
+
+// WebhookSender POSTs a signed event to a subscriber. It returns the HTTP status and an error
+// unless the subscriber answered 2xx.
+type WebhookSender interface {
+	Send(ctx context.Context, hook model.Webhook, event model.Event) (int, error)
+}
+
+// RateLimiter counts requests against limits shared by every replica.
+type RateLimiter interface {
+	// Allow counts one request for key and reports whether it fits within limit.
+	Allow(ctx context.Context, key string, limit model.RateLimit) (model.RateLimitResult, error)
+	// Peek reports whether one more request for key would fit within limit, without counting it.
+	Peek(ctx context.Context, key string, limit model.RateLimit) (model.RateLimitResult, error)
+}
+
+// BalanceBroker fans balance updates out to every replica.
+type BalanceBroker interface {
+	// Publish assigns the next per-account sequence number, records the update for resuming
+	// clients and broadcasts it. It returns the update with Seq set.
+	Publish(ctx context.Context, update model.BalanceUpdate) (model.BalanceUpdate, error)
+	// Subscribe delivers updates for the account until ctx is done. The channel is closed
+	// when ctx is done or the subscriber falls too far behind.
+	Subscribe(ctx context.Context, accountID int64) (<-chan model.BalanceUpdate, error)
+	// History returns recorded updates with Seq > afterSeq, oldest first.
+	History(ctx context.Context, accountID int64, afterSeq int64) ([]model.BalanceUpdate, error)
+	// LastSeq returns the sequence number of the account's latest update, 0 if none.
+	LastSeq(ctx context.Context, accountID int64) (int64, error)
+	Close() error
 }
@@ -0,0 +1,39 @@
+// Package audit carries who is performing an operation from the edge of the service to the
+// code that records it.
+package audit
+
+import "context"
+
+const (
+	// Anonymous is the actor of requests that did not authenticate.
+	Anonymous = "anonymous"
+
+	systemPrefix = "system:"
+)
+
+// Actor identifies who performed an operation and where the request came from.
+type Actor struct {
+	ID            string
+	CorrelationID string
+	SourceIP      string
+}
+
+// System returns the actor for work the service starts on its own, such as async workers.
+func System(name string) Actor {
+	return Actor{ID: systemPrefix + name}
+}
+
+type actorKey struct{}
+
+// WithActor returns ctx carrying a.
+func WithActor(ctx context.Context, a Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, a)
+}
+
+// FromContext returns the actor carried by ctx, or the generic system actor when there is none.
+func FromContext(ctx context.Context) Actor {
+	if a, ok := ctx.Value(actorKey{}).(Actor); ok {
+		return a
+	}
+	return System("unknown")
+}
@@ -0,0 +1,237 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	ErrMalformedToken = errors.New("auth: malformed token")
+	ErrUnknownKey     = errors.New("auth: unknown signing key")
+	ErrBadSignature   = errors.New("auth: token signature mismatch")
+	ErrExpiredToken   = errors.New("auth: token expired or not yet valid")
+	ErrBadClaims      = errors.New("auth: token issuer or audience not accepted")
+)
+
+// Claims are the registered JWT claims the service checks, plus the space-separated scopes.
+type Claims struct {
+	Subject   string   `json:"sub"`
+	Issuer    string   `json:"iss"`
+	Audience  audience `json:"aud"`
+	ExpiresAt int64    `json:"exp"`
+	NotBefore int64    `json:"nbf"`
+	Scope     string   `json:"scope"`
+}
+
+// audience accepts both forms of "aud": a single string or an array.
+type audience []string
+
+func (a *audience) UnmarshalJSON(b []byte) error {
+	var one string
+	if err := json.Unmarshal(b, &one); err == nil {
+		*a = audience{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(b, &many); err != nil {
+		return err
+	}
+	*a = many
+	return nil
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+type verifyingKey struct {
+	alg string
+	key crypto.PublicKey
+}
+
+// TokenVerifier checks JWTs signed with RS256, ES256 or EdDSA by a key in a fixed JWKS. A
+// token without "exp" is rejected; Issuer and Audience are only checked when set.
+type TokenVerifier struct {
+	keys     map[string]verifyingKey
+	issuer   string
+	audience string
+	leeway   time.Duration
+}
+
+// LoadTokenVerifier reads a JWKS document from path.
+func LoadTokenVerifier(path, issuer, audience string, leeway time.Duration) (*TokenVerifier, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewTokenVerifier(b, issuer, audience, leeway)
+}
+
+// NewTokenVerifier parses a JWKS document. Every key needs a kid; keys of unsupported types
+// are an error rather than silently ignored.
+func NewTokenVerifier(jwks []byte, issuer, audience string, leeway time.Duration) (*TokenVerifier, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(jwks, &set); err != nil {
+		return nil, fmt.Errorf("auth: parsing JWKS: %w", err)
+	}
+
+	v := &TokenVerifier{keys: make(map[string]verifyingKey, len(set.Keys)), issuer: issuer, audience: audience, leeway: leeway}
+	for _, k := range set.Keys {
+		if k.Kid == "" {
+			return nil, fmt.Errorf("auth: JWKS key without kid")
+		}
+		key, err := parseJWK(k)
+		if err != nil {
+			return nil, fmt.Errorf("auth: JWKS key %q: %w", k.Kid, err)
+		}
+		v.keys[k.Kid] = key
+	}
+	return v, nil
+}
+
+func parseJWK(k jwk) (verifyingKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := b64Int(k.N)
+		if err != nil {
+			return verifyingKey{}, err
+		}
+		e, err := b64Int(k.E)
+		if err != nil || !e.IsInt64() {
+			return verifyingKey{}, errors.New("invalid exponent")
+		}
+		return verifyingKey{alg: "RS256", key: &rsa.PublicKey{N: n, E: int(e.Int64())}}, nil
+	case "EC":
+		if k.Crv != "P-256" {
+			return verifyingKey{}, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := b64Int(k.X)
+		if err != nil {
+			return verifyingKey{}, err
+		}
+		y, err := b64Int(k.Y)
+		if err != nil {
+			return verifyingKey{}, err
+		}
+		return verifyingKey{alg: "ES256", key: &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}}, nil
+	case "OKP":
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || k.Crv != "Ed25519" || len(x) != ed25519.PublicKeySize {
+			return verifyingKey{}, errors.New("invalid Ed25519 key")
+		}
+		return verifyingKey{alg: "EdDSA", key: ed25519.PublicKey(x)}, nil
+	default:
+		return verifyingKey{}, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+// Verify checks the token's signature and time and returns its claims and the signing kid.
+func (v *TokenVerifier) Verify(token string, now time.Time) (Claims, string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Claims{}, "", ErrMalformedToken
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return Claims{}, "", ErrMalformedToken
+	}
+
+	// The key decides the algorithm, so a token cannot pick a weaker one for it.
+	key, ok := v.keys[header.Kid]
+	if !ok {
+		return Claims{}, "", ErrUnknownKey
+	}
+	if header.Alg != key.alg {
+		return Claims{}, "", ErrBadSignature
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Claims{}, "", ErrMalformedToken
+	}
+	if !verifySignature(key, []byte(parts[0]+"."+parts[1]), sig) {
+		return Claims{}, "", ErrBadSignature
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil || claims.Subject == "" {
+		return Claims{}, "", ErrMalformedToken
+	}
+
+	if claims.ExpiresAt == 0 || now.After(time.Unix(claims.ExpiresAt, 0).Add(v.leeway)) {
+		return Claims{}, "", ErrExpiredToken
+	}
+	if claims.NotBefore != 0 && now.Add(v.leeway).Before(time.Unix(claims.NotBefore, 0)) {
+		return Claims{}, "", ErrExpiredToken
+	}
+	if v.issuer != "" && claims.Issuer != v.issuer {
+		return Claims{}, "", ErrBadClaims
+	}
+	if v.audience != "" && !slices.Contains(claims.Audience, v.audience) {
+		return Claims{}, "", ErrBadClaims
+	}
+
+	return claims, header.Kid, nil
+}
+
+func verifySignature(k verifyingKey, input, sig []byte) bool {
+	switch key := k.key.(type) {
+	case *rsa.PublicKey:
+		sum := sha256.Sum256(input)
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, sum[:], sig) == nil
+	case *ecdsa.PublicKey:
+		// JWS carries ES256 signatures as the raw 32-byte r and s, not ASN.1.
+		if len(sig) != 64 {
+			return false
+		}
+		sum := sha256.Sum256(input)
+		r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])
+		return ecdsa.Verify(key, sum[:], r, s)
+	case ed25519.PublicKey:
+		return ed25519.Verify(key, input, sig)
+	default:
+		return false
+	}
+}
+
+func decodeSegment(seg string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+func b64Int(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(b) == 0 {
+		return nil, errors.New("invalid base64url integer")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,272 @@
+// Package bulk reads and writes the row files of bulk imports: CSV with a header row, or NDJSON
+// with one JSON object per line. Either way a row is a set of named string fields, so callers
+// parse values the same way for both formats.
+package bulk
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"strconv"
+	"strings"
+)
+
+const (
+	CSV    = "csv"
+	NDJSON = "ndjson"
+)
+
+// ErrMalformed is returned by Reader.Next when the file cannot be parsed any further, as
+// opposed to a malformed row, which is reported in Row.Err.
+var ErrMalformed = errors.New("malformed file")
+
+// ContentType returns the media type of files in format.
+func ContentType(format string) string {
+	if format == CSV {
+		return "text/csv"
+	}
+	return "application/x-ndjson"
+}
+
+// FormatOf returns the format of a media type such as "text/csv; charset=utf-8", or "" if it
+// is neither CSV nor NDJSON.
+func FormatOf(contentType string) string {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	switch mt {
+	case "text/csv":
+		return CSV
+	case "application/x-ndjson", "application/ndjson", "application/jsonl":
+		return NDJSON
+	default:
+		return ""
+	}
+}
+
+// Row is one input row, numbered from 1 after the header. Err is set when the row itself is
+// malformed; the rows after it can still be read.
+type Row struct {
+	Num    int64
+	Fields map[string]string
+	Err    error
+}
+
+// Reader returns rows in file order and io.EOF after the last one. Any other error means the
+// rest of the file cannot be read.
+type Reader interface {
+	Next() (Row, error)
+}
+
+// NewReader reads rows of format from r.
+func NewReader(r io.Reader, format string) (Reader, error) {
+	switch format {
+	case CSV:
+		cr := csv.NewReader(r)
+		cr.TrimLeadingSpace = true
+		return &csvReader{r: cr}, nil
+	case NDJSON:
+		return &ndjsonReader{r: bufio.NewReader(r)}, nil
+	default:
+		return nil, fmt.Errorf("unknown format %q, want %s or %s", format, CSV, NDJSON)
+	}
+}
+
+type csvReader struct {
+	r      *csv.Reader
+	header []string
+	num    int64
+}
+
+func (c *csvReader) Next() (Row, error) {
+	if c.header == nil {
+		header, err := c.r.Read()
+		if err == io.EOF {
+			return Row{}, io.EOF
+		}
+		if err != nil {
+			return Row{}, malformed(err)
+		}
+		for i, h := range header {
+			header[i] = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+		}
+		c.header = header
+		c.r.FieldsPerRecord = len(header)
+	}
+
+	record, err := c.r.Read()
+	if err == io.EOF {
+		return Row{}, io.EOF
+	}
+	c.num++
+	row := Row{Num: c.num}
+	if errors.Is(err, csv.ErrFieldCount) {
+		row.Err = fmt.Errorf("%d fields, want %d", len(record), len(c.header))
+		return row, nil
+	}
+	if err != nil {
+		return Row{}, malformed(err)
+	}
+
+	row.Fields = make(map[string]string, len(record))
+	for i, v := range record {
+		row.Fields[c.header[i]] = strings.TrimSpace(v)
+	}
+	return row, nil
+}
+
+// malformed marks CSV syntax errors; read errors of the underlying reader pass unchanged.
+func malformed(err error) error {
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+	return err
+}
+
+type ndjsonReader struct {
+	r   *bufio.Reader
+	num int64
+}
+
+func (n *ndjsonReader) Next() (Row, error) {
+	for {
+		line, err := n.r.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return Row{}, err
+		}
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			if err == io.EOF {
+				return Row{}, io.EOF
+			}
+			continue
+		}
+
+		n.num++
+		row := Row{Num: n.num}
+		row.Fields, row.Err = parseObject(line)
+		return row, nil
+	}
+}
+
+// parseObject flattens a JSON object of scalars into strings; numbers keep their literal text.
+func parseObject(line []byte) (map[string]string, error) {
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.UseNumber()
+	var obj map[string]any
+	if err := dec.Decode(&obj); err != nil || obj == nil || dec.More() {
+		return nil, errors.New("not a JSON object")
+	}
+
+	fields := make(map[string]string, len(obj))
+	for k, v := range obj {
+		switch v := v.(type) {
+		case nil:
+		case string:
+			fields[k] = strings.TrimSpace(v)
+		case json.Number:
+			fields[k] = v.String()
+		case bool:
+			fields[k] = strconv.FormatBool(v)
+		default:
+			return nil, fmt.Errorf("field %q is not a string or number", k)
+		}
+	}
+	return fields, nil
+}
+
+// Writer writes rows of fixed columns. CSV files start with the column names; NDJSON objects
+// leave out empty values.
+type Writer struct {
+	columns []string
+	csv     *csv.Writer
+	w       *bufio.Writer
+}
+
+func NewWriter(w io.Writer, format string, columns ...string) (*Writer, error) {
+	bw := &Writer{columns: columns}
+	switch format {
+	case CSV:
+		bw.csv = csv.NewWriter(w)
+		if err := bw.csv.Write(columns); err != nil {
+			return nil, err
+		}
+	case NDJSON:
+		bw.w = bufio.NewWriter(w)
+	default:
+		return nil, fmt.Errorf("unknown format %q, want %s or %s", format, CSV, NDJSON)
+	}
+	return bw, nil
+}
+
+// Write writes one row holding a value for each column; strings and integers are supported
+// and their zero values count as empty.
+func (w *Writer) Write(values ...any) error {
+	if len(values) != len(w.columns) {
+		return fmt.Errorf("%d values for %d columns", len(values), len(w.columns))
+	}
+
+	if w.csv != nil {
+		record := make([]string, len(values))
+		for i, v := range values {
+			record[i] = text(v)
+		}
+		return w.csv.Write(record)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, v := range values {
+		if text(v) == "" {
+			continue
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(w.columns[i])
+		val, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(val)
+	}
+	buf.WriteString("}\n")
+	_, err := w.w.Write(buf.Bytes())
+	return err
+}
+
+func (w *Writer) Flush() error {
+	if w.csv != nil {
+		w.csv.Flush()
+		return w.csv.Error()
+	}
+	return w.w.Flush()
+}
+
+func text(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case int64:
+		if v == 0 {
+			return ""
+		}
+		return strconv.FormatInt(v, 10)
+	case int:
+		if v == 0 {
+			return ""
+		}
+		return strconv.Itoa(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,321 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"txn-processor/internal/core/model"
+	"txn-processor/pkg/bulk"
+	"txn-processor/pkg/receipt"
+
+	"github.com/google/uuid"
+)
+
+func accountPath(id int64) string {
+	return "/v1/accounts/" + strconv.FormatInt(id, 10)
+}
+
+func adminAccountPath(id int64) string {
+	return "/v1/admin/accounts/" + strconv.FormatInt(id, 10)
+}
+
+func webhookPath(id int64) string {
+	return "/v1/webhooks/" + strconv.FormatInt(id, 10)
+}
+
+// Health returns nil when the service and its database are up.
+func (c *Client) Health(ctx context.Context) error {
+	return c.do(ctx, call{method: http.MethodGet, path: "/v1/health", idempotent: true})
+}
+
+// OpenAPI returns the API's OpenAPI document.
+func (c *Client) OpenAPI(ctx context.Context) (json.RawMessage, error) {
+	var doc json.RawMessage
+	err := c.do(ctx, call{method: http.MethodGet, path: "/v1/openapi.json", out: &doc, idempotent: true})
+	return doc, err
+}
+
+// ReceiptKeys fetches the keys that verify transfer receipts.
+func (c *Client) ReceiptKeys(ctx context.Context) (receipt.KeySet, error) {
+	var ks receipt.KeySet
+	err := c.do(ctx, call{method: http.MethodGet, path: "/.well-known/jwks.json", out: &ks, accept: "application/jwk-set+json", idempotent: true})
+	return ks, err
+}
+
+// CreateAccount is not idempotent: a retry after a lost response fails with ErrConflict.
+func (c *Client) CreateAccount(ctx context.Context, req model.AccountCreateRequest) (*model.AccountCreateResponse, error) {
+	var res model.AccountCreateResponse
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/v1/accounts", in: req, out: &res}); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c *Client) GetAccount(ctx context.Context, id int64) (*model.AccountGetResponse, error) {
+	var res model.AccountGetResponse
+	if err := c.do(ctx, call{method: http.MethodGet, path: accountPath(id), out: &res, idempotent: true}); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetAccountChanges returns the changes after version since; limit 0 uses the server default.
+func (c *Client) GetAccountChanges(ctx context.Context, id int64, since int64, limit int) (*model.AccountChangesResponse, error) {
+	q := url.Values{"since": {strconv.FormatInt(since, 10)}}
+	if limit > 0 {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+	var res model.AccountChangesResponse
+	if err := c.do(ctx, call{method: http.MethodGet, path: accountPath(id) + "/changes", query: q, out: &res, idempotent: true}); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c *Client) GrantAccount(ctx context.Context, id int64, subject string) error {
+	return c.do(ctx, call{method: http.MethodPut, path: accountPath(id) + "/grants/" + url.PathEscape(subject), idempotent: true})
+}
+
+func (c *Client) RevokeAccountGrant(ctx context.Context, id int64, subject string) error {
+	return c.do(ctx, call{method: http.MethodDelete, path: accountPath(id) + "/grants/" + url.PathEscape(subject), idempotent: true})
+}
+
+// SetAccountShards needs the admin scope.
+func (c *Client) SetAccountShards(ctx context.Context, id int64, req model.AccountShardRequest) (*model.AccountShardResponse, error) {
+	var res model.AccountShardResponse
+	if err := c.do(ctx, call{method: http.MethodPut, path: adminAccountPath(id) + "/shards", in: req, out: &res, idempotent: true}); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ListAccounts pages through all accounts in ID order and needs the admin scope. Pass the
+// response's NextAfterID as afterID for the next page; limit 0 uses the server default.
+func (c *Client) ListAccounts(ctx context.Context, afterID int64, limit int) (*model.AccountListResponse, error) {
+	q := url.Values{}
+	if afterID > 0 {
+		q.Set("after_id", strconv.FormatInt(afterID, 10))
+	}
+	if limit > 0 {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+	var res model.AccountListResponse
+	if err := c.do(ctx, call{method: http.MethodGet, path: "/v1/admin/accounts", query: q, out: &res, idempotent: true}); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// FreezeAccount needs the admin scope. Transfers from or to a frozen account fail with
+// ErrConflict until UnfreezeAccount.
+func (c *Client) FreezeAccount(ctx context.Context, id int64) (*model.AccountFreezeResponse, error) {
+	var res model.AccountFreezeResponse
+	if err := c.do(ctx, call{method: http.MethodPut, path: adminAccountPath(id) + "/freeze", out: &res, idempotent: true}); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c *Client) UnfreezeAccount(ctx context.Context, id int64) (*model.AccountFreezeResponse, error) {
+	var res model.AccountFreezeResponse
+	if err := c.do(ctx, call{method: http.MethodDelete, path: adminAccountPath(id) + "/freeze", out: &res, idempotent: true}); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Reconcile checks every balance against the change log and needs the admin scope.
+func (c *Client) Reconcile(ctx context.Context) (*model.ReconcileReport, error) {
+	var res model.ReconcileReport
+	if err := c.do(ctx, call{method: http.MethodGet, path: "/v1/admin/reconcile", out: &res, idempotent: true}); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// CreateTransfer moves money and returns the committed transfer. Without a RequestID one is
+// generated, and every retry sends the same one.
+func (c *Client) CreateTransfer(ctx context.Context, req model.TransferRequest) (*model.TransferResponse, error) {
+	if req.RequestID == "" {
+		req.RequestID = uuid.NewString()
+	}
+	var res model.TransferResponse
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/v1/transfers", in: req, out: &res, idempotent: true}); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// SubmitTransfer queues a transfer; poll GetTransfer with the returned ID for the outcome.
+// Request IDs are handled as in CreateTransfer.
+func (c *Client) SubmitTransfer(ctx context.Context, req model.TransferRequest) (*model.TransferStatusResponse, error) {
+	if req.RequestID == "" {
+		req.RequestID = uuid.NewString()
+	}
+	var res model.TransferStatusResponse
+	q := url.Values{"mode": {"async"}}
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/v1/transfers", query: q, in: req, out: &res, idempotent: true}); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetTransfer looks a transfer up by transaction ID or request ID.
+func (c *Client) GetTransfer(ctx context.Context, id string) (*model.TransferStatusResponse, error) {
+	var res model.TransferStatusResponse
+	if err := c.do(ctx, call{method: http.MethodGet, path: "/v1/transfers/" + url.PathEscape(id), out: &res, idempotent: true}); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ReverseTransfer moves a committed transfer's amount back and needs the admin scope. The
+// server reverses a transfer at most once, so retries are safe.
+func (c *Client) ReverseTransfer(ctx context.Context, id int64) (*model.TransferResponse, error) {
+	var res model.TransferResponse
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/v1/admin/transfers/" + strconv.FormatInt(id, 10) + "/reverse", out: &res, idempotent: true}); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// CreateWebhook needs the admin scope. The secret is only returned here.
+func (c *Client) CreateWebhook(ctx context.Context, req model.WebhookCreateRequest) (*model.WebhookResponse, error) {
+	var res model.WebhookResponse
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/v1/webhooks", in: req, out: &res}); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c *Client) GetWebhook(ctx context.Context, id int64) (*model.WebhookResponse, error) {
+	var res model.WebhookResponse
+	if err := c.do(ctx, call{method: http.MethodGet, path: webhookPath(id), out: &res, idempotent: true}); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c *Client) DeleteWebhook(ctx context.Context, id int64) error {
+	return c.do(ctx, call{method: http.MethodDelete, path: webhookPath(id), idempotent: true})
+}
+
+func (c *Client) EnableWebhook(ctx context.Context, id int64) (*model.WebhookResponse, error) {
+	var res model.WebhookResponse
+	if err := c.do(ctx, call{method: http.MethodPost, path: webhookPath(id) + "/enable", out: &res, idempotent: true}); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c *Client) ListDeliveries(ctx context.Context, id int64, limit int) ([]model.WebhookDelivery, error) {
+	var q url.Values
+	if limit > 0 {
+		q = url.Values{"limit": {strconv.Itoa(limit)}}
+	}
+	var res []model.WebhookDelivery
+	if err := c.do(ctx, call{method: http.MethodGet, path: webhookPath(id) + "/deliveries", query: q, out: &res, idempotent: true}); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ReplayWebhook queues redeliveries; a retry may queue some of them twice.
+func (c *Client) ReplayWebhook(ctx context.Context, id int64, req model.WebhookReplayRequest) (*model.WebhookReplayResponse, error) {
+	var res model.WebhookReplayResponse
+	if err := c.do(ctx, call{method: http.MethodPost, path: webhookPath(id) + "/replay", in: req, out: &res}); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ListAudit needs the admin scope.
+func (c *Client) ListAudit(ctx context.Context, filter model.AuditFilter) (*model.AuditListResponse, error) {
+	q := url.Values{}
+	for k, v := range map[string]string{
+		"action":        filter.Action,
+		"resource_type": filter.ResourceType,
+		"resource_id":   filter.ResourceID,
+		"actor":         filter.Actor,
+	} {
+		if v != "" {
+			q.Set(k, v)
+		}
+	}
+	if filter.BeforeID > 0 {
+		q.Set("before_id", strconv.FormatInt(filter.BeforeID, 10))
+	}
+	if filter.Limit > 0 {
+		q.Set("limit", strconv.Itoa(filter.Limit))
+	}
+	var res model.AuditListResponse
+	if err := c.do(ctx, call{method: http.MethodGet, path: "/v1/admin/audit", query: q, out: &res, idempotent: true}); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ListEvents returns outbox events after afterID in commit order and needs the admin scope.
+func (c *Client) ListEvents(ctx context.Context, afterID int64, limit int) (*model.EventListResponse, error) {
+	q := url.Values{}
+	if afterID > 0 {
+		q.Set("after_id", strconv.FormatInt(afterID, 10))
+	}
+	if limit > 0 {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+	var res model.EventListResponse
+	if err := c.do(ctx, call{method: http.MethodGet, path: "/v1/admin/events", query: q, out: &res, idempotent: true}); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func importPath(id string) string {
+	return "/v1/admin/imports/" + url.PathEscape(id)
+}
+
+// Import uploads a CSV or NDJSON file (see pkg/bulk) of accounts or transfers to the import job
+// id and returns the job once every row went through. It is not retried, as r cannot be read
+// twice; after a failure, call Import again with the same id and file to resume.
+func (c *Client) Import(ctx context.Context, id, kind, format string, r io.Reader) (*model.ImportJob, error) {
+	var res model.ImportJob
+	if err := c.do(ctx, call{
+		method:      http.MethodPut,
+		path:        importPath(id),
+		query:       url.Values{"kind": {kind}},
+		out:         &res,
+		upload:      r,
+		contentType: bulk.ContentType(format),
+	}); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c *Client) GetImport(ctx context.Context, id string) (*model.ImportJob, error) {
+	var res model.ImportJob
+	if err := c.do(ctx, call{method: http.MethodGet, path: importPath(id), out: &res, idempotent: true}); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ListImportResults returns the outcomes of an import's rows after afterRow, in row order.
+func (c *Client) ListImportResults(ctx context.Context, id string, afterRow int64, limit int) (*model.ImportResultList, error) {
+	q := url.Values{}
+	if afterRow > 0 {
+		q.Set("after_row", strconv.FormatInt(afterRow, 10))
+	}
+	if limit > 0 {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+	var res model.ImportResultList
+	if err := c.do(ctx, call{method: http.MethodGet, path: importPath(id) + "/results", query: q, out: &res, idempotent: true}); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
@@ -0,0 +1,258 @@
+// Package client is the Go SDK for the txn-processor HTTP API.
+//
+// Calls take and return the service's own model types. Transfers get a request ID when the
+// caller leaves it empty, so retries cannot move money twice; retries back off exponentially
+// and honor Retry-After. Failed calls return an *Error that matches the sentinel errors of this
+// package with errors.Is. The trace context of each call's ctx is sent along, so server spans
+// join the caller's trace.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"txn-processor/pkg/reqsign"
+	"txn-processor/pkg/tracing"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultRetries    = 3
+	defaultMinBackoff = 100 * time.Millisecond
+	defaultMaxBackoff = 5 * time.Second
+)
+
+// Client calls one txn-processor deployment. It is safe for concurrent use.
+type Client struct {
+	baseURL    string
+	http       *http.Client
+	apiKey     string
+	token      string
+	signKeyID  string
+	signSecret string
+	userAgent  string
+
+	retries    int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+}
+
+type Option func(*Client)
+
+// WithAPIKey authenticates every call with an API key.
+func WithAPIKey(key string) Option {
+	return func(c *Client) { c.apiKey = key }
+}
+
+// WithBearerToken authenticates every call with a JWT or API key sent as a bearer token.
+func WithBearerToken(token string) Option {
+	return func(c *Client) { c.token = token }
+}
+
+// WithRequestSigning signs every call with a partner's shared secret, see pkg/reqsign.
+func WithRequestSigning(keyID, secret string) Option {
+	return func(c *Client) { c.signKeyID, c.signSecret = keyID, secret }
+}
+
+// WithHTTPClient replaces http.DefaultClient, e.g. to set timeouts or an instrumented transport.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.http = hc }
+}
+
+// WithRetries sets how often a failed call is retried; 0 disables retries.
+func WithRetries(n int) Option {
+	return func(c *Client) { c.retries = max(n, 0) }
+}
+
+// WithBackoff sets the first and the longest wait between retries.
+func WithBackoff(min, max time.Duration) Option {
+	return func(c *Client) { c.minBackoff, c.maxBackoff = min, max }
+}
+
+// WithUserAgent names the calling application in the User-Agent header.
+func WithUserAgent(ua string) Option {
+	return func(c *Client) { c.userAgent = ua }
+}
+
+// New returns a client for the API at baseURL, e.g. "http://localhost:9999".
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		http:       http.DefaultClient,
+		userAgent:  "txn-processor-go",
+		retries:    defaultRetries,
+		minBackoff: defaultMinBackoff,
+		maxBackoff: defaultMaxBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// call is one logical API call; every attempt sends the same body and correlation ID.
+type call struct {
+	method string
+	path   string
+	query  url.Values
+	in     any
+	out    any
+	accept string
+	// idempotent calls are retried even when the server may have processed them.
+	idempotent bool
+	// upload is sent as the body instead of in. It can be read once, so the call is never retried.
+	upload      io.Reader
+	contentType string
+}
+
+func (c *Client) do(ctx context.Context, cl call) error {
+	res, err := c.send(ctx, cl)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if cl.out == nil {
+		_, _ = io.Copy(io.Discard, res.Body)
+		return nil
+	}
+	if raw, ok := cl.out.(*json.RawMessage); ok {
+		b, err := io.ReadAll(res.Body)
+		*raw = b
+		return err
+	}
+	return json.NewDecoder(res.Body).Decode(cl.out)
+}
+
+// send runs the attempts of cl and returns the first successful response, whose body the
+// caller must close.
+func (c *Client) send(ctx context.Context, cl call) (*http.Response, error) {
+	var body []byte
+	if cl.in != nil {
+		b, err := json.Marshal(cl.in)
+		if err != nil {
+			return nil, err
+		}
+		body = b
+	}
+	correlationID := uuid.NewString()
+
+	for attempt := 0; ; attempt++ {
+		res, err := c.attempt(ctx, cl, body, correlationID)
+		if err == nil && res.StatusCode < http.StatusBadRequest {
+			return res, nil
+		}
+		if err == nil {
+			err = decodeError(res)
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		wait, ok := c.retryable(err, cl.idempotent)
+		if !ok || attempt >= c.retries || cl.upload != nil {
+			return nil, err
+		}
+		timer := time.NewTimer(max(wait, c.backoff(attempt)))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *Client) attempt(ctx context.Context, cl call, body []byte, correlationID string) (*http.Response, error) {
+	u := c.baseURL + cl.path
+	if len(cl.query) > 0 {
+		u += "?" + cl.query.Encode()
+	}
+
+	var reader io.Reader
+	contentType := cl.contentType
+	switch {
+	case cl.upload != nil:
+		reader = cl.upload
+	case body != nil:
+		reader = bytes.NewReader(body)
+		contentType = "application/json"
+	}
+	req, err := http.NewRequestWithContext(ctx, cl.method, u, reader)
+	if err != nil {
+		return nil, err
+	}
+
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	accept := cl.accept
+	if accept == "" {
+		accept = "application/json"
+	}
+	req.Header.Set("Accept", accept)
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("X-Correlation-ID", correlationID)
+	for k, v := range tracing.Inject(ctx) {
+		req.Header.Set(k, v)
+	}
+
+	switch {
+	case c.signKeyID != "":
+		if err := reqsign.SignRequest(req, c.signKeyID, c.signSecret); err != nil {
+			return nil, err
+		}
+	case c.apiKey != "":
+		req.Header.Set("X-API-Key", c.apiKey)
+	case c.token != "":
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	return c.http.Do(req)
+}
+
+// retryable reports whether a failed attempt may be repeated and how long the server asked
+// to wait. Refusals that mean the request was not processed (rate limits, a full transfer
+// queue) are always retried; lost connections and gateway errors only for idempotent calls.
+func (c *Client) retryable(err error, idempotent bool) (time.Duration, bool) {
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		return 0, idempotent
+	}
+	switch apiErr.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return apiErr.RetryAfter, true
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return apiErr.RetryAfter, idempotent
+	default:
+		return 0, false
+	}
+}
+
+// backoff is capped exponential backoff with full jitter.
+func (c *Client) backoff(attempt int) time.Duration {
+	d := c.maxBackoff
+	if attempt < 32 {
+		d = min(c.minBackoff<<attempt, c.maxBackoff)
+	}
+	if d <= 0 {
+		return 0
+	}
+	return rand.N(d) + 1
+}
+
+func retryAfter(h http.Header) time.Duration {
+	if secs, err := strconv.Atoi(h.Get("Retry-After")); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	return 0
+}
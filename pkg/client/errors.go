@@ -0,0 +1,88 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+	"txn-processor/internal/core/model"
+)
+
+// Errors matched by *Error, one per kind of failure the API reports.
+var (
+	ErrValidation        = errors.New("validation failed")
+	ErrUnauthenticated   = errors.New("unauthenticated")
+	ErrForbidden         = errors.New("forbidden")
+	ErrNotFound          = errors.New("not found")
+	ErrConflict          = errors.New("conflict")
+	ErrTooLarge          = errors.New("request body too large")
+	ErrInsufficientFunds = errors.New("insufficient balance")
+	ErrRateLimited       = errors.New("rate limited")
+	ErrUnavailable       = errors.New("service unavailable")
+)
+
+// Error is a failed API call: the status, the server's message and, for requests that did not
+// match the API's schema, the offending fields.
+type Error struct {
+	StatusCode int
+	Message    string
+	Details    []model.ErrorDetail
+	// RetryAfter is how long the server asked the client to wait, if it said.
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	if len(e.Details) == 0 {
+		return fmt.Sprintf("txn-processor: %d %s", e.StatusCode, e.Message)
+	}
+	d := e.Details[0]
+	return fmt.Sprintf("txn-processor: %d %s: %s %s: %s", e.StatusCode, e.Message, d.In, d.Field, d.Reason)
+}
+
+// Is matches the sentinel for the status code, so callers can write errors.Is(err, ErrNotFound).
+func (e *Error) Is(target error) bool {
+	return target == sentinel(e.StatusCode)
+}
+
+func sentinel(status int) error {
+	switch status {
+	case http.StatusBadRequest:
+		return ErrValidation
+	case http.StatusUnauthorized:
+		return ErrUnauthenticated
+	case http.StatusForbidden:
+		return ErrForbidden
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusConflict:
+		return ErrConflict
+	case http.StatusRequestEntityTooLarge:
+		return ErrTooLarge
+	case http.StatusUnprocessableEntity:
+		return ErrInsufficientFunds
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	case http.StatusServiceUnavailable:
+		return ErrUnavailable
+	default:
+		return nil
+	}
+}
+
+// decodeError reads the structured error body of res and closes it. Bodies that are not JSON,
+// such as a proxy's error page, keep the status text as the message.
+func decodeError(res *http.Response) error {
+	defer res.Body.Close()
+
+	e := &Error{StatusCode: res.StatusCode, RetryAfter: retryAfter(res.Header)}
+	var body model.ErrorResponse
+	b, _ := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	if json.Unmarshal(b, &body) == nil && body.Error != "" {
+		e.Message, e.Details = body.Error, body.Details
+	} else {
+		e.Message = http.StatusText(res.StatusCode)
+	}
+	return e
+}
@@ -0,0 +1,103 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"txn-processor/internal/core/model"
+)
+
+// StreamBalance follows the account's balance over Server-Sent Events, calling fn with each
+// update after lastSeq (0 for only new ones) until ctx ends, fn fails or the server closes the
+// stream. It returns the sequence number of the last update delivered, to resume from.
+func (c *Client) StreamBalance(ctx context.Context, id int64, lastSeq int64, fn func(model.BalanceUpdate) error) (int64, error) {
+	cl := call{method: http.MethodGet, path: accountPath(id) + "/stream", accept: "text/event-stream", idempotent: true}
+	if lastSeq > 0 {
+		cl.query = map[string][]string{"last_event_id": {strconv.FormatInt(lastSeq, 10)}}
+	}
+	res, err := c.send(ctx, cl)
+	if err != nil {
+		return lastSeq, err
+	}
+	defer res.Body.Close()
+
+	scanner := bufio.NewScanner(res.Body)
+	var event, data string
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if event == "balance" && data != "" {
+				var u model.BalanceUpdate
+				if err := json.Unmarshal([]byte(data), &u); err != nil {
+					return lastSeq, fmt.Errorf("decode balance update: %w", err)
+				}
+				if err := fn(u); err != nil {
+					return lastSeq, err
+				}
+				lastSeq = u.Seq
+			}
+			event, data = "", ""
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data += strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		}
+	}
+	if err := scanner.Err(); err != nil && !errors.Is(err, context.Canceled) && ctx.Err() == nil {
+		return lastSeq, err
+	}
+	return lastSeq, ctx.Err()
+}
+
+// GraphQLError lists the errors of a GraphQL response. Data may still hold partial results.
+type GraphQLError struct {
+	Errors []struct {
+		Message    string         `json:"message"`
+		Path       []any          `json:"path"`
+		Extensions map[string]any `json:"extensions"`
+	}
+}
+
+func (e *GraphQLError) Error() string {
+	if len(e.Errors) == 0 {
+		return "graphql: unknown error"
+	}
+	return "graphql: " + e.Errors[0].Message
+}
+
+// GraphQL runs an operation and decodes its data into out. A response with errors returns a
+// *GraphQLError after decoding whatever data came back.
+func (c *Client) GraphQL(ctx context.Context, query string, vars map[string]any, out any) error {
+	var res struct {
+		Data   json.RawMessage `json:"data"`
+		Errors json.RawMessage `json:"errors"`
+	}
+	in := map[string]any{"query": query}
+	if vars != nil {
+		in["variables"] = vars
+	}
+	// Mutations are not idempotent, and queries fail the same way on every attempt.
+	if err := c.do(ctx, call{method: http.MethodPost, path: "/v1/graphql", in: in, out: &res}); err != nil {
+		return err
+	}
+
+	if out != nil && len(res.Data) > 0 && string(res.Data) != "null" {
+		if err := json.Unmarshal(res.Data, out); err != nil {
+			return err
+		}
+	}
+	if len(res.Errors) > 0 && string(res.Errors) != "null" {
+		var gqlErr GraphQLError
+		if err := json.Unmarshal(res.Errors, &gqlErr.Errors); err != nil {
+			return err
+		}
+		return &gqlErr
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package decimal
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// Column precision and scale used for every monetary column.
+const (
+	Precision = 20
+	Scale     = 4
+)
+
+// SQLType is the column definition shared by entities and migrations.
+var SQLType = fmt.Sprintf("DECIMAL(%d,%d)", Precision, Scale)
+
+// Money is a fixed-point amount that scans from and writes to DECIMAL columns.
+type Money struct {
+	decimal.Decimal
+}
+
+var Zero = Money{decimal.Zero}
+
+func ParseMoney(s string) (Money, error) {
+	d, err := decimal.NewFromString(s)
+	if err != nil {
+		return Money{}, err
+	}
+	if d.Exponent() < -Scale {
+		return Money{}, fmt.Errorf("amount %s exceeds %d decimal places", s, Scale)
+	}
+	if len(d.Truncate(0).Abs().String()) > Precision-Scale {
+		return Money{}, fmt.Errorf("amount %s exceeds %d integer digits", s, Precision-Scale)
+	}
+	return Money{d}, nil
+}
+
+func MustMoney(s string) Money {
+	m, err := ParseMoney(s)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func (m Money) Add(o Money) Money {
+	return Money{m.Decimal.Add(o.Decimal)}
+}
+
+func (m Money) Sub(o Money) Money {
+	return Money{m.Decimal.Sub(o.Decimal)}
+}
+
+func (m Money) Neg() Money {
+	return Money{m.Decimal.Neg()}
+}
+
+func (m Money) LessThan(o Money) bool {
+	return m.Decimal.LessThan(o.Decimal)
+}
+
+func (m Money) Equal(o Money) bool {
+	return m.Decimal.Equal(o.Decimal)
+}
+
+// Split divides m into n parts truncated to Scale; the remainder is added to the first part.
+func (m Money) Split(n int) []Money {
+	if n <= 0 {
+		return nil
+	}
+	parts := make([]Money, n)
+
+	each := m.Decimal.Div(decimal.NewFromInt(int64(n))).Truncate(Scale)
+	rest := m.Decimal.Sub(each.Mul(decimal.NewFromInt(int64(n))))
+	for i := range parts {
+		parts[i] = Money{each}
+	}
+	parts[0] = Money{each.Add(rest)}
+	return parts
+}
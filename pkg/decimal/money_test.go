@@ -0,0 +1,52 @@
+package decimal_test
+
+import (
+	"testing"
+
+	"txn-processor/pkg/decimal"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMoney(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want string
+		ok   bool
+	}{
+		{in: "0", want: "0", ok: true},
+		{in: "150", want: "150", ok: true},
+		{in: "0.0001", want: "0.0001", ok: true},
+		{in: "-12.5", want: "-12.5", ok: true},
+		{in: "9999999999999999.9999", want: "9999999999999999.9999", ok: true},
+		{in: "-9999999999999999", want: "-9999999999999999", ok: true},
+		{in: "0.00001"},
+		{in: "1.50000"},
+		{in: "10000000000000000"},
+		{in: "-10000000000000000.5"},
+		{in: ""},
+		{in: "abc"},
+		{in: "1,5"},
+	} {
+		m, err := decimal.ParseMoney(tc.in)
+		if !tc.ok {
+			require.Error(t, err, tc.in)
+			continue
+		}
+		require.NoError(t, err, tc.in)
+		require.Equal(t, tc.want, m.String(), tc.in)
+	}
+}
+
+func TestSplitKeepsTotal(t *testing.T) {
+	total := decimal.MustMoney("100.0001")
+	parts := total.Split(3)
+	require.Len(t, parts, 3)
+	require.Equal(t, "33.3335", parts[0].String())
+
+	sum := decimal.Zero
+	for _, p := range parts {
+		sum = sum.Add(p)
+	}
+	require.True(t, sum.Equal(total))
+}
@@ -0,0 +1,180 @@
+// Package receipt signs and verifies transfer receipts. A receipt is a detached JWS (RFC 7515
+// Appendix F) over the canonical transfer fields, signed with Ed25519. Clients import it to
+// prove that txn-processor completed a transfer, checking receipts against the published JWKS.
+package receipt
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// Algorithm is the JWS "alg" of every receipt.
+	Algorithm = "EdDSA"
+	// Type is the JWS "typ" of every receipt.
+	Type = "transfer-receipt+jws"
+)
+
+var (
+	ErrInvalidKey   = errors.New("receipt: invalid signing key")
+	ErrMalformed    = errors.New("receipt: malformed receipt")
+	ErrUnknownKey   = errors.New("receipt: unknown key id")
+	ErrBadSignature = errors.New("receipt: signature mismatch")
+)
+
+// Transfer holds the fields a receipt covers. Its JSON names match the transfer API, so a
+// TransferResponse body decodes straight into it.
+type Transfer struct {
+	TransactionID        int64     `json:"transaction_id"`
+	RequestID            string    `json:"request_id,omitempty"`
+	SourceAccountID      int64     `json:"source_account_id"`
+	DestinationAccountID int64     `json:"destination_account_id"`
+	Amount               string    `json:"amount"`
+	CreatedAt            time.Time `json:"created_at"`
+}
+
+// Payload is the canonical JSON a receipt signs: fields in declaration order, time in UTC.
+func Payload(t Transfer) []byte {
+	t.CreatedAt = t.CreatedAt.UTC()
+	b, _ := json.Marshal(t)
+	return b
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	Typ string `json:"typ"`
+}
+
+// Signer issues receipts with one Ed25519 key.
+type Signer struct {
+	keyID string
+	key   ed25519.PrivateKey
+}
+
+// NewSigner builds a signer from a base64 Ed25519 seed.
+func NewSigner(keyID, seed string) (*Signer, error) {
+	b, err := base64.StdEncoding.DecodeString(seed)
+	if err != nil || len(b) != ed25519.SeedSize || keyID == "" {
+		return nil, ErrInvalidKey
+	}
+	return &Signer{keyID: keyID, key: ed25519.NewKeyFromSeed(b)}, nil
+}
+
+func (s *Signer) KeyID() string { return s.keyID }
+
+// Key returns the signer's public key as a JWK.
+func (s *Signer) Key() Key {
+	return PublicKey(s.keyID, s.key.Public().(ed25519.PublicKey))
+}
+
+// Sign returns the detached compact JWS "<header>..<signature>" for t.
+func (s *Signer) Sign(t Transfer) string {
+	h, _ := json.Marshal(header{Alg: Algorithm, Kid: s.keyID, Typ: Type})
+	protected := b64(h)
+	sig := ed25519.Sign(s.key, signingInput(protected, Payload(t)))
+	return protected + ".." + b64(sig)
+}
+
+// Key is an Ed25519 public key in JWK form (RFC 8037).
+type Key struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+}
+
+// PublicKey returns pub as a JWK with the given key ID.
+func PublicKey(keyID string, pub ed25519.PublicKey) Key {
+	return Key{Kty: "OKP", Crv: "Ed25519", X: b64(pub), Kid: keyID, Use: "sig", Alg: Algorithm}
+}
+
+// ParsePublicKey decodes a base64 Ed25519 public key into a JWK.
+func ParsePublicKey(keyID, pub string) (Key, error) {
+	b, err := base64.StdEncoding.DecodeString(pub)
+	if err != nil || len(b) != ed25519.PublicKeySize || keyID == "" {
+		return Key{}, ErrInvalidKey
+	}
+	return PublicKey(keyID, b), nil
+}
+
+// KeySet is a JWKS document.
+type KeySet struct {
+	Keys []Key `json:"keys"`
+}
+
+// Verify checks that receipt is a valid signature of t by a key in the set.
+func (ks KeySet) Verify(receipt string, t Transfer) error {
+	protected, sig, ok := strings.Cut(receipt, "..")
+	if !ok || protected == "" || sig == "" {
+		return ErrMalformed
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(protected)
+	if err != nil {
+		return ErrMalformed
+	}
+	var h header
+	if err := json.Unmarshal(raw, &h); err != nil || h.Alg != Algorithm {
+		return ErrMalformed
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil {
+		return ErrMalformed
+	}
+
+	for _, k := range ks.Keys {
+		if k.Kid != h.Kid {
+			continue
+		}
+		pub, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || k.Kty != "OKP" || k.Crv != "Ed25519" || len(pub) != ed25519.PublicKeySize {
+			return ErrInvalidKey
+		}
+		if !ed25519.Verify(pub, signingInput(protected, Payload(t)), signature) {
+			return ErrBadSignature
+		}
+		return nil
+	}
+	return ErrUnknownKey
+}
+
+// FetchKeySet downloads a JWKS document, typically from /.well-known/jwks.json.
+func FetchKeySet(ctx context.Context, client *http.Client, url string) (KeySet, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return KeySet{}, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return KeySet{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return KeySet{}, fmt.Errorf("receipt: fetching key set: status %d", resp.StatusCode)
+	}
+
+	var ks KeySet
+	if err := json.NewDecoder(resp.Body).Decode(&ks); err != nil {
+		return KeySet{}, err
+	}
+	return ks, nil
+}
+
+func signingInput(protected string, payload []byte) []byte {
+	return []byte(protected + "." + b64(payload))
+}
+
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
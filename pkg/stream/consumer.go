@@ -0,0 +1,203 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Handler processes one message. A nil error acknowledges it; any other error leaves it
+// pending so it is redelivered after ConsumerConfig.ClaimIdle.
+type Handler func(ctx context.Context, msg Message) error
+
+// DeadLetterFunc receives a message that exceeded MaxDeliveries. The message is acknowledged
+// when it returns nil.
+type DeadLetterFunc func(ctx context.Context, msg Message) error
+
+type ConsumerConfig struct {
+	Streams  []string
+	Group    string
+	Consumer string
+
+	// Count is the number of entries read per call, Block how long a read waits for new ones.
+	Count int64
+	Block time.Duration
+
+	// ClaimIdle is how long an entry may stay unacknowledged before another consumer takes it over.
+	ClaimIdle time.Duration
+
+	// MaxDeliveries > 0 sends entries delivered more often than this to DeadLetter.
+	MaxDeliveries int64
+	DeadLetter    DeadLetterFunc
+}
+
+// Consumer reads streams as a member of a consumer group.
+type Consumer struct {
+	client redis.UniversalClient
+	cfg    ConsumerConfig
+}
+
+func NewConsumer(client redis.UniversalClient, cfg ConsumerConfig) *Consumer {
+	if cfg.Count <= 0 {
+		cfg.Count = 10
+	}
+	if cfg.Block <= 0 {
+		cfg.Block = 2 * time.Second
+	}
+	if cfg.ClaimIdle <= 0 {
+		cfg.ClaimIdle = 30 * time.Second
+	}
+	return &Consumer{client: client, cfg: cfg}
+}
+
+// Run creates the group if needed and handles messages until ctx is cancelled.
+func (c *Consumer) Run(ctx context.Context, h Handler) error {
+	for _, s := range c.cfg.Streams {
+		err := c.client.XGroupCreateMkStream(ctx, s, c.cfg.Group, "0").Err()
+		if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+			return err
+		}
+	}
+
+	lastClaim := time.Time{}
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil
+		}
+
+		if time.Since(lastClaim) >= c.cfg.ClaimIdle/2 {
+			if err := c.claim(ctx, h); err != nil && ctx.Err() == nil {
+				return err
+			}
+			lastClaim = time.Now()
+		}
+
+		if err := c.read(ctx, h); err != nil && ctx.Err() == nil {
+			return err
+		}
+	}
+}
+
+func (c *Consumer) read(ctx context.Context, h Handler) error {
+	args := make([]string, 0, len(c.cfg.Streams)*2)
+	args = append(args, c.cfg.Streams...)
+	for range c.cfg.Streams {
+		args = append(args, ">")
+	}
+
+	res, err := c.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    c.cfg.Group,
+		Consumer: c.cfg.Consumer,
+		Streams:  args,
+		Count:    c.cfg.Count,
+		Block:    c.cfg.Block,
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, s := range res {
+		for _, m := range s.Messages {
+			c.handle(ctx, h, decode(s.Stream, m.ID, m.Values))
+		}
+	}
+	return nil
+}
+
+// claim takes over entries other consumers left unacknowledged for longer than ClaimIdle.
+func (c *Consumer) claim(ctx context.Context, h Handler) error {
+	for _, s := range c.cfg.Streams {
+		start := "0-0"
+		for {
+			msgs, next, err := c.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+				Stream:   s,
+				Group:    c.cfg.Group,
+				Consumer: c.cfg.Consumer,
+				MinIdle:  c.cfg.ClaimIdle,
+				Start:    start,
+				Count:    c.cfg.Count,
+			}).Result()
+			if err != nil {
+				return err
+			}
+
+			for _, m := range msgs {
+				msg := decode(s, m.ID, m.Values)
+				msg.Deliveries = c.deliveries(ctx, s, m.ID)
+				c.handle(ctx, h, msg)
+			}
+
+			if next == "0-0" || next == "" {
+				break
+			}
+			start = next
+		}
+	}
+	return nil
+}
+
+func (c *Consumer) deliveries(ctx context.Context, stream, id string) int64 {
+	pending, err := c.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: stream,
+		Group:  c.cfg.Group,
+		Start:  id,
+		End:    id,
+		Count:  1,
+	}).Result()
+	if err != nil || len(pending) == 0 {
+		return 1
+	}
+	return pending[0].RetryCount
+}
+
+func (c *Consumer) handle(ctx context.Context, h Handler, msg Message) {
+	parent := otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(msg.Headers))
+	ctx, span := tracer.Start(parent, "stream.consume "+msg.Stream,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "redis"),
+			attribute.String("messaging.destination", msg.Stream),
+			attribute.String("messaging.message_id", msg.ID),
+			attribute.String("messaging.consumer_group", c.cfg.Group),
+			attribute.Int64("messaging.delivery_count", msg.Deliveries),
+		),
+	)
+	defer span.End()
+
+	if c.cfg.MaxDeliveries > 0 && msg.Deliveries > c.cfg.MaxDeliveries {
+		if c.cfg.DeadLetter != nil {
+			if err := c.cfg.DeadLetter(ctx, msg); err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, "dead letter failed")
+				return
+			}
+		}
+		c.ack(ctx, span, msg)
+		return
+	}
+
+	if err := h(ctx, msg); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return
+	}
+
+	c.ack(ctx, span, msg)
+}
+
+func (c *Consumer) ack(ctx context.Context, span trace.Span, msg Message) {
+	if err := c.client.XAck(ctx, msg.Stream, c.cfg.Group, msg.ID).Err(); err != nil {
+		span.RecordError(err)
+	}
+}
@@ -0,0 +1,42 @@
+package stream
+
+import (
+	"context"
+	"slices"
+	"strconv"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Fields added to dead-lettered entries next to the original values.
+const (
+	FieldDeadSourceStream = "dead.source_stream"
+	FieldDeadSourceID     = "dead.source_id"
+	FieldDeadDeliveries   = "dead.deliveries"
+	FieldDeadReason       = "dead.reason"
+)
+
+// DeadLetterTo returns a DeadLetterFunc that copies the entry, its headers and its origin to stream.
+// A reason in msg.Values[FieldDeadReason] is kept; otherwise the delivery limit is recorded.
+// The omit fields, such as credentials, are left out of the copy.
+func DeadLetterTo(client redis.UniversalClient, stream string, omit ...string) DeadLetterFunc {
+	return func(ctx context.Context, msg Message) error {
+		fields := make(map[string]interface{}, len(msg.Values)+len(msg.Headers)+4)
+		for k, v := range msg.Values {
+			if !slices.Contains(omit, k) {
+				fields[k] = v
+			}
+		}
+		for k, v := range msg.Headers {
+			fields[HeaderPrefix+k] = v
+		}
+		fields[FieldDeadSourceStream] = msg.Stream
+		fields[FieldDeadSourceID] = msg.ID
+		fields[FieldDeadDeliveries] = strconv.FormatInt(msg.Deliveries, 10)
+		if _, ok := fields[FieldDeadReason]; !ok {
+			fields[FieldDeadReason] = "max deliveries exceeded"
+		}
+
+		return client.XAdd(ctx, &redis.XAddArgs{Stream: stream, Values: fields}).Err()
+	}
+}
@@ -0,0 +1,58 @@
+package stream
+
+import (
+	"context"
+
+	"github.com/go-redis/redis/v8"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Producer appends messages to streams, trimming each stream to roughly MaxLen entries.
+type Producer struct {
+	client redis.UniversalClient
+	maxLen int64
+}
+
+func NewProducer(client redis.UniversalClient, maxLen int64) *Producer {
+	return &Producer{client: client, maxLen: maxLen}
+}
+
+// Publish adds values to stream under a producer span whose context travels in the message headers.
+func (p *Producer) Publish(ctx context.Context, stream string, values map[string]string) (string, error) {
+	ctx, span := tracer.Start(ctx, "stream.publish "+stream,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "redis"),
+			attribute.String("messaging.destination", stream),
+		),
+	)
+	defer span.End()
+
+	headers := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, headers)
+
+	fields := make(map[string]interface{}, len(values)+len(headers))
+	for k, v := range values {
+		fields[k] = v
+	}
+	for k, v := range headers {
+		fields[HeaderPrefix+k] = v
+	}
+
+	id, err := p.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: stream,
+		MaxLen: p.maxLen,
+		Approx: p.maxLen > 0,
+		Values: fields,
+	}).Result()
+	if err != nil {
+		span.RecordError(err)
+		return "", err
+	}
+
+	span.SetAttributes(attribute.String("messaging.message_id", id))
+	return id, nil
+}
@@ -0,0 +1,72 @@
+// Package stream is a small Redis Streams toolkit shared by txn-processor and its consumers.
+// Messages carry W3C trace context in fields prefixed with HeaderPrefix, so a consumer span
+// continues the trace of the request that produced the message.
+package stream
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+
+	"go.opentelemetry.io/otel"
+)
+
+// HeaderPrefix marks message fields that carry propagation headers rather than data.
+const HeaderPrefix = "h:"
+
+const instrumentation = "txn-processor/pkg/stream"
+
+var tracer = otel.Tracer(instrumentation)
+
+// Message is a decoded stream entry.
+type Message struct {
+	Stream  string
+	ID      string
+	Values  map[string]string
+	Headers map[string]string
+
+	// Deliveries is 1 on first delivery and grows each time the entry is reclaimed.
+	Deliveries int64
+}
+
+// Partition maps a key (for example an account ID) onto one of n streams named prefix:<p>.
+// All messages for the same key land on the same stream, which keeps them ordered.
+func Partition(prefix string, key int64, partitions int) string {
+	if partitions <= 1 {
+		return prefix
+	}
+	h := fnv.New32a()
+	h.Write([]byte(strconv.FormatInt(key, 10)))
+	return fmt.Sprintf("%s:%d", prefix, h.Sum32()%uint32(partitions))
+}
+
+// Partitions lists every stream Partition can return for the prefix.
+func Partitions(prefix string, partitions int) []string {
+	if partitions <= 1 {
+		return []string{prefix}
+	}
+	streams := make([]string, partitions)
+	for i := range streams {
+		streams[i] = fmt.Sprintf("%s:%d", prefix, i)
+	}
+	return streams
+}
+
+func decode(stream, id string, fields map[string]interface{}) Message {
+	msg := Message{
+		Stream:     stream,
+		ID:         id,
+		Values:     make(map[string]string, len(fields)),
+		Headers:    map[string]string{},
+		Deliveries: 1,
+	}
+	for k, v := range fields {
+		s := fmt.Sprint(v)
+		if len(k) > len(HeaderPrefix) && k[:len(HeaderPrefix)] == HeaderPrefix {
+			msg.Headers[k[len(HeaderPrefix):]] = s
+			continue
+		}
+		msg.Values[k] = s
+	}
+	return msg
+}
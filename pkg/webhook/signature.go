@@ -0,0 +1,82 @@
+// Package webhook signs and verifies webhook payloads. Receivers import it to check
+// that a request came from txn-processor and was not replayed.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers sent with every delivery.
+const (
+	HeaderSignature = "Webhook-Signature"
+	HeaderEventID   = "Webhook-Id"
+	HeaderEventType = "Webhook-Event"
+)
+
+var (
+	ErrMalformedSignature = errors.New("webhook: malformed signature header")
+	ErrInvalidSignature   = errors.New("webhook: signature mismatch")
+	ErrExpiredSignature   = errors.New("webhook: timestamp outside tolerance")
+)
+
+// Sign returns the Webhook-Signature value "t=<unix>,v1=<hex>", where v1 is
+// HMAC-SHA256 over "<unix>.<body>" keyed with secret.
+func Sign(secret string, ts time.Time, body []byte) string {
+	unix := strconv.FormatInt(ts.Unix(), 10)
+	return "t=" + unix + ",v1=" + mac(secret, unix, body)
+}
+
+// Verify checks header against body. A tolerance > 0 also rejects signatures older or newer
+// than tolerance, which stops replays of captured requests.
+func Verify(secret, header string, body []byte, tolerance time.Duration) error {
+	var unix string
+	var sigs []string
+	for _, part := range strings.Split(header, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return ErrMalformedSignature
+		}
+		switch k {
+		case "t":
+			unix = v
+		case "v1":
+			sigs = append(sigs, v)
+		}
+	}
+	if unix == "" || len(sigs) == 0 {
+		return ErrMalformedSignature
+	}
+
+	if tolerance > 0 {
+		sec, err := strconv.ParseInt(unix, 10, 64)
+		if err != nil {
+			return ErrMalformedSignature
+		}
+		if d := time.Since(time.Unix(sec, 0)); d > tolerance || d < -tolerance {
+			return ErrExpiredSignature
+		}
+	}
+
+	// Several v1 values let a receiver accept both secrets during a rotation.
+	want := mac(secret, unix, body)
+	for _, sig := range sigs {
+		if hmac.Equal([]byte(sig), []byte(want)) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+func mac(secret, unix string, body []byte) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(unix))
+	h.Write([]byte("."))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"os"
 	"os/signal"
@@ -9,9 +10,19 @@ import (
 	"time"
 	"txn-processor/config"
 	"txn-processor/internal/adapter/inbound/fiber/router"
+	grpcapi "txn-processor/internal/adapter/inbound/grpc"
+	"txn-processor/internal/adapter/inbound/queue"
 	"txn-processor/internal/adapter/outbound/gorm/dao"
+	"txn-processor/internal/adapter/outbound/logger"
+	"txn-processor/internal/adapter/outbound/redispubsub"
+	"txn-processor/internal/adapter/outbound/redisratelimit"
+	"txn-processor/internal/adapter/outbound/redisstream"
+	"txn-processor/internal/adapter/outbound/webhook"
 	"txn-processor/internal/core/service"
+	"txn-processor/internal/port"
 	"txn-processor/pkg/tracing"
+
+	"github.com/go-redis/redis/v8"
 )
 
 type Server interface {
@@ -20,9 +31,13 @@ type Server interface {
 }
 
 type App struct {
-	config *config.App
-	server Server
-	tracer tracing.Tracer
+	config   *config.App
+	server   Server
+	tracer   tracing.Tracer
+	services *service.Service
+	dao      *dao.Dao
+	commands *queue.TransferConsumer
+	grpc     *grpcapi.Server
 }
 
 func New() *App {
@@ -58,17 +73,24 @@ func New() *App {
 		slog.Error("Failed to build application", "error", err)
 		os.Exit(1)
 	}
+	app.services = services
 	app.server = router.New(services, tracer)
+	if app.config.GRPC.IsEnabled {
+		app.grpc = grpcapi.New(services, tracer)
+	}
 
 	app.seed(ctx)
 
 	return app
 }
 
+// Start runs the background workers and the servers until SIGINT or SIGTERM.
 func (a *App) Start() {
 
 	slog.Info("Starting service", "name", a.config.Name, "port", a.config.Port, "env", a.config.Env)
 
+	a.services.Start(context.Background())
+
 	go func() {
 		if err := a.server.Listen(":" + a.config.Port); err != nil {
 			slog.Error("Failed to start server", "error", err)
@@ -76,6 +98,31 @@ func (a *App) Start() {
 		}
 	}()
 
+	if a.grpc != nil {
+		slog.Info("Starting gRPC server", "port", a.config.GRPC.Port)
+		go func() {
+			if err := a.grpc.Listen(":" + a.config.GRPC.Port); err != nil {
+				slog.Error("Failed to start gRPC server", "error", err)
+			}
+		}()
+	}
+
+	if a.config.Stream.Commands.IsEnabled {
+		rdb, err := a.redisClient()
+		if err != nil {
+			slog.Error("Failed to start command consumer", "error", err)
+			os.Exit(1)
+		}
+		a.commands = queue.NewTransferConsumer(rdb, a.services, a.services, a.config.Stream.Commands)
+
+		slog.Info("Consuming transfer commands", "stream", a.config.Stream.Commands.Stream)
+		go func() {
+			if err := a.commands.Listen(); err != nil {
+				slog.Error("Command consumer stopped", "error", err)
+			}
+		}()
+	}
+
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
 
@@ -101,6 +148,28 @@ func (a *App) Shutdown(ctx context.Context) error {
 		return err
 	}
 
+	if a.grpc != nil {
+		if err := a.grpc.Shutdown(ctx); err != nil {
+			slog.ErrorContext(ctx, "Error shutting down gRPC server", "error", err)
+		}
+	}
+
+	if a.commands != nil {
+		if err := a.commands.Shutdown(); err != nil {
+			slog.ErrorContext(ctx, "Error stopping command consumer", "error", err)
+		}
+	}
+
+	if err := a.services.Shutdown(ctx); err != nil {
+		slog.ErrorContext(ctx, "Error stopping service workers", "error", err)
+	}
+
+	if a.dao != nil {
+		if err := a.dao.Shutdown(ctx); err != nil {
+			slog.ErrorContext(ctx, "Error stopping DAO workers", "error", err)
+		}
+	}
+
 	if conn, err := dao.GetConnections(); err == nil {
 		if err := conn.Close(ctx); err != nil {
 			slog.ErrorContext(ctx, "Connection close failed", "error", err)
@@ -125,13 +194,56 @@ func (a *App) build(ctx context.Context) (*service.Service, error) {
 		slog.ErrorContext(ctx, "error while creating dao", "err", err)
 		os.Exit(1)
 	}
+	a.dao = dao
+
+	publisher, err := a.publisher()
+	if err != nil {
+		return nil, err
+	}
+
+	rdb, err := a.redisClient()
+	if err != nil {
+		return nil, err
+	}
+
+	sender := webhook.NewSender(time.Duration(a.config.Webhook.TimeoutMs) * time.Millisecond)
+	broker := redispubsub.NewBroker(rdb, a.config.Realtime)
+	limiter := redisratelimit.NewLimiter(rdb)
+
+	return service.New(dao, publisher, sender, broker, limiter, a.tracer, a.config), nil
+}
+
+func (a *App) redisClient() (*redis.Client, error) {
+	conn, err := dao.GetConnections()
+	if err != nil {
+		return nil, err
+	}
+	return conn.Redis(), nil
+}
 
-	return service.New(dao, a.tracer), nil
+func (a *App) publisher() (port.EventPublisher, error) {
+	switch a.config.Outbox.Publisher {
+	case "", "log":
+		return logger.NewPublisher(), nil
+	case "redis":
+		rdb, err := a.redisClient()
+		if err != nil {
+			return nil, err
+		}
+		return redisstream.NewPublisher(rdb, a.config.Stream), nil
+	default:
+		return nil, fmt.Errorf("unknown outbox publisher %q", a.config.Outbox.Publisher)
+	}
 }
 
 func (a *App) seed(ctx context.Context) {
 
 	if a.config.DB.IsAutoMigrate {
+		if err := dao.MigrateDecimal(ctx); err != nil {
+			slog.ErrorContext(ctx, "error while migrating money columns", "err", err)
+			os.Exit(1)
+		}
+
 		err := dao.AutoMigrate(ctx)
 		if err != nil {
 			slog.ErrorContext(ctx, "error while running migrator", "err", err)
@@ -0,0 +1,159 @@
+package benchmark_test
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"txn-processor/config"
+	"txn-processor/internal/adapter/outbound/gorm/dao"
+	"txn-processor/internal/core/model"
+	"txn-processor/pkg/tracing"
+
+	"github.com/testcontainers/testcontainers-go/modules/mariadb"
+	"github.com/testcontainers/testcontainers-go/modules/redis"
+)
+
+// concurrency mirrors `task load`: hey -c 10000 against a single source account.
+const concurrency = 10000
+
+var (
+	setupOnce sync.Once
+	setupErr  error
+	dbConf    config.DB
+	conn      *dao.Connections
+	outbound  *dao.Dao
+	nextID    atomic.Int64
+)
+
+func setup(b *testing.B) {
+	b.Helper()
+
+	setupOnce.Do(func() {
+		ctx := context.Background()
+
+		mariaC, err := mariadb.Run(ctx,
+			"mariadb:11",
+			mariadb.WithDatabase("benchdb"),
+			mariadb.WithUsername("user"),
+			mariadb.WithPassword("password"),
+		)
+		if err != nil {
+			setupErr = err
+			return
+		}
+
+		redisC, err := redis.Run(ctx, "redis:7-alpine")
+		if err != nil {
+			setupErr = err
+			return
+		}
+
+		mariaHost, _ := mariaC.Host(ctx)
+		mariaPort, _ := mariaC.MappedPort(ctx, "3306")
+		redisHost, _ := redisC.Host(ctx)
+		redisPort, _ := redisC.MappedPort(ctx, "6379")
+
+		dbConf = config.DB{
+			Host:     mariaHost,
+			Port:     mariaPort.Port(),
+			User:     "user",
+			Password: "password",
+			DBName:   "benchdb",
+			LogLevel: 1,
+		}
+		dbConf.Tuning.MaxOpenConns = 50
+		dbConf.Tuning.MaxIdleConns = 25
+		dbConf.Tuning.ConnMaxLifetimeMin = 5
+
+		cacheConf := config.Cache{
+			Host: redisHost,
+			Port: redisPort.Port(),
+		}
+		cacheConf.Tuning.PoolSize = 50
+
+		outbound, setupErr = dao.New(ctx, dbConf, cacheConf, tracing.NewBlankTracer())
+		if setupErr != nil {
+			return
+		}
+		if setupErr = dao.AutoMigrate(ctx); setupErr != nil {
+			return
+		}
+		conn, setupErr = dao.GetConnections()
+	})
+
+	if setupErr != nil {
+		b.Fatalf("setup failed: %v", setupErr)
+	}
+}
+
+func BenchmarkTransferStrategies(b *testing.B) {
+	setup(b)
+
+	strategies := []string{
+		dao.StrategyPessimistic,
+		dao.StrategyOptimistic,
+		dao.StrategyConditional,
+	}
+
+	for _, strategy := range strategies {
+		b.Run(strategy, func(b *testing.B) {
+			benchmarkStrategy(b, strategy)
+		})
+	}
+}
+
+func benchmarkStrategy(b *testing.B, strategy string) {
+	ctx := context.Background()
+
+	conf := dbConf
+	conf.LockStrategy = strategy
+	conf.OptimisticRetries = 50
+
+	transfers, err := dao.NewTransferDAO(conn, conf)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	// Fresh accounts per run so earlier runs don't skew balances.
+	source := 1000 + nextID.Add(2)
+	dest := source + 1
+	if err := outbound.CreateAccount(ctx, model.AccountCreateRequest{AccountID: source, InitialBalance: "1000000000"}); err != nil {
+		b.Fatal(err)
+	}
+	if err := outbound.CreateAccount(ctx, model.AccountCreateRequest{AccountID: dest, InitialBalance: "0"}); err != nil {
+		b.Fatal(err)
+	}
+
+	req := model.TransferRequest{
+		SourceAccountID:      source,
+		DestinationAccountID: dest,
+		Amount:               "1",
+	}
+
+	var mu sync.Mutex
+	var failed int
+
+	b.SetParallelism(max(1, concurrency/runtime.GOMAXPROCS(0)))
+	b.ResetTimer()
+	start := time.Now()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := transfers.RunTransferTx(ctx, req); err != nil {
+				mu.Lock()
+				failed++
+				mu.Unlock()
+			}
+		}
+	})
+
+	elapsed := time.Since(start)
+	b.StopTimer()
+
+	b.ReportMetric(float64(b.N-failed)/elapsed.Seconds(), "transfers/s")
+	b.ReportMetric(float64(failed), "failed")
+}
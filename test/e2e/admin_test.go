@@ -0,0 +1,149 @@
+package e2e_test
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"txn-processor/internal/core/model"
+	"txn-processor/pkg/client"
+)
+
+// lastEventID pages to the end of the event log.
+func (s *E2eSuite) lastEventID(c *client.Client) int64 {
+	var last int64
+	for {
+		page, err := c.ListEvents(s.ctx, last, 1000)
+		s.Require().NoError(err)
+		if n := len(page.Events); n > 0 {
+			last = page.Events[n-1].ID
+		}
+		if page.NextAfterID == 0 {
+			return last
+		}
+	}
+}
+
+func (s *E2eSuite) TestAdminOperations() {
+	c := s.sdkClient(s.inbound)
+	ctx := s.ctx
+
+	since := s.lastEventID(c)
+
+	for _, id := range []int64{9911, 9912} {
+		_, err := c.CreateAccount(ctx, model.AccountCreateRequest{AccountID: id, InitialBalance: "100"})
+		s.Require().NoError(err)
+	}
+
+	list, err := c.ListAccounts(ctx, 9910, 2)
+	s.Require().NoError(err)
+	s.Require().Len(list.Accounts, 2)
+	s.Require().Equal(int64(9911), list.Accounts[0].AccountID)
+	s.Require().Equal(int64(9912), list.NextAfterID)
+
+	// A frozen account can neither send nor receive, synchronously or through the queue.
+	frozen, err := c.FreezeAccount(ctx, 9911)
+	s.Require().NoError(err)
+	s.Require().True(frozen.Frozen)
+
+	acc, err := c.GetAccount(ctx, 9911)
+	s.Require().NoError(err)
+	s.Require().True(acc.Frozen)
+
+	_, err = c.CreateTransfer(ctx, model.TransferRequest{SourceAccountID: 9911, DestinationAccountID: 9912, Amount: "10"})
+	s.Require().ErrorIs(err, client.ErrConflict)
+	_, err = c.CreateTransfer(ctx, model.TransferRequest{SourceAccountID: 9912, DestinationAccountID: 9911, Amount: "10"})
+	s.Require().ErrorIs(err, client.ErrConflict)
+
+	queued, err := c.SubmitTransfer(ctx, model.TransferRequest{SourceAccountID: 9912, DestinationAccountID: 9911, Amount: "10"})
+	s.Require().NoError(err)
+	s.Require().Eventually(func() bool {
+		st, err := c.GetTransfer(ctx, queued.TransferID)
+		return err == nil && st.Status == model.TransferFailed && st.FailureReason == "account frozen"
+	}, 10*time.Second, 50*time.Millisecond)
+
+	_, err = c.FreezeAccount(ctx, 999999)
+	s.Require().ErrorIs(err, client.ErrNotFound)
+
+	_, err = c.UnfreezeAccount(ctx, 9911)
+	s.Require().NoError(err)
+
+	tr, err := c.CreateTransfer(ctx, model.TransferRequest{SourceAccountID: 9911, DestinationAccountID: 9912, Amount: "30"})
+	s.Require().NoError(err)
+
+	// Reversing moves the amount back once; asking again returns the same reversal.
+	rev, err := c.ReverseTransfer(ctx, tr.TransactionID)
+	s.Require().NoError(err)
+	s.Require().Equal(int64(9912), rev.SourceAccountID)
+	s.Require().Equal(int64(9911), rev.DestinationAccountID)
+	s.Require().Equal("reversal:"+strconv.FormatInt(tr.TransactionID, 10), rev.RequestID)
+
+	again, err := c.ReverseTransfer(ctx, tr.TransactionID)
+	s.Require().NoError(err)
+	s.Require().Equal(rev.TransactionID, again.TransactionID)
+
+	// Reversal and import request IDs cannot be claimed by clients ahead of the service.
+	for _, id := range []string{"reversal:" + strconv.FormatInt(tr.TransactionID+1, 10), "import:e2e:1"} {
+		_, err = c.CreateTransfer(ctx, model.TransferRequest{SourceAccountID: 9911, DestinationAccountID: 9912, Amount: "0.0001", RequestID: id})
+		s.Require().ErrorIs(err, client.ErrValidation, id)
+	}
+
+	for _, id := range []int64{9911, 9912} {
+		acc, err := c.GetAccount(ctx, id)
+		s.Require().NoError(err)
+		s.Require().Equal("100", acc.Balance)
+	}
+
+	_, err = c.ReverseTransfer(ctx, 1<<40)
+	s.Require().ErrorIs(err, client.ErrNotFound)
+
+	report, err := c.Reconcile(ctx)
+	s.Require().NoError(err)
+	s.Require().Empty(report.Mismatches)
+	s.Require().Equal(report.Issued, report.Total)
+	s.Require().Positive(report.Accounts)
+
+	// The tail shows both accounts being created, then the transfer and its reversal once for
+	// each account, in order.
+	events, err := c.ListEvents(ctx, since, 1000)
+	s.Require().NoError(err)
+	var seen []string
+	for _, e := range events.Events {
+		if e.AccountID == 9911 || e.AccountID == 9912 {
+			seen = append(seen, e.Type+" "+strconv.FormatInt(e.AccountID, 10))
+		}
+	}
+	s.Require().Equal([]string{
+		model.EventAccountCreated + " 9911",
+		model.EventAccountCreated + " 9912",
+		model.EventTransferCompleted + " 9911",
+		model.EventTransferCompleted + " 9912",
+		model.EventTransferCompleted + " 9912",
+		model.EventTransferCompleted + " 9911",
+	}, seen)
+}
+
+func (s *E2eSuite) TestAdminOperationsNeedAdmin() {
+	_, svc, _ := s.authApp()
+	key, err := svc.IssueAPIKey(s.asAdmin(), model.APIKeyIssueRequest{Name: "e2e-txnctl", Subject: "ops-reader"})
+	s.Require().NoError(err)
+
+	c := s.sdkClient(svc, client.WithAPIKey(key.Key))
+
+	ctx, cancel := context.WithTimeout(s.ctx, 10*time.Second)
+	defer cancel()
+
+	_, err = c.ListAccounts(ctx, 0, 10)
+	s.Require().ErrorIs(err, client.ErrForbidden)
+	_, err = c.FreezeAccount(ctx, 9911)
+	s.Require().ErrorIs(err, client.ErrForbidden)
+	_, err = c.ReverseTransfer(ctx, 1)
+	s.Require().ErrorIs(err, client.ErrForbidden)
+	_, err = c.Reconcile(ctx)
+	s.Require().ErrorIs(err, client.ErrForbidden)
+	_, err = c.ListEvents(ctx, 0, 10)
+	s.Require().ErrorIs(err, client.ErrForbidden)
+
+	_, err = s.sdkClient(svc).ListAccounts(ctx, 0, 10)
+	s.Require().ErrorIs(err, client.ErrUnauthenticated)
+}
@@ -0,0 +1,130 @@
+package e2e_test
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"txn-processor/config"
+	"txn-processor/internal/adapter/outbound/gorm/dao"
+	"txn-processor/internal/adapter/outbound/logger"
+	"txn-processor/internal/adapter/outbound/redispubsub"
+	"txn-processor/internal/adapter/outbound/redisratelimit"
+	"txn-processor/internal/adapter/outbound/webhook"
+	"txn-processor/internal/core/model"
+	"txn-processor/internal/core/service"
+	"txn-processor/pkg/tracing"
+)
+
+func (s *E2eSuite) TestAsyncTransferSettles() {
+	for _, acc := range []model.AccountCreateRequest{{AccountID: 9951, InitialBalance: "100"}, {AccountID: 9952, InitialBalance: "0"}} {
+		s.Require().Equal(http.StatusCreated, s.doJSON("POST", "/v1/accounts", acc, nil))
+	}
+
+	settled := func(id string) model.TransferStatusResponse {
+		var st model.TransferStatusResponse
+		s.Require().Eventually(func() bool {
+			return s.doJSON("GET", "/v1/transfers/"+id, nil, &st) == http.StatusOK && st.Status != model.TransferPending
+		}, 10*time.Second, 50*time.Millisecond)
+		return st
+	}
+
+	for _, tc := range []struct {
+		req    model.TransferRequest
+		status string
+		reason string
+	}{
+		{req: model.TransferRequest{SourceAccountID: 9951, DestinationAccountID: 9952, Amount: "40"}, status: model.TransferCompleted},
+		{req: model.TransferRequest{SourceAccountID: 9951, DestinationAccountID: 9952, Amount: "61"}, status: model.TransferFailed, reason: "insufficient balance"},
+		{req: model.TransferRequest{SourceAccountID: 9951, DestinationAccountID: 999999, Amount: "1"}, status: model.TransferFailed, reason: "not found"},
+	} {
+		var queued model.TransferStatusResponse
+		s.Require().Equal(http.StatusAccepted, s.doJSON("POST", "/v1/transfers?mode=async", tc.req, &queued))
+		s.Require().Equal(model.TransferPending, queued.Status)
+
+		st := settled(queued.TransferID)
+		s.Require().Equal(tc.status, st.Status, tc.req.Amount)
+		s.Require().Equal(tc.reason, st.FailureReason, tc.req.Amount)
+	}
+
+	var acc model.AccountGetResponse
+	s.Require().Equal(http.StatusOK, s.doJSON("GET", "/v1/accounts/9951", nil, &acc))
+	s.Require().Equal("60", acc.Balance)
+}
+
+func (s *E2eSuite) TestAsyncTransferRecovery() {
+	for _, acc := range []model.AccountCreateRequest{{AccountID: 9953, InitialBalance: "10"}, {AccountID: 9954, InitialBalance: "0"}} {
+		s.Require().Equal(http.StatusCreated, s.doJSON("POST", "/v1/accounts", acc, nil))
+	}
+
+	// Transfers accepted by a run that stopped before its workers got to them; more of them
+	// than one listing returns.
+	ids := make([]string, 0, 5)
+	for i := range 5 {
+		req := model.TransferRequest{
+			RequestID:            fmt.Sprintf("e2e-recover-%d", i),
+			SourceAccountID:      9953,
+			DestinationAccountID: 9954,
+			Amount:               "3",
+		}
+		s.Require().NoError(s.outbound.CreatePendingTransfer(s.asAdmin(), req))
+		ids = append(ids, req.RequestID)
+	}
+
+	conn, err := dao.GetConnections()
+	s.Require().NoError(err)
+	broker := redispubsub.NewBroker(conn.Redis(), config.Realtime{History: 100, BufferSize: 16})
+	cfg := &config.App{Async: config.Async{Workers: 1, QueueSize: 2}}
+	svc := service.New(s.outbound, logger.NewPublisher(), webhook.NewSender(time.Second), broker, redisratelimit.NewLimiter(conn.Redis()), tracing.NewBlankTracer(), cfg)
+	svc.Start(s.ctx)
+	defer func() { _ = svc.Shutdown(s.ctx) }()
+
+	statuses := map[string]int{}
+	s.Require().Eventually(func() bool {
+		clear(statuses)
+		for _, id := range ids {
+			st, err := svc.GetTransfer(s.asAdmin(), id)
+			if err != nil || st.Status == model.TransferPending {
+				return false
+			}
+			statuses[st.Status]++
+		}
+		return true
+	}, 10*time.Second, 50*time.Millisecond)
+	s.Require().Equal(map[string]int{model.TransferCompleted: 3, model.TransferFailed: 2}, statuses)
+
+	acc, err := svc.GetAccount(s.asAdmin(), 9953)
+	s.Require().NoError(err)
+	s.Require().Equal("1", acc.Balance)
+}
+
+func (s *E2eSuite) TestAsyncWorkersStartWithService() {
+	for _, acc := range []model.AccountCreateRequest{{AccountID: 9955, InitialBalance: "10"}, {AccountID: 9956, InitialBalance: "0"}} {
+		s.Require().Equal(http.StatusCreated, s.doJSON("POST", "/v1/accounts", acc, nil))
+	}
+
+	conn, err := dao.GetConnections()
+	s.Require().NoError(err)
+	broker := redispubsub.NewBroker(conn.Redis(), config.Realtime{History: 100, BufferSize: 16})
+	cfg := &config.App{Async: config.Async{Workers: 1, QueueSize: 2}}
+	svc := service.New(s.outbound, logger.NewPublisher(), webhook.NewSender(time.Second), broker, redisratelimit.NewLimiter(conn.Redis()), tracing.NewBlankTracer(), cfg)
+	defer func() { _ = svc.Shutdown(s.ctx) }()
+
+	// A service that is built but not started, like the one txnctl runs, processes nothing.
+	queued, err := svc.SubmitTransfer(s.asAdmin(), model.TransferRequest{RequestID: "e2e-unstarted", SourceAccountID: 9955, DestinationAccountID: 9956, Amount: "4"})
+	s.Require().NoError(err)
+	time.Sleep(200 * time.Millisecond)
+	st, err := svc.GetTransfer(s.asAdmin(), queued.TransferID)
+	s.Require().NoError(err)
+	s.Require().Equal(model.TransferPending, st.Status)
+
+	svc.Start(s.ctx)
+	s.Require().Eventually(func() bool {
+		st, err := svc.GetTransfer(s.asAdmin(), queued.TransferID)
+		return err == nil && st.Status == model.TransferCompleted
+	}, 10*time.Second, 50*time.Millisecond)
+
+	acc, err := svc.GetAccount(s.asAdmin(), 9955)
+	s.Require().NoError(err)
+	s.Require().Equal("6", acc.Balance)
+}
@@ -0,0 +1,60 @@
+package e2e_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"txn-processor/internal/adapter/inbound/fiber/middleware"
+	"txn-processor/internal/core/model"
+	"txn-processor/pkg/audit"
+)
+
+func (s *E2eSuite) TestAuditLog() {
+	body, err := json.Marshal(model.AccountCreateRequest{AccountID: 8001, InitialBalance: "40"})
+	s.Require().NoError(err)
+
+	req := httptest.NewRequest("POST", "/v1/accounts", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(middleware.CorrelationIDKey, "audit-corr-1")
+	res, err := s.app.Test(req, -1)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusCreated, res.StatusCode)
+
+	s.Require().Equal(http.StatusCreated, s.doJSON("POST", "/v1/accounts", model.AccountCreateRequest{AccountID: 8002, InitialBalance: "0"}, nil))
+
+	var tr model.TransferResponse
+	s.Require().Equal(http.StatusCreated, s.doJSON("POST", "/v1/transfers", model.TransferRequest{
+		SourceAccountID:      8001,
+		DestinationAccountID: 8002,
+		Amount:               "15",
+	}, &tr))
+
+	var page model.AuditListResponse
+	s.Require().Equal(http.StatusOK, s.doJSON("GET", "/v1/admin/audit?resource_type=account&resource_id=8001", nil, &page))
+	s.Require().Len(page.Records, 1)
+	created := page.Records[0]
+	s.Require().Equal(model.AuditAccountCreate, created.Action)
+	s.Require().Equal(audit.Anonymous, created.Actor)
+	s.Require().Equal("audit-corr-1", created.CorrelationID)
+	s.Require().NotEmpty(created.SourceIP)
+	s.Require().Empty(created.Before)
+
+	s.Require().Equal(http.StatusOK, s.doJSON("GET", "/v1/admin/audit?action=transfer.create&limit=1", nil, &page))
+	s.Require().Len(page.Records, 1)
+	s.Require().NotZero(page.NextBeforeID)
+
+	var before, after struct {
+		Source struct {
+			Balance string `json:"balance"`
+			Version int64  `json:"version"`
+		} `json:"source"`
+	}
+	s.Require().NoError(json.Unmarshal(page.Records[0].Before, &before))
+	s.Require().NoError(json.Unmarshal(page.Records[0].After, &after))
+	s.Require().Equal("40", before.Source.Balance)
+	s.Require().Equal("25", after.Source.Balance)
+	s.Require().Equal(before.Source.Version+1, after.Source.Version)
+
+	s.Require().Equal(http.StatusBadRequest, s.doJSON("GET", "/v1/admin/audit?limit=10000", nil, nil))
+}
@@ -0,0 +1,270 @@
+package e2e_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"txn-processor/config"
+	"txn-processor/internal/adapter/inbound/fiber/router"
+	"txn-processor/internal/adapter/outbound/gorm/dao"
+	"txn-processor/internal/adapter/outbound/logger"
+	"txn-processor/internal/adapter/outbound/redispubsub"
+	"txn-processor/internal/adapter/outbound/redisratelimit"
+	"txn-processor/internal/adapter/outbound/webhook"
+	"txn-processor/internal/core/model"
+	"txn-processor/internal/core/service"
+	"txn-processor/pkg/auth"
+	"txn-processor/pkg/reqsign"
+	"txn-processor/pkg/tracing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const partnerSecret = "e2e-partner-secret-0123456789"
+
+// authApp builds a second app over the same database with authentication enabled and a JWKS
+// holding one Ed25519 key, returned for minting tokens.
+func (s *E2eSuite) authApp() (*fiber.App, *service.Service, ed25519.PrivateKey) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	s.Require().NoError(err)
+
+	jwks, err := json.Marshal(map[string]any{"keys": []map[string]string{{
+		"kty": "OKP", "crv": "Ed25519", "kid": "idp-1", "x": base64.RawURLEncoding.EncodeToString(pub),
+	}}})
+	s.Require().NoError(err)
+	path := filepath.Join(s.T().TempDir(), "jwks.json")
+	s.Require().NoError(os.WriteFile(path, jwks, 0o600))
+
+	conn, err := dao.GetConnections()
+	s.Require().NoError(err)
+	broker := redispubsub.NewBroker(conn.Redis(), config.Realtime{History: 100, BufferSize: 16})
+
+	cfg := &config.App{
+		Async: config.Async{Workers: 1, QueueSize: 10},
+		Auth: config.Auth{
+			IsEnabled:         true,
+			JWKSFile:          path,
+			Issuer:            "e2e-idp",
+			Audience:          "txn-processor",
+			SigningKeys:       map[string]string{"partner-x": partnerSecret},
+			SigningMaxSkewSec: 60,
+		},
+	}
+	svc := service.New(s.outbound, logger.NewPublisher(), webhook.NewSender(time.Second), broker, redisratelimit.NewLimiter(conn.Redis()), tracing.NewBlankTracer(), cfg)
+	return router.New(svc, tracing.NewBlankTracer()), svc, priv
+}
+
+func (s *E2eSuite) call(app *fiber.App, method, path string, headers map[string]string, in any, out any) int {
+	var body bytes.Buffer
+	if in != nil {
+		s.Require().NoError(json.NewEncoder(&body).Encode(in))
+	}
+	req := httptest.NewRequest(method, path, &body)
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	res, err := app.Test(req, -1)
+	s.Require().NoError(err)
+	if out != nil {
+		s.Require().NoError(json.NewDecoder(res.Body).Decode(out))
+	}
+	return res.StatusCode
+}
+
+// asAdmin is the context of an operator issuing keys outside the API.
+func (s *E2eSuite) asAdmin() context.Context {
+	return auth.WithPrincipal(s.ctx, auth.Principal{Subject: "e2e-admin", Method: auth.MethodSystem, Scopes: []string{auth.ScopeAdmin}})
+}
+
+func signToken(key ed25519.PrivateKey, claims map[string]any) string {
+	enc := func(v any) string {
+		b, _ := json.Marshal(v)
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+	input := enc(map[string]string{"alg": "EdDSA", "kid": "idp-1", "typ": "JWT"}) + "." + enc(claims)
+	return input + "." + base64.RawURLEncoding.EncodeToString(ed25519.Sign(key, []byte(input)))
+}
+
+func (s *E2eSuite) TestAuthentication() {
+	app, svc, idpKey := s.authApp()
+	defer func() { _ = svc.Shutdown(s.ctx) }()
+
+	// Health and the JWKS stay public; everything else needs credentials.
+	s.Require().Equal(http.StatusOK, s.call(app, "GET", "/v1/health", nil, nil, nil))
+	s.Require().Equal(http.StatusOK, s.call(app, "GET", "/.well-known/jwks.json", nil, nil, nil))
+	s.Require().Equal(http.StatusUnauthorized, s.call(app, "GET", "/v1/accounts/9201", nil, nil, nil))
+	s.Require().Equal(http.StatusUnauthorized, s.call(app, "GET", "/v1/accounts/9201", map[string]string{"X-API-Key": "txn_000000000000_nope"}, nil, nil))
+
+	// Issuing keys is an admin operation.
+	_, err := svc.IssueAPIKey(s.ctx, model.APIKeyIssueRequest{Name: "partner-a"})
+	s.Require().ErrorIs(err, service.ErrUnauthenticated)
+
+	issued, err := svc.IssueAPIKey(s.asAdmin(), model.APIKeyIssueRequest{Name: "partner-a"})
+	s.Require().NoError(err)
+	s.Require().Equal("partner-a", issued.Subject)
+	s.Require().Equal(auth.DefaultScopes, issued.Scopes)
+	withKey := map[string]string{"X-API-Key": issued.Key}
+
+	admin, err := svc.IssueAPIKey(s.asAdmin(), model.APIKeyIssueRequest{Name: "ops", Scopes: []string{auth.ScopeAdmin}})
+	s.Require().NoError(err)
+	withAdmin := map[string]string{"X-API-Key": admin.Key}
+
+	s.Require().Equal(http.StatusCreated, s.call(app, "POST", "/v1/accounts", withKey, model.AccountCreateRequest{AccountID: 9201, InitialBalance: "10"}, nil))
+	s.Require().Equal(http.StatusOK, s.call(app, "GET", "/v1/accounts/9201", map[string]string{"Authorization": "Bearer " + issued.Key}, nil, nil))
+
+	// The principal becomes the actor of the audit record.
+	var audit model.AuditListResponse
+	s.Require().Equal(http.StatusOK, s.call(app, "GET", "/v1/admin/audit?action=account.create&resource_id=9201", withAdmin, nil, &audit))
+	s.Require().Len(audit.Records, 1)
+	s.Require().Equal("partner-a", audit.Records[0].Actor)
+
+	now := time.Now().Unix()
+	valid := signToken(idpKey, map[string]any{"sub": "partner-a", "iss": "e2e-idp", "aud": "txn-processor", "exp": now + 60, "scope": "accounts:read"})
+	s.Require().Equal(http.StatusOK, s.call(app, "GET", "/v1/accounts/9201", map[string]string{"Authorization": "Bearer " + valid}, nil, nil))
+
+	expired := signToken(idpKey, map[string]any{"sub": "svc-b", "iss": "e2e-idp", "aud": "txn-processor", "exp": now - 3600})
+	s.Require().Equal(http.StatusUnauthorized, s.call(app, "GET", "/v1/accounts/9201", map[string]string{"Authorization": "Bearer " + expired}, nil, nil))
+
+	otherAudience := signToken(idpKey, map[string]any{"sub": "svc-b", "iss": "e2e-idp", "aud": "someone-else", "exp": now + 60})
+	s.Require().Equal(http.StatusUnauthorized, s.call(app, "GET", "/v1/accounts/9201", map[string]string{"Authorization": "Bearer " + otherAudience}, nil, nil))
+
+	// A revoked key stops working at once, cache included.
+	s.Require().NoError(svc.RevokeAPIKey(s.asAdmin(), issued.ID))
+	s.Require().Equal(http.StatusUnauthorized, s.call(app, "GET", "/v1/accounts/9201", withKey, nil, nil))
+	s.Require().ErrorIs(svc.RevokeAPIKey(s.asAdmin(), "000000000000"), service.ErrNotFound)
+}
+
+func (s *E2eSuite) TestAuthorization() {
+	app, svc, _ := s.authApp()
+	defer func() { _ = svc.Shutdown(s.ctx) }()
+
+	key := func(name string, scopes ...string) map[string]string {
+		issued, err := svc.IssueAPIKey(s.asAdmin(), model.APIKeyIssueRequest{Name: name, Scopes: scopes})
+		s.Require().NoError(err)
+		return map[string]string{"X-API-Key": issued.Key}
+	}
+	alice, bob, carol, admin := key("alice"), key("bob"), key("carol", auth.ScopeAccountsRead), key("ops", auth.ScopeAdmin)
+
+	_, err := svc.IssueAPIKey(s.asAdmin(), model.APIKeyIssueRequest{Name: "dave", Scopes: []string{"everything"}})
+	s.Require().ErrorIs(err, service.ErrValidation)
+
+	// Accounts belong to their creator; only admins may create them for someone else.
+	var acc model.AccountGetResponse
+	s.Require().Equal(http.StatusCreated, s.call(app, "POST", "/v1/accounts", alice, model.AccountCreateRequest{AccountID: 9301, InitialBalance: "100"}, nil))
+	s.Require().Equal(http.StatusCreated, s.call(app, "POST", "/v1/accounts", bob, model.AccountCreateRequest{AccountID: 9302, InitialBalance: "0"}, nil))
+	s.Require().Equal(http.StatusForbidden, s.call(app, "POST", "/v1/accounts", alice, model.AccountCreateRequest{AccountID: 9303, InitialBalance: "0", Owner: "bob"}, nil))
+	s.Require().Equal(http.StatusCreated, s.call(app, "POST", "/v1/accounts", admin, model.AccountCreateRequest{AccountID: 9303, InitialBalance: "0", Owner: "bob"}, nil))
+	s.Require().Equal(http.StatusOK, s.call(app, "GET", "/v1/accounts/9303", bob, nil, &acc))
+	s.Require().Equal("bob", acc.Owner)
+
+	// Money only leaves accounts the caller owns; either side may read the transfer.
+	move := model.TransferRequest{SourceAccountID: 9301, DestinationAccountID: 9302, Amount: "10"}
+	s.Require().Equal(http.StatusForbidden, s.call(app, "GET", "/v1/accounts/9301", bob, nil, nil))
+	s.Require().Equal(http.StatusForbidden, s.call(app, "POST", "/v1/transfers", bob, move, nil))
+
+	var tr model.TransferResponse
+	s.Require().Equal(http.StatusCreated, s.call(app, "POST", "/v1/transfers", alice, move, &tr))
+	txPath := "/v1/transfers/" + strconv.FormatInt(tr.TransactionID, 10)
+	s.Require().Equal(http.StatusOK, s.call(app, "GET", txPath, bob, nil, nil))
+	s.Require().Equal(http.StatusForbidden, s.call(app, "GET", txPath, carol, nil, nil))
+
+	// Delegates read and move money, but cannot pass access on; scopes still apply.
+	s.Require().Equal(http.StatusNoContent, s.call(app, "PUT", "/v1/accounts/9301/grants/carol", alice, nil, nil))
+	s.Require().Equal(http.StatusOK, s.call(app, "GET", "/v1/accounts/9301", carol, nil, nil))
+	s.Require().Equal(http.StatusForbidden, s.call(app, "POST", "/v1/transfers", carol, move, nil))
+
+	s.Require().Equal(http.StatusForbidden, s.call(app, "PUT", "/v1/accounts/9301/grants/bob", bob, nil, nil))
+	s.Require().Equal(http.StatusNoContent, s.call(app, "PUT", "/v1/accounts/9301/grants/bob", alice, nil, nil))
+	s.Require().Equal(http.StatusCreated, s.call(app, "POST", "/v1/transfers", bob, move, nil))
+	s.Require().Equal(http.StatusForbidden, s.call(app, "PUT", "/v1/accounts/9301/grants/dave", bob, nil, nil))
+
+	s.Require().Equal(http.StatusNoContent, s.call(app, "DELETE", "/v1/accounts/9301/grants/bob", alice, nil, nil))
+	s.Require().Equal(http.StatusNotFound, s.call(app, "DELETE", "/v1/accounts/9301/grants/bob", alice, nil, nil))
+	s.Require().Equal(http.StatusForbidden, s.call(app, "POST", "/v1/transfers", bob, move, nil))
+
+	// Admin routes need the admin scope.
+	shards := model.AccountShardRequest{Shards: 2}
+	s.Require().Equal(http.StatusForbidden, s.call(app, "PUT", "/v1/admin/accounts/9301/shards", alice, shards, nil))
+	s.Require().Equal(http.StatusForbidden, s.call(app, "GET", "/v1/admin/audit", alice, nil, nil))
+	s.Require().Equal(http.StatusOK, s.call(app, "PUT", "/v1/admin/accounts/9301/shards", admin, shards, nil))
+
+	var audit model.AuditListResponse
+	s.Require().Equal(http.StatusOK, s.call(app, "GET", "/v1/admin/audit?action=account.grant&resource_id=9301", admin, nil, &audit))
+	s.Require().Len(audit.Records, 2)
+	s.Require().Equal("alice", audit.Records[0].Actor)
+}
+
+func (s *E2eSuite) TestNegativeTransferRefused() {
+	app, svc, _ := s.authApp()
+	defer func() { _ = svc.Shutdown(s.ctx) }()
+
+	key := func(name string) map[string]string {
+		issued, err := svc.IssueAPIKey(s.asAdmin(), model.APIKeyIssueRequest{Name: name})
+		s.Require().NoError(err)
+		return map[string]string{"X-API-Key": issued.Key}
+	}
+	alice, bob := key("alice"), key("bob")
+	s.Require().Equal(http.StatusCreated, s.call(app, "POST", "/v1/accounts", alice, model.AccountCreateRequest{AccountID: 9311, InitialBalance: "100"}, nil))
+	s.Require().Equal(http.StatusCreated, s.call(app, "POST", "/v1/accounts", bob, model.AccountCreateRequest{AccountID: 9312, InitialBalance: "50"}, nil))
+
+	// Owning the source must not let a negative amount pull money out of the destination.
+	for _, amount := range []string{"-100", "0"} {
+		move := model.TransferRequest{SourceAccountID: 9311, DestinationAccountID: 9312, Amount: amount}
+		s.Require().Equal(http.StatusBadRequest, s.call(app, "POST", "/v1/transfers", alice, move, nil))
+	}
+
+	var acc model.AccountGetResponse
+	s.Require().Equal(http.StatusOK, s.call(app, "GET", "/v1/accounts/9312", bob, nil, &acc))
+	s.Require().Equal("50", acc.Balance)
+}
+
+func (s *E2eSuite) TestSignedRequests() {
+	app, svc, _ := s.authApp()
+	defer func() { _ = svc.Shutdown(s.ctx) }()
+
+	send := func(method, uri string, body []byte, signature string) int {
+		req := httptest.NewRequest(method, uri, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if signature != "" {
+			req.Header.Set(reqsign.Header, signature)
+		}
+		res, err := app.Test(req, -1)
+		s.Require().NoError(err)
+		return res.StatusCode
+	}
+
+	body, err := json.Marshal(model.AccountCreateRequest{AccountID: 9501, InitialBalance: "10"})
+	s.Require().NoError(err)
+	now := time.Now()
+
+	created := reqsign.Sign("partner-x", partnerSecret, "POST", "/v1/accounts", body, now, reqsign.NewNonce())
+	s.Require().Equal(http.StatusCreated, send("POST", "/v1/accounts", body, created))
+
+	// The partner owns what it created, and every request needs a fresh nonce.
+	read := reqsign.Sign("partner-x", partnerSecret, "GET", "/v1/accounts/9501?x=1", nil, now, reqsign.NewNonce())
+	s.Require().Equal(http.StatusOK, send("GET", "/v1/accounts/9501?x=1", nil, read))
+	s.Require().Equal(http.StatusUnauthorized, send("GET", "/v1/accounts/9501?x=1", nil, read))
+
+	// Method, URI and body are all covered.
+	moved := reqsign.Sign("partner-x", partnerSecret, "GET", "/v1/accounts/9501", nil, now, reqsign.NewNonce())
+	s.Require().Equal(http.StatusUnauthorized, send("GET", "/v1/accounts/9502", nil, moved))
+	tampered := reqsign.Sign("partner-x", partnerSecret, "POST", "/v1/accounts", body, now, reqsign.NewNonce())
+	s.Require().Equal(http.StatusUnauthorized, send("POST", "/v1/accounts", []byte(`{"account_id":9502,"initial_balance":"99"}`), tampered))
+
+	stale := reqsign.Sign("partner-x", partnerSecret, "GET", "/v1/accounts/9501", nil, now.Add(-2*time.Minute), reqsign.NewNonce())
+	s.Require().Equal(http.StatusUnauthorized, send("GET", "/v1/accounts/9501", nil, stale))
+	unknown := reqsign.Sign("partner-y", partnerSecret, "GET", "/v1/accounts/9501", nil, now, reqsign.NewNonce())
+	s.Require().Equal(http.StatusUnauthorized, send("GET", "/v1/accounts/9501", nil, unknown))
+	s.Require().Equal(http.StatusUnauthorized, send("GET", "/v1/accounts/9501", nil, ""))
+}
@@ -0,0 +1,90 @@
+package e2e_test
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"txn-processor/config"
+	"txn-processor/internal/adapter/outbound/gorm/dao"
+	"txn-processor/internal/core/model"
+)
+
+func (s *E2eSuite) TestGroupCommit() {
+	for _, acc := range []model.AccountCreateRequest{
+		{AccountID: 9981, InitialBalance: "100"},
+		{AccountID: 9982, InitialBalance: "0"},
+		{AccountID: 9983, InitialBalance: "50"},
+	} {
+		s.Require().Equal(http.StatusCreated, s.doJSON("POST", "/v1/accounts", acc, nil))
+	}
+	s.Require().Equal(http.StatusOK, s.doJSON("PUT", "/v1/admin/accounts/9983/freeze", nil, nil))
+
+	conn, err := dao.GetConnections()
+	s.Require().NoError(err)
+	transfers, err := dao.NewTransferDAO(conn, config.DB{GroupCommit: config.GroupCommit{IsEnabled: true, WindowMs: 300, MaxBatch: 10}})
+	s.Require().NoError(err)
+
+	// batch submits the transfers at once, so they land in one group commit.
+	batch := func(reqs ...model.TransferRequest) ([]*model.TransferResponse, []error) {
+		results := make([]*model.TransferResponse, len(reqs))
+		errs := make([]error, len(reqs))
+		var wg sync.WaitGroup
+		for i, req := range reqs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results[i], errs[i] = transfers.RunTransferTx(s.asAdmin(), req)
+			}()
+		}
+		wg.Wait()
+		return results, errs
+	}
+	balance := func(id string) string {
+		var acc model.AccountGetResponse
+		s.Require().Equal(http.StatusOK, s.doJSON("GET", "/v1/accounts/"+id, nil, &acc))
+		return acc.Balance
+	}
+
+	// A refused transfer fails alone; the rest of its batch commits.
+	_, errs := batch(
+		model.TransferRequest{SourceAccountID: 9981, DestinationAccountID: 9982, Amount: "30"},
+		model.TransferRequest{SourceAccountID: 9981, DestinationAccountID: 9982, Amount: "1000"},
+		model.TransferRequest{SourceAccountID: 9983, DestinationAccountID: 9982, Amount: "10"},
+		model.TransferRequest{SourceAccountID: 9981, DestinationAccountID: 9982, Amount: "20"},
+	)
+	s.Require().NoError(errs[0])
+	s.Require().ErrorIs(errs[1], dao.ErrInsufficientBalance)
+	s.Require().ErrorIs(errs[2], dao.ErrAccountFrozen)
+	s.Require().NoError(errs[3])
+	s.Require().Equal("50", balance("9981"))
+	s.Require().Equal("50", balance("9982"))
+
+	// A request ID given twice fails the batch insert, so each transfer runs on its own; the
+	// replay returns the first transfer and no money moves twice.
+	results, errs := batch(
+		model.TransferRequest{RequestID: "e2e-group-1", SourceAccountID: 9981, DestinationAccountID: 9982, Amount: "5"},
+		model.TransferRequest{RequestID: "e2e-group-1", SourceAccountID: 9981, DestinationAccountID: 9982, Amount: "5"},
+		model.TransferRequest{SourceAccountID: 9982, DestinationAccountID: 9981, Amount: "10"},
+	)
+	for _, err := range errs {
+		s.Require().NoError(err)
+	}
+	s.Require().Equal(results[0].TransactionID, results[1].TransactionID)
+	s.Require().Equal("55", balance("9981"))
+	s.Require().Equal("45", balance("9982"))
+
+	// Shutdown commits the transfer still waiting for its window and refuses the ones after.
+	queued := make(chan error, 1)
+	go func() {
+		_, err := transfers.RunTransferTx(s.asAdmin(), model.TransferRequest{SourceAccountID: 9981, DestinationAccountID: 9982, Amount: "5"})
+		queued <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+	s.shutdown(transfers)
+	s.Require().NoError(<-queued)
+	_, err = transfers.RunTransferTx(s.asAdmin(), model.TransferRequest{SourceAccountID: 9981, DestinationAccountID: 9982, Amount: "5"})
+	s.Require().Error(err)
+	s.Require().Equal("50", balance("9981"))
+	s.Require().Equal("50", balance("9982"))
+}
@@ -0,0 +1,43 @@
+package e2e_test
+
+import (
+	"net/http"
+	"time"
+	"txn-processor/internal/adapter/outbound/gorm/dao"
+	"txn-processor/internal/core/model"
+	"txn-processor/pkg/hashchain"
+)
+
+// chainSeed is the Ed25519 seed 0x00..0x1f.
+const chainSeed = "AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8="
+
+func (s *E2eSuite) TestTransferHashChain() {
+	s.Require().Equal(http.StatusCreated, s.doJSON("POST", "/v1/accounts", model.AccountCreateRequest{AccountID: 9001, InitialBalance: "100"}, nil))
+	s.Require().Equal(http.StatusCreated, s.doJSON("POST", "/v1/accounts", model.AccountCreateRequest{AccountID: 9002, InitialBalance: "0"}, nil))
+
+	for i := 0; i < 5; i++ {
+		s.Require().Equal(http.StatusCreated, s.doJSON("POST", "/v1/transfers", model.TransferRequest{
+			SourceAccountID:      9001,
+			DestinationAccountID: 9002,
+			Amount:               "1.25",
+		}, nil))
+	}
+
+	signer, err := hashchain.NewSigner("e2e", chainSeed)
+	s.Require().NoError(err)
+
+	// Once the chainer has caught up every link and checkpoint verifies against the public key.
+	var report *dao.ChainReport
+	s.Require().Eventually(func() bool {
+		report, err = dao.VerifyChain(s.ctx, signer.PublicKey())
+		return err == nil && report.Break == nil && report.Links >= 5 && report.Checkpoints > 0
+	}, 10*time.Second, 50*time.Millisecond)
+
+	// A different key does not verify the checkpoints.
+	other, err := hashchain.NewSigner("e2e", "HxwbGhkYFxYVFBMSERAPDg0MCwoJCAcGBQQDAgEAAAA=")
+	s.Require().NoError(err)
+	report, err = dao.VerifyChain(s.ctx, other.PublicKey())
+	s.Require().NoError(err)
+	s.Require().NotNil(report.Break)
+	s.Require().Equal("checkpoint signature invalid", report.Break.Reason)
+}
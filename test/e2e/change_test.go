@@ -0,0 +1,65 @@
+package e2e_test
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+	"txn-processor/internal/core/model"
+)
+
+func (s *E2eSuite) TestAccountChanges() {
+	s.Require().Equal(http.StatusCreated, s.doJSON("POST", "/v1/accounts", model.AccountCreateRequest{AccountID: 6001, InitialBalance: "100"}, nil))
+	s.Require().Equal(http.StatusCreated, s.doJSON("POST", "/v1/accounts", model.AccountCreateRequest{AccountID: 6002, InitialBalance: "100"}, nil))
+
+	for i := 0; i < 3; i++ {
+		s.Require().Equal(http.StatusCreated, s.doJSON("POST", "/v1/transfers", model.TransferRequest{
+			SourceAccountID:      6001,
+			DestinationAccountID: 6002,
+			Amount:               "10",
+		}, nil))
+	}
+
+	var acc model.AccountGetResponse
+	s.Require().Equal(http.StatusOK, s.doJSON("GET", "/v1/accounts/6001", nil, &acc))
+	s.Require().Equal(int64(4), acc.Version)
+
+	var page model.AccountChangesResponse
+	s.Require().Equal(http.StatusOK, s.doJSON("GET", "/v1/accounts/6001/changes", nil, &page))
+	s.Require().Len(page.Changes, 4)
+	s.Require().Equal(model.ChangeCreated, page.Changes[0].Kind)
+	s.Require().Equal("70", page.Changes[3].Balance)
+	s.Require().Equal("-10", page.Changes[3].Amount)
+	s.Require().Equal(int64(4), page.Next)
+
+	// Paging resumes after since.
+	s.Require().Equal(http.StatusOK, s.doJSON("GET", "/v1/accounts/6001/changes?since=2&limit=1", nil, &page))
+	s.Require().Len(page.Changes, 1)
+	s.Require().Equal(int64(3), page.Changes[0].Version)
+	s.Require().True(page.HasMore)
+
+	// Hot account changes are numbered by the sequencer and stay gap-free across a reshard.
+	s.Require().Equal(http.StatusOK, s.doJSON("PUT", "/v1/admin/accounts/6002/shards", model.AccountShardRequest{Shards: 4}, nil))
+	for i := 0; i < 5; i++ {
+		s.Require().Equal(http.StatusCreated, s.doJSON("POST", "/v1/transfers", model.TransferRequest{
+			SourceAccountID:      6001,
+			DestinationAccountID: 6002,
+			Amount:               "1",
+		}, nil))
+	}
+	s.Require().Equal(http.StatusOK, s.doJSON("PUT", "/v1/admin/accounts/6002/shards", model.AccountShardRequest{Shards: 0}, nil))
+
+	s.Require().Eventually(func() bool {
+		s.doJSON("GET", "/v1/accounts/6002/changes", nil, &page)
+		return len(page.Changes) == 11
+	}, 5*time.Second, 50*time.Millisecond)
+
+	for i, c := range page.Changes {
+		s.Require().Equal(int64(i+1), c.Version, fmt.Sprintf("change %d", i))
+	}
+	s.Require().Equal(model.ChangeReshard, page.Changes[4].Kind)
+	s.Require().Equal(model.ChangeReshard, page.Changes[10].Kind)
+	s.Require().Equal("135", page.Changes[10].Balance)
+
+	s.Require().Equal(http.StatusNotFound, s.doJSON("GET", "/v1/accounts/6999/changes", nil, nil))
+	s.Require().Equal(http.StatusBadRequest, s.doJSON("GET", "/v1/accounts/6001/changes?since=x", nil, nil))
+}
@@ -0,0 +1,173 @@
+package e2e_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"txn-processor/internal/adapter/inbound/fiber/router"
+	"txn-processor/internal/core/model"
+	"txn-processor/internal/core/service"
+	"txn-processor/pkg/client"
+	"txn-processor/pkg/tracing"
+)
+
+// sdkClient serves inbound on a loopback port and returns an SDK client for it.
+func (s *E2eSuite) sdkClient(inbound *service.Service, opts ...client.Option) *client.Client {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	s.Require().NoError(err)
+	app := router.New(inbound, tracing.NewBlankTracer())
+	go func() { _ = app.Listener(ln) }()
+	s.T().Cleanup(func() { _ = app.Shutdown() })
+
+	opts = append([]client.Option{client.WithBackoff(time.Millisecond, 10*time.Millisecond)}, opts...)
+	return client.New("http://"+ln.Addr().String(), opts...)
+}
+
+// lossyTransport delivers every request but loses the response to the first POST, as when a
+// connection drops after the server committed.
+type lossyTransport struct {
+	posts atomic.Int32
+}
+
+func (t *lossyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := http.DefaultTransport.RoundTrip(req)
+	if err == nil && req.Method == http.MethodPost && t.posts.Add(1) == 1 {
+		_, _ = io.Copy(io.Discard, res.Body)
+		_ = res.Body.Close()
+		return nil, errors.New("connection reset by peer")
+	}
+	return res, err
+}
+
+func (s *E2eSuite) TestClient() {
+	c := s.sdkClient(s.inbound)
+	ctx := s.ctx
+
+	s.Require().NoError(c.Health(ctx))
+
+	for _, id := range []int64{9901, 9902} {
+		_, err := c.CreateAccount(ctx, model.AccountCreateRequest{AccountID: id, InitialBalance: "100"})
+		s.Require().NoError(err)
+	}
+	_, err := c.CreateAccount(ctx, model.AccountCreateRequest{AccountID: 9901, InitialBalance: "1"})
+	s.Require().ErrorIs(err, client.ErrConflict)
+
+	tr, err := c.CreateTransfer(ctx, model.TransferRequest{SourceAccountID: 9901, DestinationAccountID: 9902, Amount: "40"})
+	s.Require().NoError(err)
+	s.Require().NotEmpty(tr.RequestID, "the SDK fills in a request ID")
+	s.Require().NotEmpty(tr.Receipt)
+
+	got, err := c.GetTransfer(ctx, tr.RequestID)
+	s.Require().NoError(err)
+	s.Require().Equal("completed", got.Status)
+	s.Require().Equal(tr.TransactionID, got.Transfer.TransactionID)
+
+	acc, err := c.GetAccount(ctx, 9902)
+	s.Require().NoError(err)
+	s.Require().Equal("140", acc.Balance)
+
+	changes, err := c.GetAccountChanges(ctx, 9902, 0, 10)
+	s.Require().NoError(err)
+	s.Require().NotEmpty(changes.Changes)
+
+	queued, err := c.SubmitTransfer(ctx, model.TransferRequest{SourceAccountID: 9902, DestinationAccountID: 9901, Amount: "1"})
+	s.Require().NoError(err)
+	s.Require().NotEmpty(queued.TransferID)
+
+	_, err = c.CreateTransfer(ctx, model.TransferRequest{SourceAccountID: 9901, DestinationAccountID: 9902, Amount: "1000"})
+	s.Require().ErrorIs(err, client.ErrInsufficientFunds)
+
+	_, err = c.GetAccount(ctx, 999999)
+	s.Require().ErrorIs(err, client.ErrNotFound)
+
+	// Validation failures carry the offending fields.
+	_, err = c.CreateTransfer(ctx, model.TransferRequest{SourceAccountID: 9901, DestinationAccountID: 9902})
+	s.Require().ErrorIs(err, client.ErrValidation)
+	var apiErr *client.Error
+	s.Require().ErrorAs(err, &apiErr)
+	s.Require().Equal(http.StatusBadRequest, apiErr.StatusCode)
+	s.Require().NotEmpty(apiErr.Details)
+
+	keys, err := c.ReceiptKeys(ctx)
+	s.Require().NoError(err)
+	s.Require().NotEmpty(keys.Keys)
+
+	doc, err := c.OpenAPI(ctx)
+	s.Require().NoError(err)
+	s.Require().Contains(string(doc), `"openapi"`)
+
+	var data struct {
+		Account struct {
+			Balance string `json:"balance"`
+		} `json:"account"`
+	}
+	s.Require().NoError(c.GraphQL(ctx, `query($id: ID!) { account(id: $id) { balance } }`, map[string]any{"id": "9901"}, &data))
+	s.Require().NotEmpty(data.Account.Balance)
+
+	var gqlErr *client.GraphQLError
+	s.Require().ErrorAs(c.GraphQL(ctx, `{ account(id: "999999") { balance } }`, nil, nil), &gqlErr)
+	s.Require().Equal("NOT_FOUND", gqlErr.Errors[0].Extensions["code"])
+}
+
+func (s *E2eSuite) TestClientRetriesTransferIdempotently() {
+	transport := &lossyTransport{}
+	c := s.sdkClient(s.inbound, client.WithHTTPClient(&http.Client{Transport: transport}))
+	ctx := s.ctx
+
+	for _, id := range []int64{9903, 9904} {
+		_, err := s.inbound.CreateAccount(ctx, model.AccountCreateRequest{AccountID: id, InitialBalance: "100"})
+		s.Require().NoError(err)
+	}
+
+	// The first attempt commits but its response is lost; the retry replays the same request ID.
+	tr, err := c.CreateTransfer(ctx, model.TransferRequest{SourceAccountID: 9903, DestinationAccountID: 9904, Amount: "30"})
+	s.Require().NoError(err)
+	s.Require().EqualValues(2, transport.posts.Load())
+
+	acc, err := c.GetAccount(ctx, 9903)
+	s.Require().NoError(err)
+	s.Require().Equal("70", acc.Balance, "the transfer ran once")
+
+	got, err := c.GetTransfer(ctx, tr.RequestID)
+	s.Require().NoError(err)
+	s.Require().Equal(tr.TransactionID, got.Transfer.TransactionID)
+
+	// Account creation is not idempotent, so a lost response is not retried.
+	transport.posts.Store(0)
+	_, err = c.CreateAccount(ctx, model.AccountCreateRequest{AccountID: 9905, InitialBalance: "1"})
+	s.Require().ErrorContains(err, "connection reset")
+}
+
+func (s *E2eSuite) TestClientBalanceStream() {
+	c := s.sdkClient(s.inbound)
+	ctx, cancel := context.WithTimeout(s.ctx, 10*time.Second)
+	defer cancel()
+
+	for _, id := range []int64{9906, 9907} {
+		_, err := c.CreateAccount(ctx, model.AccountCreateRequest{AccountID: id, InitialBalance: "100"})
+		s.Require().NoError(err)
+	}
+
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		_, _ = c.CreateTransfer(ctx, model.TransferRequest{SourceAccountID: 9906, DestinationAccountID: 9907, Amount: "25"})
+	}()
+
+	done := errors.New("done")
+	var balances []string
+	last, err := c.StreamBalance(ctx, 9906, 0, func(u model.BalanceUpdate) error {
+		balances = append(balances, u.Balance)
+		if u.Balance == "75" {
+			return done
+		}
+		return nil
+	})
+	s.Require().ErrorIs(err, done)
+	s.Require().Equal("100", balances[0], "the stream starts with a snapshot")
+	s.Require().Positive(last)
+}
@@ -0,0 +1,100 @@
+package e2e_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"txn-processor/internal/adapter/outbound/gorm/dao"
+	"txn-processor/pkg/stream"
+
+	"github.com/go-redis/redis/v8"
+)
+
+func (s *E2eSuite) TestStreamConsumer() {
+	const (
+		orders = "e2e:stream:orders"
+		dead   = "e2e:stream:orders:dead"
+		group  = "e2e"
+	)
+	conn, err := dao.GetConnections()
+	s.Require().NoError(err)
+	rdb := conn.Redis()
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	defer cancel()
+
+	producer := stream.NewProducer(rdb, 0)
+	publish := func(order string) {
+		_, err := producer.Publish(ctx, orders, map[string]string{"order": order})
+		s.Require().NoError(err)
+	}
+
+	// An entry read by a consumer that died before acknowledging it.
+	s.Require().NoError(rdb.XGroupCreateMkStream(ctx, orders, group, "0").Err())
+	publish("orphan")
+	s.Require().NoError(rdb.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group: group, Consumer: "ghost", Streams: []string{orders, ">"}, Count: 1,
+	}).Err())
+
+	var mu sync.Mutex
+	handled := map[string]int64{}
+	attempts := map[string]int{}
+	handler := func(_ context.Context, msg stream.Message) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		order := msg.Values["order"]
+		attempts[order]++
+		switch {
+		case order == "poison", order == "flaky" && attempts[order] == 1:
+			return errors.New("not now")
+		}
+		handled[order] = msg.Deliveries
+		return nil
+	}
+
+	consumer := stream.NewConsumer(rdb, stream.ConsumerConfig{
+		Streams:       []string{orders},
+		Group:         group,
+		Consumer:      "e2e-1",
+		Block:         50 * time.Millisecond,
+		ClaimIdle:     200 * time.Millisecond,
+		MaxDeliveries: 2,
+		DeadLetter:    stream.DeadLetterTo(rdb, dead),
+	})
+	done := make(chan error, 1)
+	go func() { done <- consumer.Run(ctx, handler) }()
+
+	publish("ok")
+	publish("flaky")
+	publish("poison")
+
+	// Everything is eventually handled or parked, and acknowledged either way.
+	s.Require().Eventually(func() bool {
+		n, err := rdb.XLen(ctx, dead).Result()
+		if err != nil || n != 1 {
+			return false
+		}
+		pending, err := rdb.XPending(ctx, orders, group).Result()
+		return err == nil && pending.Count == 0
+	}, 10*time.Second, 50*time.Millisecond)
+
+	// Failed entries stay pending and come back once idle; the orphan is taken over.
+	mu.Lock()
+	s.Require().Equal(map[string]int64{"ok": 1, "flaky": 2, "orphan": 2}, handled)
+	s.Require().Equal(2, attempts["poison"])
+	mu.Unlock()
+
+	// The poison entry was parked with its origin after failing MaxDeliveries times.
+	parked, err := rdb.XRange(ctx, dead, "-", "+").Result()
+	s.Require().NoError(err)
+	s.Require().Equal("poison", parked[0].Values["order"])
+	s.Require().Equal(orders, parked[0].Values[stream.FieldDeadSourceStream])
+	s.Require().Equal("3", parked[0].Values[stream.FieldDeadDeliveries])
+	s.Require().Equal("max deliveries exceeded", parked[0].Values[stream.FieldDeadReason])
+
+	cancel()
+	s.Require().NoError(<-done)
+}
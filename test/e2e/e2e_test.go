@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"io"
 	"net/http/httptest"
 	"testing"
 	"time"
@@ -11,6 +12,10 @@ import (
 	"txn-processor/config"
 	"txn-processor/internal/adapter/inbound/fiber/router"
 	"txn-processor/internal/adapter/outbound/gorm/dao"
+	"txn-processor/internal/adapter/outbound/logger"
+	"txn-processor/internal/adapter/outbound/redispubsub"
+	"txn-processor/internal/adapter/outbound/redisratelimit"
+	"txn-processor/internal/adapter/outbound/webhook"
 	"txn-processor/internal/core/model"
 	"txn-processor/internal/core/service"
 	"txn-processor/pkg/tracing"
@@ -24,6 +29,7 @@ import (
 type E2eSuite struct {
 	suite.Suite
 	app        *fiber.App
+	inbound    *service.Service
 	outbound   *dao.Dao
 	mariaC     *mariadb.MariaDBContainer
 	redisC     *redis.RedisContainer
@@ -82,13 +88,56 @@ func (s *E2eSuite) SetupSuite() {
 
 	s.Require().NoError(dao.AutoMigrate(s.ctx))
 
-	inbound := service.New(outbound, tracer)
+	appCfg := &config.App{
+		Async:  config.Async{Workers: 2, QueueSize: 10},
+		Outbox: config.Outbox{IsEnabled: true, PollIntervalMs: 20, BatchSize: 100},
+		Webhook: config.Webhook{
+			IsEnabled:      true,
+			PollIntervalMs: 20,
+			BatchSize:      10,
+			TimeoutMs:      1000,
+			MaxAttempts:    5,
+			RetryBaseMs:    10,
+			RetryMaxSec:    1,
+			DisableAfter:   3,
+		},
+		Realtime: config.Realtime{IsEnabled: true},
+		Changes:  config.Changes{SequenceIntervalMs: 20, SequenceBatch: 100},
+		Import:   config.Import{ChunkSize: 2},
+		Chain: config.Chain{
+			IsEnabled:             true,
+			IntervalMs:            20,
+			BatchSize:             100,
+			CheckpointEvery:       3,
+			CheckpointIntervalSec: 3600,
+			SigningKey:            chainSeed,
+			KeyID:                 "e2e",
+		},
+		Receipt: config.Receipt{
+			IsEnabled:   true,
+			SigningKeys: map[string]string{"r1": receiptSeedOld, "r2": receiptSeed},
+			ActiveKeyID: "r2",
+		},
+	}
+	conn, err := dao.GetConnections()
+	s.Require().NoError(err)
+	broker := redispubsub.NewBroker(conn.Redis(), config.Realtime{History: 100, BufferSize: 16})
+
+	inbound := service.New(outbound, logger.NewPublisher(), webhook.NewSender(time.Second), broker, redisratelimit.NewLimiter(conn.Redis()), tracer, appCfg)
+	inbound.Start(s.ctx)
+	s.inbound = inbound
 	s.app = router.New(inbound, tracer)
 }
 
 func (s *E2eSuite) TearDownSuite() {
 	defer s.cancelFunc()
 
+	if s.inbound != nil {
+		_ = s.inbound.Shutdown(s.ctx)
+	}
+	if s.outbound != nil {
+		_ = s.outbound.Shutdown(s.ctx)
+	}
 	if s.mariaC != nil {
 		_ = s.mariaC.Terminate(s.ctx)
 	}
@@ -97,6 +146,32 @@ func (s *E2eSuite) TearDownSuite() {
 	}
 }
 
+// shutdown stops the background work of a DAO a test built for itself.
+func (s *E2eSuite) shutdown(d any) {
+	if stopper, ok := d.(interface{ Shutdown(context.Context) error }); ok {
+		s.Require().NoError(stopper.Shutdown(s.ctx))
+	}
+}
+
+func (s *E2eSuite) doJSON(method, path string, in any, out any) int {
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		s.Require().NoError(err)
+		body = bytes.NewReader(b)
+	}
+
+	req := httptest.NewRequest(method, path, body)
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := s.app.Test(req, -1)
+	s.Require().NoError(err)
+	if out != nil {
+		s.Require().NoError(json.NewDecoder(res.Body).Decode(out))
+	}
+	return res.StatusCode
+}
+
 func (s *E2eSuite) TestLifecycle() {
 	// Step 1: Create Account 1001 with 500
 	acc1 := model.AccountCreateRequest{
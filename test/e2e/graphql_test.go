@@ -0,0 +1,160 @@
+package e2e_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"txn-processor/internal/core/model"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type graphqlError struct {
+	Message    string         `json:"message"`
+	Extensions map[string]any `json:"extensions"`
+}
+
+type graphqlResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []graphqlError  `json:"errors"`
+}
+
+// graphql posts query to app and decodes the data into out.
+func (s *E2eSuite) graphql(app *fiber.App, headers map[string]string, query string, vars map[string]any, out any) (int, []graphqlError) {
+	var res graphqlResponse
+	status := s.call(app, "POST", "/v1/graphql", headers, map[string]any{"query": query, "variables": vars}, &res)
+	if out != nil && len(res.Data) > 0 {
+		s.Require().NoError(json.Unmarshal(res.Data, out))
+	}
+	return status, res.Errors
+}
+
+type graphqlAccount struct {
+	ID              string `json:"id"`
+	Balance         string `json:"balance"`
+	RecentTransfers []struct {
+		ID          string               `json:"id"`
+		Amount      string               `json:"amount"`
+		Source      *struct{ ID string } `json:"source"`
+		Destination *struct {
+			ID      string `json:"id"`
+			Balance string `json:"balance"`
+		} `json:"destination"`
+	} `json:"recentTransfers"`
+	Statement struct {
+		Entries []struct {
+			Kind     string               `json:"kind"`
+			Amount   string               `json:"amount"`
+			Transfer *struct{ ID string } `json:"transfer"`
+		} `json:"entries"`
+	} `json:"statement"`
+}
+
+const accountQuery = `query($id: ID!) {
+  account(id: $id) {
+    id balance
+    recentTransfers(last: 5) { id amount source { id } destination { id balance } }
+    statement(limit: 10) { entries { kind amount transfer { id } } }
+  }
+}`
+
+func (s *E2eSuite) TestGraphQL() {
+	for _, id := range []int64{9701, 9702} {
+		s.Require().Equal(http.StatusCreated, s.doJSON("POST", "/v1/accounts", model.AccountCreateRequest{AccountID: id, InitialBalance: "100"}, nil))
+	}
+
+	// Mutations run through the transfer service.
+	var created struct {
+		CreateTransfer struct {
+			ID      string `json:"id"`
+			Amount  string `json:"amount"`
+			Receipt string `json:"receipt"`
+		} `json:"createTransfer"`
+	}
+	move := `mutation($amount: String!) {
+  createTransfer(input: {sourceAccountId: 9701, destinationAccountId: "9702", amount: $amount}) { id amount receipt }
+}`
+	for _, amount := range []string{"10", "2.5"} {
+		status, errs := s.graphql(s.app, nil, move, map[string]any{"amount": amount}, &created)
+		s.Require().Equal(http.StatusOK, status)
+		s.Require().Empty(errs)
+	}
+	s.Require().Equal("2.5000", created.CreateTransfer.Amount)
+	s.Require().NotEmpty(created.CreateTransfer.Receipt)
+
+	// One round trip returns the account, its recent transfers and their counterparties.
+	var got struct{ Account graphqlAccount }
+	status, errs := s.graphql(s.app, nil, accountQuery, map[string]any{"id": "9701"}, &got)
+	s.Require().Equal(http.StatusOK, status)
+	s.Require().Empty(errs)
+	s.Require().Equal("9701", got.Account.ID)
+	s.Require().Equal("87.5000", got.Account.Balance)
+	s.Require().Len(got.Account.RecentTransfers, 2)
+	s.Require().Equal(created.CreateTransfer.ID, got.Account.RecentTransfers[0].ID)
+	s.Require().Equal("9701", got.Account.RecentTransfers[0].Source.ID)
+	s.Require().Equal("112.5000", got.Account.RecentTransfers[0].Destination.Balance)
+
+	entries := got.Account.Statement.Entries
+	s.Require().Len(entries, 3)
+	s.Require().Equal(model.ChangeCreated, entries[0].Kind)
+	s.Require().Nil(entries[0].Transfer)
+	s.Require().Equal(model.ChangeDebit, entries[2].Kind)
+	s.Require().Equal(created.CreateTransfer.ID, entries[2].Transfer.ID)
+
+	// Service errors come back as coded field errors next to partial data.
+	status, errs = s.graphql(s.app, nil, `{ account(id: "999999") { id } }`, nil, nil)
+	s.Require().Equal(http.StatusOK, status)
+	s.Require().Len(errs, 1)
+	s.Require().Equal("NOT_FOUND", errs[0].Extensions["code"])
+
+	_, errs = s.graphql(s.app, nil, move, map[string]any{"amount": "1000"}, nil)
+	s.Require().Len(errs, 1)
+	s.Require().Equal("INSUFFICIENT_FUNDS", errs[0].Extensions["code"])
+
+	_, errs = s.graphql(s.app, nil, `{ account(id: "x") { id } }`, nil, nil)
+	s.Require().Len(errs, 1)
+	s.Require().Equal("BAD_USER_INPUT", errs[0].Extensions["code"])
+
+	// Queries that would fan out past the complexity limit are refused before they run.
+	// Limits are priced at the page the service serves: 0 means the default page, and a limit it
+	// refuses counts as the largest one.
+	for _, fanOut := range []string{
+		`{ account(id: "9701") { statement(limit: 100) { entries { transfer { source { recentTransfers(last: 100) { id } } } } } } }`,
+		`{ account(id: "9701") { statement(limit: 0) { entries { transfer { source { statement(limit: 0) { next } } } } } } }`,
+		`{ statement(accountId: "9701", limit: -1) { entries { transfer { id } } } account(id: "9701") { id } }`,
+		`{ account(id: "9701") { recentTransfers(last: -1) { source { statement(limit: 10) { next } } } } }`,
+	} {
+		status, errs = s.graphql(s.app, nil, fanOut, nil, nil)
+		s.Require().Equal(http.StatusUnprocessableEntity, status, fanOut)
+		s.Require().NotEmpty(errs, fanOut)
+		s.Require().Equal("COMPLEXITY_LIMIT_EXCEEDED", errs[0].Extensions["code"], fanOut)
+	}
+}
+
+func (s *E2eSuite) TestGraphQLAuthorization() {
+	app, svc, _ := s.authApp()
+	defer func() { _ = svc.Shutdown(s.ctx) }()
+
+	key := func(name string) map[string]string {
+		issued, err := svc.IssueAPIKey(s.asAdmin(), model.APIKeyIssueRequest{Name: name})
+		s.Require().NoError(err)
+		return map[string]string{"X-API-Key": issued.Key}
+	}
+	alice, bob := key("gql-alice"), key("gql-bob")
+
+	s.Require().Equal(http.StatusUnauthorized, s.call(app, "POST", "/v1/graphql", nil, map[string]any{"query": "{ account(id: 9703) { id } }"}, nil))
+	s.Require().Equal(http.StatusCreated, s.call(app, "POST", "/v1/accounts", alice, model.AccountCreateRequest{AccountID: 9703, InitialBalance: "50"}, nil))
+	s.Require().Equal(http.StatusCreated, s.call(app, "POST", "/v1/accounts", bob, model.AccountCreateRequest{AccountID: 9704, InitialBalance: "0"}, nil))
+	s.Require().Equal(http.StatusCreated, s.call(app, "POST", "/v1/transfers", alice, model.TransferRequest{SourceAccountID: 9703, DestinationAccountID: 9704, Amount: "5"}, nil))
+
+	// Alice sees her transfer, but not bob's account on the other side of it.
+	var got struct{ Account graphqlAccount }
+	_, errs := s.graphql(app, alice, accountQuery, map[string]any{"id": 9703}, &got)
+	s.Require().Empty(errs)
+	s.Require().Len(got.Account.RecentTransfers, 1)
+	s.Require().NotNil(got.Account.RecentTransfers[0].Source)
+	s.Require().Nil(got.Account.RecentTransfers[0].Destination)
+
+	_, errs = s.graphql(app, bob, accountQuery, map[string]any{"id": 9703}, nil)
+	s.Require().Len(errs, 1)
+	s.Require().Equal("FORBIDDEN", errs[0].Extensions["code"])
+}
@@ -0,0 +1,107 @@
+package e2e_test
+
+import (
+	"context"
+	"net"
+	"strconv"
+
+	grpcapi "txn-processor/internal/adapter/inbound/grpc"
+	txnv1 "txn-processor/internal/adapter/inbound/grpc/pb/txn/v1"
+	"txn-processor/internal/core/model"
+	"txn-processor/internal/port"
+	"txn-processor/pkg/tracing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// grpcClient serves inbound over an in-memory listener and returns a connection to it.
+func (s *E2eSuite) grpcClient(inbound port.Inbound) *grpc.ClientConn {
+	lis := bufconn.Listen(1 << 20)
+	srv := grpcapi.New(inbound, tracing.NewBlankTracer())
+	go func() { _ = srv.Serve(lis) }()
+	s.T().Cleanup(func() { _ = srv.Shutdown(s.ctx) })
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	s.Require().NoError(err)
+	s.T().Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func (s *E2eSuite) TestGRPC() {
+	conn := s.grpcClient(s.inbound)
+	health := txnv1.NewHealthServiceClient(conn)
+	accounts := txnv1.NewAccountServiceClient(conn)
+	transfers := txnv1.NewTransferServiceClient(conn)
+
+	hc, err := health.Check(s.ctx, &txnv1.CheckRequest{})
+	s.Require().NoError(err)
+	s.Require().True(hc.Serving)
+
+	for _, id := range []int64{9601, 9602} {
+		_, err := accounts.CreateAccount(s.ctx, &txnv1.CreateAccountRequest{AccountId: id, InitialBalance: "100"})
+		s.Require().NoError(err)
+	}
+
+	tr, err := transfers.CreateTransfer(s.ctx, &txnv1.CreateTransferRequest{SourceAccountId: 9601, DestinationAccountId: 9602, Amount: "40.5"})
+	s.Require().NoError(err)
+	s.Require().Equal("40.5000", tr.Amount)
+	s.Require().NotEmpty(tr.Receipt)
+
+	got, err := transfers.GetTransfer(s.ctx, &txnv1.GetTransferRequest{TransferId: strconv.FormatInt(tr.TransactionId, 10)})
+	s.Require().NoError(err)
+	s.Require().Equal("completed", got.Status)
+	s.Require().Equal(tr.TransactionId, got.Transfer.TransactionId)
+
+	acc, err := accounts.GetAccount(s.ctx, &txnv1.GetAccountRequest{AccountId: 9602})
+	s.Require().NoError(err)
+	s.Require().Equal("140.5000", acc.Balance)
+
+	changes, err := accounts.GetAccountChanges(s.ctx, &txnv1.GetAccountChangesRequest{AccountId: 9602})
+	s.Require().NoError(err)
+	s.Require().NotEmpty(changes.Changes)
+
+	// Service errors keep their meaning as status codes.
+	codeOf := func(err error) codes.Code { return status.Code(err) }
+	_, err = accounts.GetAccount(s.ctx, &txnv1.GetAccountRequest{AccountId: 999999})
+	s.Require().Equal(codes.NotFound, codeOf(err))
+	_, err = accounts.CreateAccount(s.ctx, &txnv1.CreateAccountRequest{AccountId: 9601, InitialBalance: "1"})
+	s.Require().Equal(codes.AlreadyExists, codeOf(err))
+	_, err = transfers.CreateTransfer(s.ctx, &txnv1.CreateTransferRequest{SourceAccountId: 9601, DestinationAccountId: 9601, Amount: "1"})
+	s.Require().Equal(codes.InvalidArgument, codeOf(err))
+	_, err = transfers.CreateTransfer(s.ctx, &txnv1.CreateTransferRequest{SourceAccountId: 9601, DestinationAccountId: 9602, Amount: "1000"})
+	s.Require().Equal(codes.FailedPrecondition, codeOf(err))
+}
+
+func (s *E2eSuite) TestGRPCAuthentication() {
+	_, svc, _ := s.authApp()
+	defer func() { _ = svc.Shutdown(s.ctx) }()
+	conn := s.grpcClient(svc)
+	accounts := txnv1.NewAccountServiceClient(conn)
+
+	// Health stays public; everything else needs credentials and follows the same policy.
+	_, err := txnv1.NewHealthServiceClient(conn).Check(s.ctx, &txnv1.CheckRequest{})
+	s.Require().NoError(err)
+	_, err = accounts.GetAccount(s.ctx, &txnv1.GetAccountRequest{AccountId: 9601})
+	s.Require().Equal(codes.Unauthenticated, status.Code(err))
+
+	issued, err := svc.IssueAPIKey(s.asAdmin(), model.APIKeyIssueRequest{Name: "grpc-client"})
+	s.Require().NoError(err)
+	ctx := metadata.AppendToOutgoingContext(s.ctx, grpcapi.APIKeyKey, issued.Key)
+
+	_, err = accounts.CreateAccount(ctx, &txnv1.CreateAccountRequest{AccountId: 9603, InitialBalance: "5"})
+	s.Require().NoError(err)
+	acc, err := accounts.GetAccount(ctx, &txnv1.GetAccountRequest{AccountId: 9603})
+	s.Require().NoError(err)
+	s.Require().Equal("grpc-client", acc.Owner)
+
+	_, err = accounts.GetAccount(ctx, &txnv1.GetAccountRequest{AccountId: 9601})
+	s.Require().Equal(codes.PermissionDenied, status.Code(err))
+}
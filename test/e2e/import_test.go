@@ -0,0 +1,121 @@
+package e2e_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"txn-processor/internal/core/model"
+	"txn-processor/pkg/bulk"
+	"txn-processor/pkg/client"
+)
+
+func (s *E2eSuite) TestImport() {
+	c := s.sdkClient(s.inbound)
+	ctx := s.ctx
+
+	const accounts = "account_id,initial_balance,owner\n" +
+		"9921,100,\n" +
+		"9922,50,\n" +
+		"9921,70,\n" +
+		"abc,10,\n" +
+		"9924,5,,extra\n"
+
+	// The upload stops at a line that cannot be parsed, keeping the rows before it.
+	_, err := c.Import(ctx, "e2e-accounts", model.ImportAccounts, bulk.CSV, strings.NewReader(accounts+"9923,1\"0,\n"))
+	s.Require().ErrorIs(err, client.ErrValidation)
+
+	job, err := c.GetImport(ctx, "e2e-accounts")
+	s.Require().NoError(err)
+	s.Require().Equal(model.ImportRunning, job.Status)
+	s.Require().Equal(int64(5), job.Rows)
+
+	// Uploading the fixed file resumes after the applied rows.
+	job, err = c.Import(ctx, "e2e-accounts", model.ImportAccounts, bulk.CSV, strings.NewReader(accounts+"9923,25,\n"))
+	s.Require().NoError(err)
+	s.Require().Equal(model.ImportCompleted, job.Status)
+	s.Require().Equal(int64(6), job.Rows)
+	s.Require().Equal(int64(3), job.Created)
+	s.Require().Equal(int64(1), job.Existing)
+	s.Require().Equal(int64(2), job.Failed)
+
+	acc, err := c.GetAccount(ctx, 9921)
+	s.Require().NoError(err)
+	s.Require().Equal("100", acc.Balance)
+
+	_, err = c.Import(ctx, "e2e-accounts", model.ImportTransfers, bulk.CSV, strings.NewReader(accounts))
+	s.Require().ErrorIs(err, client.ErrConflict)
+
+	page, err := c.ListImportResults(ctx, "e2e-accounts", 0, 4)
+	s.Require().NoError(err)
+	s.Require().Len(page.Results, 4)
+	s.Require().Equal(int64(4), page.NextAfterRow)
+	s.Require().Equal(model.ImportResult{Row: 3, Status: model.ImportRowExists, AccountID: 9921}, page.Results[2])
+	s.Require().Equal(model.ImportRowFailed, page.Results[3].Status)
+	s.Require().Contains(page.Results[3].Error, "account_id")
+
+	page, err = c.ListImportResults(ctx, "e2e-accounts", page.NextAfterRow, 4)
+	s.Require().NoError(err)
+	s.Require().Len(page.Results, 2)
+	s.Require().Zero(page.NextAfterRow)
+	s.Require().Equal("4 fields, want 3", page.Results[0].Error)
+	s.Require().Equal(model.ImportResult{Row: 6, Status: model.ImportRowCreated, AccountID: 9923}, page.Results[1])
+
+	// The results come as a file in the format asked for.
+	req := httptest.NewRequest("GET", "/v1/admin/imports/e2e-accounts/results", nil)
+	req.Header.Set("Accept", "text/csv")
+	res, err := s.app.Test(req, -1)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, res.StatusCode)
+	file, err := io.ReadAll(res.Body)
+	s.Require().NoError(err)
+	lines := strings.Split(strings.TrimSpace(string(file)), "\n")
+	s.Require().Len(lines, 7)
+	s.Require().Equal("row,status,account_id,transaction_id,error", lines[0])
+	s.Require().Equal("6,created,9923,,", lines[6])
+
+	// Transfers apply one by one; refused rows are recorded and the rest go on.
+	const transfers = `{"source_account_id":9921,"destination_account_id":9922,"amount":"30","request_id":"e2e-import-1"}
+{"source_account_id":9922,"destination_account_id":9921,"amount":"1000"}
+
+{"source_account_id":9921,"destination_account_id":999999,"amount":"1"}
+[9921,9922,"1"]
+{"source_account_id":9922,"destination_account_id":9921,"amount":"-5"}
+{"source_account_id":9921,"destination_account_id":9922,"amount":"31","request_id":"e2e-import-1"}
+{"source_account_id":9921,"destination_account_id":9922,"amount":"30.00","request_id":"e2e-import-1"}
+{"source_account_id":9921,"destination_account_id":9922,"amount":"1","request_id":"import:e2e-transfers:8"}
+`
+	job, err = c.Import(ctx, "e2e-transfers", model.ImportTransfers, bulk.NDJSON, strings.NewReader(transfers))
+	s.Require().NoError(err)
+	s.Require().Equal(int64(8), job.Rows)
+	s.Require().Equal(int64(2), job.Created)
+	s.Require().Equal(int64(6), job.Failed)
+
+	page, err = c.ListImportResults(ctx, "e2e-transfers", 0, 0)
+	s.Require().NoError(err)
+	s.Require().Len(page.Results, 8)
+	s.Require().NotZero(page.Results[0].TransactionID)
+	s.Require().Equal("not a JSON object", page.Results[3].Error)
+	s.Require().Equal(`invalid amount "-5"`, page.Results[4].Error)
+
+	// A request ID replays only the transfer it was given to.
+	s.Require().Equal(model.ImportRowFailed, page.Results[5].Status)
+	s.Require().Zero(page.Results[5].TransactionID)
+	s.Require().Equal(model.ImportRowCreated, page.Results[6].Status)
+	s.Require().Equal(page.Results[0].TransactionID, page.Results[6].TransactionID)
+	s.Require().Equal(`reserved request_id "import:e2e-transfers:8"`, page.Results[7].Error)
+
+	tr, err := c.GetTransfer(ctx, "e2e-import-1")
+	s.Require().NoError(err)
+	s.Require().Equal(page.Results[0].TransactionID, tr.Transfer.TransactionID)
+
+	// Uploading the same file again moves no money twice.
+	job, err = c.Import(ctx, "e2e-transfers", model.ImportTransfers, bulk.NDJSON, strings.NewReader(transfers))
+	s.Require().NoError(err)
+	s.Require().Equal(int64(2), job.Created)
+
+	acc, err = c.GetAccount(ctx, 9921)
+	s.Require().NoError(err)
+	s.Require().Equal("70", acc.Balance)
+}
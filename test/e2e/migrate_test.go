@@ -0,0 +1,78 @@
+package e2e_test
+
+import (
+	"net/http"
+
+	"txn-processor/internal/adapter/outbound/gorm/dao"
+	"txn-processor/internal/core/model"
+)
+
+// columnType returns the DATA_TYPE of a column, or "" if there is no such column.
+func (s *E2eSuite) columnType(table, column string) string {
+	conn, err := dao.GetConnections()
+	s.Require().NoError(err)
+
+	var types []string
+	s.Require().NoError(conn.DB().WithContext(s.ctx).Raw(
+		"SELECT DATA_TYPE FROM INFORMATION_SCHEMA.COLUMNS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND COLUMN_NAME = ?",
+		table, column,
+	).Scan(&types).Error)
+	if len(types) == 0 {
+		return ""
+	}
+	return types[0]
+}
+
+func (s *E2eSuite) TestNegativeInitialBalanceRefused() {
+	s.Require().Equal(http.StatusBadRequest, s.doJSON("POST", "/v1/accounts", model.AccountCreateRequest{AccountID: 9932, InitialBalance: "-1"}, nil))
+	s.Require().Equal(http.StatusNotFound, s.doJSON("GET", "/v1/accounts/9932", nil, nil))
+}
+
+func (s *E2eSuite) TestMigrateLegacyMoneyColumn() {
+	s.Require().Equal(http.StatusCreated, s.doJSON("POST", "/v1/accounts", model.AccountCreateRequest{AccountID: 9931, InitialBalance: "12.5"}, nil))
+
+	conn, err := dao.GetConnections()
+	s.Require().NoError(err)
+	db := conn.DB().WithContext(s.ctx)
+	exec := func(sql string, args ...any) {
+		s.Require().NoError(db.Exec(sql, args...).Error)
+	}
+
+	// Put the balance back the way it was before the DECIMAL migration, and restore it however
+	// the test ends.
+	s.T().Cleanup(func() {
+		exec("ALTER TABLE accounts DROP COLUMN IF EXISTS balance_legacy, DROP COLUMN IF EXISTS balance_decimal")
+		exec("ALTER TABLE accounts MODIFY balance DECIMAL(20,4) NOT NULL")
+	})
+	exec("ALTER TABLE accounts MODIFY balance VARCHAR(255) NOT NULL")
+	s.Require().Equal("varchar", s.columnType("accounts", "balance"))
+
+	// A value that does not fit DECIMAL(20,4) stops the migration and leaves the column as it was.
+	exec("UPDATE accounts SET balance = '1.23456' WHERE account_id = 9931")
+	s.Require().Error(dao.MigrateDecimal(s.ctx))
+	s.Require().Equal("varchar", s.columnType("accounts", "balance"))
+	s.Require().Empty(s.columnType("accounts", "balance_decimal"))
+
+	exec("UPDATE accounts SET balance = '12.5' WHERE account_id = 9931")
+	s.Require().NoError(dao.MigrateDecimal(s.ctx))
+	s.Require().Equal("decimal", s.columnType("accounts", "balance"))
+	s.Require().Equal("varchar", s.columnType("accounts", "balance_legacy"))
+	s.Require().NoError(dao.MigrateDecimal(s.ctx))
+
+	var balance string
+	s.Require().NoError(db.Raw("SELECT CAST(balance AS CHAR) FROM accounts WHERE account_id = ?", 9931).Scan(&balance).Error)
+	s.Require().Equal("12.5000", balance)
+
+	mismatches, err := dao.VerifyDecimal(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Empty(mismatches)
+
+	// Verification compares against the kept legacy values.
+	exec("UPDATE accounts SET balance_legacy = '12.49' WHERE account_id = 9931")
+	mismatches, err = dao.VerifyDecimal(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Len(mismatches, 1)
+	s.Require().Equal("accounts", mismatches[0].Table)
+	s.Require().Equal("12.49", mismatches[0].Old)
+	s.Require().Equal("12.5000", mismatches[0].New)
+}
@@ -0,0 +1,75 @@
+package e2e_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"txn-processor/internal/adapter/inbound/fiber/middleware"
+	"txn-processor/internal/core/model"
+)
+
+func (s *E2eSuite) TestOpenAPI() {
+	var doc struct {
+		OpenAPI string                    `json:"openapi"`
+		Paths   map[string]map[string]any `json:"paths"`
+	}
+	s.Require().Equal(http.StatusOK, s.doJSON("GET", "/v1/openapi.json", nil, &doc))
+	s.Require().Equal("3.0.3", doc.OpenAPI)
+	s.Require().Contains(doc.Paths["/v1/transfers"], "post")
+
+	s.Require().Equal(http.StatusCreated, s.doJSON("POST", "/v1/accounts", model.AccountCreateRequest{AccountID: 9801, InitialBalance: "10"}, nil))
+
+	post := func(path, body string) (int, model.ErrorResponse) {
+		req := httptest.NewRequest("POST", path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		res, err := s.app.Test(req, -1)
+		s.Require().NoError(err)
+		var out model.ErrorResponse
+		s.Require().NoError(json.NewDecoder(res.Body).Decode(&out))
+		return res.StatusCode, out
+	}
+
+	// Unknown fields and wrong types are refused before the handler, naming each offender.
+	status, res := post("/v1/transfers", `{"source_account_id":9801,"destination_account_id":9801,"amount":"1","memo":"x"}`)
+	s.Require().Equal(http.StatusBadRequest, status)
+	s.Require().Len(res.Details, 1)
+	s.Require().Equal("body", res.Details[0].In)
+	s.Require().Contains(res.Details[0].Reason, "memo")
+
+	status, res = post("/v1/accounts", `{"account_id":"9802","initial_balance":10}`)
+	s.Require().Equal(http.StatusBadRequest, status)
+	s.Require().Equal("invalid request", res.Error)
+	s.Require().ElementsMatch([]string{"account_id", "initial_balance"}, []string{res.Details[0].Field, res.Details[1].Field})
+
+	status, res = post("/v1/accounts", `{"initial_balance":"1"}`)
+	s.Require().Equal(http.StatusBadRequest, status)
+	s.Require().NotEmpty(res.Details)
+
+	// Amounts are plain non-negative decimals.
+	for _, amount := range []string{"-5", "abc", "1e3", "1.23456", "12345678901234567"} {
+		status, res = post("/v1/transfers", `{"source_account_id":9801,"destination_account_id":9802,"amount":"`+amount+`"}`)
+		s.Require().Equal(http.StatusBadRequest, status, amount)
+		s.Require().Equal("amount", res.Details[0].Field, amount)
+	}
+	status, res = post("/v1/accounts", `{"account_id":9802,"initial_balance":"-1"}`)
+	s.Require().Equal(http.StatusBadRequest, status)
+	s.Require().Equal("initial_balance", res.Details[0].Field)
+
+	// Parameters are checked too.
+	var bad model.ErrorResponse
+	s.Require().Equal(http.StatusBadRequest, s.doJSON("GET", "/v1/accounts/9801/changes?limit=lots", nil, &bad))
+	s.Require().Equal(model.ErrorDetail{In: "query", Field: "limit", Reason: bad.Details[0].Reason}, bad.Details[0])
+	s.Require().Equal(http.StatusBadRequest, s.doJSON("GET", "/v1/accounts/abc", nil, &bad))
+	s.Require().Equal("id", bad.Details[0].Field)
+
+	huge := `{"account_id":9803,"initial_balance":"1","owner":"` + string(bytes.Repeat([]byte("a"), middleware.MaxBodyBytes)) + `"}`
+	status, res = post("/v1/accounts", huge)
+	s.Require().Equal(http.StatusRequestEntityTooLarge, status)
+	s.Require().Equal("request body too large", res.Error)
+
+	// Nothing reached the service.
+	s.Require().Equal(http.StatusNotFound, s.doJSON("GET", "/v1/accounts/9802", nil, nil))
+	s.Require().Equal(http.StatusNotFound, s.doJSON("GET", "/v1/accounts/9803", nil, nil))
+}
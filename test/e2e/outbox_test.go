@@ -0,0 +1,114 @@
+package e2e_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"txn-processor/config"
+	"txn-processor/internal/adapter/outbound/gorm/dao"
+	"txn-processor/internal/core/model"
+	"txn-processor/internal/core/service"
+	"txn-processor/internal/port"
+	"txn-processor/pkg/tracing"
+)
+
+// leaseAs takes the relay lease under a fixed owner, so the test can hand it over.
+type leaseAs struct {
+	port.OutboxDao
+	owner string
+}
+
+func (l leaseAs) AcquireRelayLease(ctx context.Context, _ string, ttl time.Duration) (bool, error) {
+	return l.OutboxDao.AcquireRelayLease(ctx, l.owner, ttl)
+}
+
+// flakyPublisher records every batch it is given and fails the first fail of them.
+type flakyPublisher struct {
+	fail    int
+	batches [][]model.Event
+}
+
+func (p *flakyPublisher) Publish(_ context.Context, events []model.Event) error {
+	p.batches = append(p.batches, events)
+	if p.fail > 0 {
+		p.fail--
+		return errors.New("broker unavailable")
+	}
+	return nil
+}
+
+func (s *E2eSuite) TestOutboxRelay() {
+	const leaseKey = "outbox:relay:lease"
+	conn, err := dao.GetConnections()
+	s.Require().NoError(err)
+	rdb := conn.Redis()
+	ctx := s.ctx
+
+	// Take the lease from the suite's relay, as if it had expired and another replica won it.
+	s.Require().NoError(rdb.Set(ctx, leaseKey, "e2e-relay", 5*time.Second).Err())
+	defer rdb.Del(ctx, leaseKey)
+	// Let a batch the suite's relay started before the takeover finish.
+	time.Sleep(100 * time.Millisecond)
+
+	ok, err := s.outbound.AcquireRelayLease(ctx, "e2e-other", time.Minute)
+	s.Require().NoError(err)
+	s.Require().False(ok)
+
+	leased := leaseAs{OutboxDao: s.outbound, owner: "e2e-relay"}
+	ok, err = leased.AcquireRelayLease(ctx, "", time.Minute)
+	s.Require().NoError(err)
+	s.Require().True(ok)
+	ttl, err := rdb.PTTL(ctx, leaseKey).Result()
+	s.Require().NoError(err)
+	s.Require().Greater(ttl, 5*time.Second)
+
+	for _, acc := range []model.AccountCreateRequest{{AccountID: 9961, InitialBalance: "100"}, {AccountID: 9962, InitialBalance: "0"}} {
+		s.Require().Equal(http.StatusCreated, s.doJSON("POST", "/v1/accounts", acc, nil))
+	}
+	for _, amount := range []string{"1", "2", "3"} {
+		tr := model.TransferRequest{SourceAccountID: 9961, DestinationAccountID: 9962, Amount: amount}
+		s.Require().Equal(http.StatusCreated, s.doJSON("POST", "/v1/transfers", tr, nil))
+	}
+
+	pub := &flakyPublisher{fail: 1}
+	relay := service.NewOutboxRelay(leased, pub, tracing.NewBlankTracer(), config.Outbox{BatchSize: 100})
+
+	// A failed publish leaves the batch unsent, so the next drain publishes it again.
+	s.Require().Error(relay.Drain(ctx))
+	s.Require().NoError(relay.Drain(ctx))
+	s.Require().Len(pub.batches, 2)
+	s.Require().Equal(pub.batches[0], pub.batches[1])
+
+	// Each account gets its own event for every transfer, sent or received, in order.
+	for _, id := range []int64{9961, 9962} {
+		var types, amounts []string
+		var last int64
+		for _, e := range pub.batches[1] {
+			if e.AccountID != id {
+				continue
+			}
+			s.Require().Greater(e.ID, last)
+			last = e.ID
+			types = append(types, e.Type)
+			if e.Type == model.EventTransferCompleted {
+				var tr model.TransferResponse
+				s.Require().NoError(json.Unmarshal(e.Payload, &tr))
+				amounts = append(amounts, tr.Amount)
+			}
+		}
+		s.Require().Equal([]string{
+			model.EventAccountCreated,
+			model.EventTransferCompleted,
+			model.EventTransferCompleted,
+			model.EventTransferCompleted,
+		}, types, id)
+		s.Require().Equal([]string{"1", "2", "3"}, amounts, id)
+	}
+
+	// Once marked sent, nothing is published again.
+	s.Require().NoError(relay.Drain(ctx))
+	s.Require().Len(pub.batches, 2)
+}
@@ -0,0 +1,125 @@
+package e2e_test
+
+import (
+	"encoding/json"
+	"time"
+
+	"txn-processor/config"
+	"txn-processor/internal/adapter/inbound/queue"
+	"txn-processor/internal/adapter/outbound/gorm/dao"
+	"txn-processor/internal/core/model"
+	"txn-processor/pkg/auth"
+	"txn-processor/pkg/stream"
+
+	"github.com/go-redis/redis/v8"
+)
+
+func (s *E2eSuite) TestCommandQueue() {
+	_, svc, _ := s.authApp()
+	defer func() { _ = svc.Shutdown(s.ctx) }()
+
+	conn, err := dao.GetConnections()
+	s.Require().NoError(err)
+	rdb := conn.Redis()
+	ctx := s.ctx
+
+	key, err := svc.IssueAPIKey(s.asAdmin(), model.APIKeyIssueRequest{Name: "e2e-queue", Scopes: []string{auth.ScopeAdmin}})
+	s.Require().NoError(err)
+	for _, id := range []int64{9941, 9942} {
+		_, err := svc.CreateAccount(s.asAdmin(), model.AccountCreateRequest{AccountID: id, InitialBalance: "100"})
+		s.Require().NoError(err)
+	}
+
+	conf := config.Commands{
+		Stream:        "e2e:commands",
+		Group:         "e2e",
+		ReplyStream:   "e2e:replies",
+		DeadStream:    "e2e:commands:dead",
+		MaxDeliveries: 2,
+		ClaimIdleSec:  1,
+	}
+	command := func(requestID string, withKey bool) *redis.XAddArgs {
+		values := map[string]interface{}{
+			queue.FieldRequestID:   requestID,
+			queue.FieldSource:      "9941",
+			queue.FieldDestination: "9942",
+			queue.FieldAmount:      "10",
+			// Ignored: replies only ever go to the configured stream.
+			"reply_to": "e2e:victim",
+		}
+		if withKey {
+			values[queue.FieldAPIKey] = key.Key
+		} else {
+			values[queue.FieldToken] = "not-a-jwt"
+		}
+		return &redis.XAddArgs{Stream: conf.Stream, Values: values}
+	}
+	s.Require().NoError(rdb.XGroupCreateMkStream(ctx, conf.Stream, conf.Group, "0").Err())
+
+	// Two commands taken by a consumer that died before acknowledging them: one is claimed
+	// once it is idle, the other has been delivered too often already and is parked.
+	ghost := func(consumer string) {
+		s.Require().NoError(rdb.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group: conf.Group, Consumer: consumer, Streams: []string{conf.Stream, ">"}, Count: 1,
+		}).Err())
+	}
+	s.Require().NoError(rdb.XAdd(ctx, command("q-idle", true)).Err())
+	ghost("ghost-1")
+	poison, err := rdb.XAdd(ctx, command("q-poison", true)).Result()
+	s.Require().NoError(err)
+	ghost("ghost-1")
+	s.Require().NoError(rdb.XClaim(ctx, &redis.XClaimArgs{
+		Stream: conf.Stream, Group: conf.Group, Consumer: "ghost-2", Messages: []string{poison},
+	}).Err())
+
+	consumer := queue.NewTransferConsumer(rdb, svc, svc, conf)
+	go func() { _ = consumer.Listen() }()
+	defer func() { _ = consumer.Shutdown() }()
+
+	// A command delivered twice moves the money once; both deliveries are answered.
+	s.Require().NoError(rdb.XAdd(ctx, command("q-1", true)).Err())
+	s.Require().NoError(rdb.XAdd(ctx, command("q-1", true)).Err())
+	s.Require().NoError(rdb.XAdd(ctx, command("q-anon", false)).Err())
+
+	entries := func(name string) []redis.XMessage {
+		msgs, err := rdb.XRange(ctx, name, "-", "+").Result()
+		s.Require().NoError(err)
+		return msgs
+	}
+	s.Require().Eventually(func() bool {
+		return len(entries(conf.ReplyStream)) == 3 && len(entries(conf.DeadStream)) == 2
+	}, 15*time.Second, 50*time.Millisecond)
+
+	transfers := map[string][]int64{}
+	for _, m := range entries(conf.ReplyStream) {
+		s.Require().Equal(model.TransferCompleted, m.Values[queue.FieldStatus])
+		var tr model.TransferResponse
+		s.Require().NoError(json.Unmarshal([]byte(m.Values[queue.FieldTransfer].(string)), &tr))
+		id := m.Values[queue.FieldRequestID].(string)
+		transfers[id] = append(transfers[id], tr.TransactionID)
+	}
+	s.Require().Len(transfers["q-idle"], 1)
+	s.Require().Len(transfers["q-1"], 2)
+	s.Require().Equal(transfers["q-1"][0], transfers["q-1"][1])
+
+	reasons := map[string]string{}
+	for _, m := range entries(conf.DeadStream) {
+		reasons[m.Values[queue.FieldRequestID].(string)] = m.Values[stream.FieldDeadReason].(string)
+		// Parked commands keep no credentials.
+		s.Require().NotContains(m.Values, queue.FieldAPIKey)
+		s.Require().NotContains(m.Values, queue.FieldToken)
+	}
+	s.Require().Equal(map[string]string{"q-poison": "max deliveries exceeded", "q-anon": "unauthenticated"}, reasons)
+
+	n, err := rdb.Exists(ctx, "e2e:victim").Result()
+	s.Require().NoError(err)
+	s.Require().Zero(n)
+
+	acc, err := svc.GetAccount(s.asAdmin(), 9941)
+	s.Require().NoError(err)
+	s.Require().Equal("80", acc.Balance)
+
+	pending, err := rdb.XPending(ctx, conf.Stream, conf.Group).Result()
+	s.Require().NoError(err)
+	s.Require().Zero(pending.Count)
+}
@@ -0,0 +1,189 @@
+package e2e_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"time"
+
+	"txn-processor/config"
+	"txn-processor/internal/adapter/inbound/fiber/router"
+	grpcapi "txn-processor/internal/adapter/inbound/grpc"
+	txnv1 "txn-processor/internal/adapter/inbound/grpc/pb/txn/v1"
+	"txn-processor/internal/adapter/outbound/gorm/dao"
+	"txn-processor/internal/adapter/outbound/logger"
+	"txn-processor/internal/adapter/outbound/redispubsub"
+	"txn-processor/internal/adapter/outbound/redisratelimit"
+	"txn-processor/internal/adapter/outbound/webhook"
+	"txn-processor/internal/core/model"
+	"txn-processor/internal/core/service"
+	"txn-processor/pkg/tracing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func (s *E2eSuite) TestRateLimit() {
+	conn, err := dao.GetConnections()
+	s.Require().NoError(err)
+	broker := redispubsub.NewBroker(conn.Redis(), config.Realtime{History: 100, BufferSize: 16})
+
+	cfg := &config.App{
+		Async: config.Async{Workers: 1, QueueSize: 10},
+		RateLimit: config.RateLimit{
+			IsEnabled: true,
+			Routes:    map[string]string{"transfers": "5/1m"},
+			Account:   "2/1m",
+		},
+	}
+	svc := service.New(s.outbound, logger.NewPublisher(), webhook.NewSender(time.Second), broker, redisratelimit.NewLimiter(conn.Redis()), tracing.NewBlankTracer(), cfg)
+	defer func() { _ = svc.Shutdown(s.ctx) }()
+	app := router.New(svc, tracing.NewBlankTracer())
+
+	send := func(method, path string, in any) *http.Response {
+		var body bytes.Buffer
+		s.Require().NoError(json.NewEncoder(&body).Encode(in))
+		req := httptest.NewRequest(method, path, &body)
+		req.Header.Set("Content-Type", "application/json")
+		res, err := app.Test(req, -1)
+		s.Require().NoError(err)
+		return res
+	}
+
+	// Routes without a limit, and without a default, are not counted.
+	for _, id := range []int64{9401, 9402} {
+		res := send("POST", "/v1/accounts", model.AccountCreateRequest{AccountID: id, InitialBalance: "100"})
+		s.Require().Equal(http.StatusCreated, res.StatusCode)
+		s.Require().Empty(res.Header.Get("RateLimit-Limit"))
+	}
+
+	out := model.TransferRequest{SourceAccountID: 9401, DestinationAccountID: 9402, Amount: "1"}
+	back := model.TransferRequest{SourceAccountID: 9402, DestinationAccountID: 9401, Amount: "1"}
+
+	res := send("POST", "/v1/transfers", out)
+	s.Require().Equal(http.StatusCreated, res.StatusCode)
+	s.Require().Equal("5", res.Header.Get("RateLimit-Limit"))
+	s.Require().Equal("4", res.Header.Get("RateLimit-Remaining"))
+	s.Require().Equal(http.StatusCreated, send("POST", "/v1/transfers", out).StatusCode)
+
+	// The service refuses the third transfer out of the account with that limit's headers.
+	res = send("POST", "/v1/transfers", out)
+	s.Require().Equal(http.StatusTooManyRequests, res.StatusCode)
+	s.Require().Equal("2", res.Header.Get("RateLimit-Limit"))
+	s.Require().Equal("0", res.Header.Get("RateLimit-Remaining"))
+	retry, err := strconv.Atoi(res.Header.Get("Retry-After"))
+	s.Require().NoError(err)
+	s.Require().Positive(retry)
+
+	// Another account still has room until the caller's own limit runs out.
+	s.Require().Equal(http.StatusCreated, send("POST", "/v1/transfers", back).StatusCode)
+	s.Require().Equal(http.StatusCreated, send("POST", "/v1/transfers", back).StatusCode)
+
+	res = send("GET", "/v1/transfers/1", nil)
+	s.Require().Equal(http.StatusTooManyRequests, res.StatusCode)
+	s.Require().Equal("5", res.Header.Get("RateLimit-Limit"))
+	s.Require().NotEmpty(res.Header.Get("Retry-After"))
+}
+
+// TestRateLimitOtherAPIs checks that gRPC and GraphQL draw on the same per-account budget as
+// REST, so no API moves money unthrottled.
+func (s *E2eSuite) TestRateLimitOtherAPIs() {
+	conn, err := dao.GetConnections()
+	s.Require().NoError(err)
+	broker := redispubsub.NewBroker(conn.Redis(), config.Realtime{History: 100, BufferSize: 16})
+
+	cfg := &config.App{
+		Async: config.Async{Workers: 1, QueueSize: 10},
+		RateLimit: config.RateLimit{
+			IsEnabled: true,
+			Account:   "2/1m",
+		},
+	}
+	svc := service.New(s.outbound, logger.NewPublisher(), webhook.NewSender(time.Second), broker, redisratelimit.NewLimiter(conn.Redis()), tracing.NewBlankTracer(), cfg)
+	defer func() { _ = svc.Shutdown(s.ctx) }()
+	app := router.New(svc, tracing.NewBlankTracer())
+	transfers := txnv1.NewTransferServiceClient(s.grpcClient(svc))
+
+	for _, id := range []int64{9403, 9404} {
+		_, err := svc.CreateAccount(s.ctx, model.AccountCreateRequest{AccountID: id, InitialBalance: "100"})
+		s.Require().NoError(err)
+	}
+
+	move := &txnv1.CreateTransferRequest{SourceAccountId: 9403, DestinationAccountId: 9404, Amount: "1"}
+	_, err = transfers.CreateTransfer(s.ctx, move)
+	s.Require().NoError(err)
+	_, err = transfers.SubmitTransfer(s.ctx, move)
+	s.Require().NoError(err)
+
+	var header metadata.MD
+	_, err = transfers.CreateTransfer(s.ctx, move, grpc.Header(&header))
+	s.Require().Equal(codes.ResourceExhausted, status.Code(err))
+	s.Require().NotEmpty(header.Get(grpcapi.RetryAfterKey))
+
+	_, errs := s.graphql(app, nil, `mutation {
+  createTransfer(input: {sourceAccountId: 9403, destinationAccountId: "9404", amount: "1"}) { id }
+}`, nil, nil)
+	s.Require().Len(errs, 1)
+	s.Require().Equal("RATE_LIMITED", errs[0].Extensions["code"])
+
+	// The other account has its own budget.
+	_, err = transfers.CreateTransfer(s.ctx, &txnv1.CreateTransferRequest{SourceAccountId: 9404, DestinationAccountId: 9403, Amount: "1"})
+	s.Require().NoError(err)
+
+	// Both refused transfers left the balance alone; the queued one settles in the background.
+	s.Require().Eventually(func() bool {
+		acc, err := svc.GetAccount(s.ctx, 9403)
+		return err == nil && acc.Balance == "99"
+	}, 5*time.Second, 50*time.Millisecond)
+}
+
+// TestRateLimitAuthenticated checks that only callers allowed to send from an account use up
+// its budget, and that failed authentications are limited by client IP.
+func (s *E2eSuite) TestRateLimitAuthenticated() {
+	conn, err := dao.GetConnections()
+	s.Require().NoError(err)
+	broker := redispubsub.NewBroker(conn.Redis(), config.Realtime{History: 100, BufferSize: 16})
+
+	cfg := &config.App{
+		Async: config.Async{Workers: 1, QueueSize: 10},
+		Auth:  config.Auth{IsEnabled: true},
+		RateLimit: config.RateLimit{
+			IsEnabled: true,
+			Account:   "2/1m",
+			Auth:      "3/1m",
+		},
+	}
+	svc := service.New(s.outbound, logger.NewPublisher(), webhook.NewSender(time.Second), broker, redisratelimit.NewLimiter(conn.Redis()), tracing.NewBlankTracer(), cfg)
+	defer func() { _ = svc.Shutdown(s.ctx) }()
+	app := router.New(svc, tracing.NewBlankTracer())
+
+	key := func(name string) map[string]string {
+		issued, err := svc.IssueAPIKey(s.asAdmin(), model.APIKeyIssueRequest{Name: name})
+		s.Require().NoError(err)
+		return map[string]string{"X-API-Key": issued.Key}
+	}
+	owner, mallory := key("e2e-limit-owner"), key("e2e-limit-mallory")
+	s.Require().Equal(http.StatusCreated, s.call(app, "POST", "/v1/accounts", owner, model.AccountCreateRequest{AccountID: 9405, InitialBalance: "100"}, nil))
+	s.Require().Equal(http.StatusCreated, s.call(app, "POST", "/v1/accounts", owner, model.AccountCreateRequest{AccountID: 9406, InitialBalance: "0"}, nil))
+
+	// Transfers someone else is not allowed to send leave the account's budget alone.
+	move := model.TransferRequest{SourceAccountID: 9405, DestinationAccountID: 9406, Amount: "1"}
+	for range 3 {
+		s.Require().Equal(http.StatusForbidden, s.call(app, "POST", "/v1/transfers", mallory, move, nil))
+	}
+	s.Require().Equal(http.StatusCreated, s.call(app, "POST", "/v1/transfers", owner, move, nil))
+	s.Require().Equal(http.StatusCreated, s.call(app, "POST", "/v1/transfers", owner, move, nil))
+	s.Require().Equal(http.StatusTooManyRequests, s.call(app, "POST", "/v1/transfers", owner, move, nil))
+
+	// After three failed attempts the IP is refused before any credentials are checked.
+	wrong := map[string]string{"X-API-Key": service.APIKeyPrefix + "not-a-key"}
+	for range 3 {
+		s.Require().Equal(http.StatusUnauthorized, s.call(app, "GET", "/v1/accounts/9405", wrong, nil, nil))
+	}
+	s.Require().Equal(http.StatusTooManyRequests, s.call(app, "GET", "/v1/accounts/9405", wrong, nil, nil))
+	s.Require().Equal(http.StatusTooManyRequests, s.call(app, "GET", "/v1/accounts/9405", owner, nil, nil))
+}
@@ -0,0 +1,62 @@
+package e2e_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"txn-processor/internal/core/model"
+	"txn-processor/pkg/receipt"
+)
+
+// Receipts are signed with r2; r1 is the key it replaced and stays published.
+const (
+	receiptSeedOld = "AQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQE="
+	receiptSeed    = "AgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgI="
+)
+
+func (s *E2eSuite) TestTransferReceipts() {
+	s.Require().Equal(http.StatusCreated, s.doJSON("POST", "/v1/accounts", model.AccountCreateRequest{AccountID: 9101, InitialBalance: "100"}, nil))
+	s.Require().Equal(http.StatusCreated, s.doJSON("POST", "/v1/accounts", model.AccountCreateRequest{AccountID: 9102, InitialBalance: "0"}, nil))
+
+	var keys receipt.KeySet
+	s.Require().Equal(http.StatusOK, s.doJSON("GET", "/.well-known/jwks.json", nil, &keys))
+	s.Require().Len(keys.Keys, 2)
+	s.Require().Equal("r1", keys.Keys[0].Kid)
+	s.Require().Equal("r2", keys.Keys[1].Kid)
+
+	// A client decodes the response body into receipt.Transfer and checks the receipt with it.
+	var raw json.RawMessage
+	s.Require().Equal(http.StatusCreated, s.doJSON("POST", "/v1/transfers", model.TransferRequest{
+		RequestID:            "receipt-1",
+		SourceAccountID:      9101,
+		DestinationAccountID: 9102,
+		Amount:               "12.5",
+	}, &raw))
+
+	var created model.TransferResponse
+	var signed receipt.Transfer
+	s.Require().NoError(json.Unmarshal(raw, &created))
+	s.Require().NoError(json.Unmarshal(raw, &signed))
+	s.Require().NotEmpty(created.Receipt)
+	s.Require().NoError(keys.Verify(created.Receipt, signed))
+
+	tampered := signed
+	tampered.Amount = "125"
+	s.Require().True(errors.Is(keys.Verify(created.Receipt, tampered), receipt.ErrBadSignature))
+	s.Require().True(errors.Is(receipt.KeySet{Keys: keys.Keys[:1]}.Verify(created.Receipt, signed), receipt.ErrUnknownKey))
+
+	// Reading the transfer back returns a receipt that verifies against the stored fields.
+	var status model.TransferStatusResponse
+	s.Require().Equal(http.StatusOK, s.doJSON("GET", "/v1/transfers/"+strconv.FormatInt(created.TransactionID, 10), nil, &status))
+	s.Require().NotNil(status.Transfer)
+	tr := status.Transfer
+	s.Require().NoError(keys.Verify(tr.Receipt, receipt.Transfer{
+		TransactionID:        tr.TransactionID,
+		RequestID:            tr.RequestID,
+		SourceAccountID:      tr.SourceAccountID,
+		DestinationAccountID: tr.DestinationAccountID,
+		Amount:               tr.Amount,
+		CreatedAt:            tr.CreatedAt,
+	}))
+}
@@ -0,0 +1,112 @@
+package e2e_test
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"txn-processor/config"
+	"txn-processor/internal/adapter/outbound/gorm/dao"
+	"txn-processor/internal/core/model"
+	"txn-processor/internal/core/service"
+)
+
+func (s *E2eSuite) TestShardedDebit() {
+	for _, acc := range []model.AccountCreateRequest{{AccountID: 9971, InitialBalance: "100"}, {AccountID: 9972, InitialBalance: "0"}} {
+		s.Require().Equal(http.StatusCreated, s.doJSON("POST", "/v1/accounts", acc, nil))
+	}
+	s.Require().Equal(http.StatusOK, s.doJSON("PUT", "/v1/admin/accounts/9971/shards", model.AccountShardRequest{Shards: 4}, nil))
+
+	transfer := func(amount string) error {
+		_, err := s.inbound.ProcessTransfer(s.asAdmin(), model.TransferRequest{
+			SourceAccountID:      9971,
+			DestinationAccountID: 9972,
+			Amount:               amount,
+		})
+		return err
+	}
+
+	// No shard holds more than 25, so this drains several of them.
+	s.Require().NoError(transfer("60"))
+
+	// Debits racing for what is left keep hitting short shards and drain at the same time.
+	errs := make([]error, 8)
+	var wg sync.WaitGroup
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = transfer("5")
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		s.Require().NoError(err)
+	}
+
+	s.Require().ErrorIs(transfer("0.0001"), service.ErrInsufficientFunds)
+
+	var acc model.AccountGetResponse
+	s.Require().Equal(http.StatusOK, s.doJSON("GET", "/v1/accounts/9971", nil, &acc))
+	s.Require().Equal("0", acc.Balance)
+	s.Require().Equal(http.StatusOK, s.doJSON("GET", "/v1/accounts/9972", nil, &acc))
+	s.Require().Equal("100", acc.Balance)
+}
+
+func (s *E2eSuite) TestReshardBeforeTransferCommits() {
+	for _, acc := range []model.AccountCreateRequest{{AccountID: 9973, InitialBalance: "100"}, {AccountID: 9974, InitialBalance: "0"}} {
+		s.Require().Equal(http.StatusCreated, s.doJSON("POST", "/v1/accounts", acc, nil))
+	}
+
+	conn, err := dao.GetConnections()
+	s.Require().NoError(err)
+	transfers, err := dao.NewTransferDAO(conn, config.DB{GroupCommit: config.GroupCommit{IsEnabled: true, WindowMs: 300, MaxBatch: 10}})
+	s.Require().NoError(err)
+	defer s.shutdown(transfers)
+
+	// The transfer is queued while both accounts are plain; the destination is sharded before
+	// its batch locks the rows, so it has to run as a sharded transfer.
+	done := make(chan error, 1)
+	go func() {
+		_, err := transfers.RunTransferTx(s.asAdmin(), model.TransferRequest{SourceAccountID: 9973, DestinationAccountID: 9974, Amount: "10"})
+		done <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+	s.Require().Equal(http.StatusOK, s.doJSON("PUT", "/v1/admin/accounts/9974/shards", model.AccountShardRequest{Shards: 2}, nil))
+	s.Require().NoError(<-done)
+
+	var acc model.AccountGetResponse
+	s.Require().Equal(http.StatusOK, s.doJSON("GET", "/v1/accounts/9974", nil, &acc))
+	s.Require().Equal("10", acc.Balance)
+
+	// The credit landed on a shard and is numbered by the sequencer, so the feed has no gaps.
+	var page model.AccountChangesResponse
+	s.Require().Eventually(func() bool {
+		s.doJSON("GET", "/v1/accounts/9974/changes", nil, &page)
+		return len(page.Changes) == 3 && page.Changes[2].Version == 3
+	}, 5*time.Second, 50*time.Millisecond)
+	for i, c := range page.Changes {
+		s.Require().Equal(int64(i+1), c.Version)
+	}
+}
+
+func (s *E2eSuite) TestGetHotAccounts() {
+	for _, acc := range []model.AccountCreateRequest{
+		{AccountID: 9975, InitialBalance: "30"},
+		{AccountID: 9976, InitialBalance: "40"},
+		{AccountID: 9977, InitialBalance: "50"},
+	} {
+		s.Require().Equal(http.StatusCreated, s.doJSON("POST", "/v1/accounts", acc, nil))
+	}
+	s.Require().Equal(http.StatusOK, s.doJSON("PUT", "/v1/admin/accounts/9975/shards", model.AccountShardRequest{Shards: 3}, nil))
+	s.Require().Equal(http.StatusOK, s.doJSON("PUT", "/v1/admin/accounts/9976/shards", model.AccountShardRequest{Shards: 2}, nil))
+
+	// Each hot account adds its own shards, read together with the rows.
+	accounts, err := s.inbound.GetAccounts(s.asAdmin(), []int64{9975, 9976, 9977})
+	s.Require().NoError(err)
+	balances := map[int64]string{}
+	for _, acc := range accounts {
+		balances[acc.AccountID] = acc.Balance
+	}
+	s.Require().Equal(map[int64]string{9975: "30", 9976: "40", 9977: "50"}, balances)
+}
@@ -0,0 +1,99 @@
+package e2e_test
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+
+	"txn-processor/config"
+	"txn-processor/internal/adapter/outbound/gorm/dao"
+	"txn-processor/internal/core/model"
+)
+
+func (s *E2eSuite) TestLockStrategies() {
+	conn, err := dao.GetConnections()
+	s.Require().NoError(err)
+	balance := func(id int64) string {
+		var b string
+		s.Require().NoError(conn.DB().WithContext(s.ctx).Raw("SELECT CAST(balance AS CHAR) FROM accounts WHERE account_id = ?", id).Scan(&b).Error)
+		return b
+	}
+
+	for i, strategy := range []string{dao.StrategyPessimistic, dao.StrategyOptimistic, dao.StrategyConditional} {
+		a, b := int64(9984+2*i), int64(9985+2*i)
+		for _, id := range []int64{a, b} {
+			acc := model.AccountCreateRequest{AccountID: id, InitialBalance: "100"}
+			s.Require().Equal(http.StatusCreated, s.doJSON("POST", "/v1/accounts", acc, nil), strategy)
+		}
+
+		transfers, err := dao.NewTransferDAO(conn, config.DB{LockStrategy: strategy, OptimisticRetries: 100})
+		s.Require().NoError(err)
+		defer s.shutdown(transfers)
+
+		// Concurrent transfers from one account must neither lose an update nor overdraw it.
+		errs := make([]error, 20)
+		var wg sync.WaitGroup
+		for n := range errs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, errs[n] = transfers.RunTransferTx(s.asAdmin(), model.TransferRequest{SourceAccountID: a, DestinationAccountID: b, Amount: "5"})
+			}()
+		}
+		wg.Wait()
+		for _, err := range errs {
+			s.Require().NoError(err, strategy)
+		}
+
+		_, err = transfers.RunTransferTx(s.asAdmin(), model.TransferRequest{SourceAccountID: a, DestinationAccountID: b, Amount: "0.0001"})
+		s.Require().ErrorIs(err, dao.ErrInsufficientBalance, strategy)
+
+		s.Require().Equal("0.0000", balance(a), strategy)
+		s.Require().Equal("200.0000", balance(b), strategy)
+	}
+
+	_, err = dao.NewTransferDAO(conn, config.DB{LockStrategy: "magic"})
+	s.Require().Error(err)
+}
+
+func (s *E2eSuite) TestOppositeTransfers() {
+	conn, err := dao.GetConnections()
+	s.Require().NoError(err)
+
+	for i, strategy := range []string{dao.StrategyPessimistic, dao.StrategyOptimistic, dao.StrategyConditional} {
+		a, b := int64(9991+2*i), int64(9992+2*i)
+		for _, id := range []int64{a, b} {
+			acc := model.AccountCreateRequest{AccountID: id, InitialBalance: "100"}
+			s.Require().Equal(http.StatusCreated, s.doJSON("POST", "/v1/accounts", acc, nil), strategy)
+		}
+
+		transfers, err := dao.NewTransferDAO(conn, config.DB{LockStrategy: strategy, OptimisticRetries: 100})
+		s.Require().NoError(err)
+		defer s.shutdown(transfers)
+
+		// Transfers in both directions at once must all go through without deadlocking.
+		errs := make([]error, 20)
+		var wg sync.WaitGroup
+		for n := range errs {
+			req := model.TransferRequest{SourceAccountID: a, DestinationAccountID: b, Amount: "1"}
+			if n%2 == 1 {
+				req.SourceAccountID, req.DestinationAccountID = b, a
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, errs[n] = transfers.RunTransferTx(s.asAdmin(), req)
+			}()
+		}
+		wg.Wait()
+		for _, err := range errs {
+			s.Require().NoError(err, strategy)
+		}
+
+		for _, id := range []int64{a, b} {
+			var acc model.AccountGetResponse
+			s.Require().Equal(http.StatusOK, s.doJSON("GET", "/v1/accounts/"+strconv.FormatInt(id, 10), nil, &acc), strategy)
+			s.Require().Equal("100", acc.Balance, strategy)
+		}
+	}
+}
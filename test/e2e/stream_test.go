@@ -0,0 +1,58 @@
+package e2e_test
+
+import (
+	"context"
+	"time"
+
+	"txn-processor/internal/core/model"
+)
+
+func (s *E2eSuite) nextUpdate(ch <-chan model.BalanceUpdate) model.BalanceUpdate {
+	select {
+	case u, ok := <-ch:
+		s.Require().True(ok, "stream closed")
+		return u
+	case <-time.After(10 * time.Second):
+		s.FailNow("no balance update")
+		return model.BalanceUpdate{}
+	}
+}
+
+func (s *E2eSuite) TestBalanceStream() {
+	ctx, cancel := context.WithCancel(s.ctx)
+	defer cancel()
+
+	s.Require().Equal(201, s.doJSON("POST", "/v1/accounts", model.AccountCreateRequest{AccountID: 5005, InitialBalance: "100"}, nil))
+	s.Require().Equal(201, s.doJSON("POST", "/v1/accounts", model.AccountCreateRequest{AccountID: 5006, InitialBalance: "1"}, nil))
+
+	updates, err := s.inbound.StreamBalance(ctx, 5005, 0)
+	s.Require().NoError(err)
+
+	snapshot := s.nextUpdate(updates)
+	s.Require().Equal("100", snapshot.Balance)
+
+	transfer := model.TransferRequest{SourceAccountID: 5005, DestinationAccountID: 5006, Amount: "40"}
+	s.Require().Equal(201, s.doJSON("POST", "/v1/transfers", transfer, nil))
+
+	// The AccountCreated update may still arrive first; sequence numbers only go up.
+	last := snapshot
+	for last.Balance != "60" {
+		u := s.nextUpdate(updates)
+		s.Require().Equal(last.Seq+1, u.Seq)
+		last = u
+	}
+
+	// Resuming after the snapshot replays everything since, ending at the current balance.
+	resumed, err := s.inbound.StreamBalance(ctx, 5005, snapshot.Seq)
+	s.Require().NoError(err)
+
+	var u model.BalanceUpdate
+	for u.Seq < last.Seq {
+		u = s.nextUpdate(resumed)
+		s.Require().Greater(u.Seq, snapshot.Seq)
+	}
+	s.Require().Equal(last, u)
+
+	_, err = s.inbound.StreamBalance(ctx, 999999, 0)
+	s.Require().Error(err)
+}
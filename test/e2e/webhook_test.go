@@ -0,0 +1,129 @@
+package e2e_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"txn-processor/internal/core/model"
+	"txn-processor/pkg/webhook"
+)
+
+// hookReceiver is a partner endpoint that records every delivery and answers with status.
+type hookReceiver struct {
+	*httptest.Server
+	status atomic.Int32
+
+	mu       sync.Mutex
+	requests []hookRequest
+}
+
+type hookRequest struct {
+	header http.Header
+	body   []byte
+}
+
+func newHookReceiver() *hookReceiver {
+	r := &hookReceiver{}
+	r.status.Store(http.StatusOK)
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		r.mu.Lock()
+		r.requests = append(r.requests, hookRequest{header: req.Header.Clone(), body: body})
+		r.mu.Unlock()
+		w.WriteHeader(int(r.status.Load()))
+	}))
+	return r
+}
+
+func (r *hookReceiver) received() []hookRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]hookRequest(nil), r.requests...)
+}
+
+func (s *E2eSuite) TestWebhooks() {
+	start := time.Now().Add(-time.Minute)
+
+	recv := newHookReceiver()
+	defer recv.Close()
+
+	// Subscribe to transfers touching 3004; it only ever appears as the destination.
+	accountID := int64(3004)
+	var hook model.WebhookResponse
+	code := s.doJSON("POST", "/v1/webhooks", model.WebhookCreateRequest{
+		URL:        recv.URL,
+		EventTypes: []string{model.EventTransferCompleted},
+		AccountID:  &accountID,
+	}, &hook)
+	s.Require().Equal(201, code)
+	s.Require().NotEmpty(hook.Secret)
+	s.Require().True(hook.Enabled)
+
+	s.Require().Equal(201, s.doJSON("POST", "/v1/accounts", model.AccountCreateRequest{AccountID: 3003, InitialBalance: "100"}, nil))
+	s.Require().Equal(201, s.doJSON("POST", "/v1/accounts", model.AccountCreateRequest{AccountID: 3004, InitialBalance: "10"}, nil))
+
+	transfer := model.TransferRequest{SourceAccountID: 3003, DestinationAccountID: 3004, Amount: "25"}
+	s.Require().Equal(201, s.doJSON("POST", "/v1/transfers", transfer, nil))
+
+	// Delivered once, signed with the subscription secret.
+	s.Require().Eventually(func() bool { return len(recv.received()) == 1 }, 10*time.Second, 20*time.Millisecond)
+
+	first := recv.received()[0]
+	s.Require().NoError(webhook.Verify(hook.Secret, first.header.Get(webhook.HeaderSignature), first.body, time.Minute))
+	s.Require().Equal(model.EventTransferCompleted, first.header.Get(webhook.HeaderEventType))
+
+	var payload model.WebhookPayload
+	s.Require().NoError(json.Unmarshal(first.body, &payload))
+	var tr model.TransferResponse
+	s.Require().NoError(json.Unmarshal(payload.Data, &tr))
+	s.Require().Equal(int64(3004), tr.DestinationAccountID)
+	s.Require().Equal("25", tr.Amount)
+
+	var deliveries []model.WebhookDelivery
+	s.Require().Equal(200, s.doJSON("GET", "/v1/webhooks/"+strconv.FormatInt(hook.ID, 10)+"/deliveries", nil, &deliveries))
+	s.Require().Len(deliveries, 1)
+	s.Require().Equal(model.DeliverySucceeded, deliveries[0].Status)
+
+	// A failing endpoint is retried with backoff and disabled after DisableAfter attempts.
+	recv.status.Store(http.StatusInternalServerError)
+	s.Require().Equal(201, s.doJSON("POST", "/v1/transfers", transfer, nil))
+
+	s.Require().Eventually(func() bool {
+		var got model.WebhookResponse
+		s.doJSON("GET", "/v1/webhooks/"+strconv.FormatInt(hook.ID, 10), nil, &got)
+		return !got.Enabled && got.DisabledAt != nil
+	}, 10*time.Second, 20*time.Millisecond)
+
+	failed := len(recv.received())
+	s.Require().Equal(1+3, failed)
+
+	// Re-enabling and replaying re-sends both transfers, including the one already delivered.
+	recv.status.Store(http.StatusOK)
+
+	var enabled model.WebhookResponse
+	s.Require().Equal(200, s.doJSON("POST", "/v1/webhooks/"+strconv.FormatInt(hook.ID, 10)+"/enable", nil, &enabled))
+	s.Require().True(enabled.Enabled)
+	s.Require().Zero(enabled.Failures)
+
+	var replay model.WebhookReplayResponse
+	s.Require().Equal(202, s.doJSON("POST", "/v1/webhooks/"+strconv.FormatInt(hook.ID, 10)+"/replay", model.WebhookReplayRequest{Since: start}, &replay))
+	s.Require().Equal(2, replay.Queued)
+
+	s.Require().Eventually(func() bool {
+		var got []model.WebhookDelivery
+		s.doJSON("GET", "/v1/webhooks/"+strconv.FormatInt(hook.ID, 10)+"/deliveries", nil, &got)
+		for _, d := range got {
+			if d.Status != model.DeliverySucceeded {
+				return false
+			}
+		}
+		return len(got) == 2
+	}, 10*time.Second, 20*time.Millisecond)
+	s.Require().GreaterOrEqual(len(recv.received()), failed+2)
+}